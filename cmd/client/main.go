@@ -4,10 +4,17 @@ import (
 	"context"
 	"io"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
 
 	clients "ydx-goadv-gophkeeper/internal/client"
+	"ydx-goadv-gophkeeper/internal/client/agent"
 	"ydx-goadv-gophkeeper/internal/client/configs"
+	"ydx-goadv-gophkeeper/internal/client/generator"
 	"ydx-goadv-gophkeeper/internal/client/model"
+	"ydx-goadv-gophkeeper/internal/client/modes"
 	"ydx-goadv-gophkeeper/internal/client/services"
 	"ydx-goadv-gophkeeper/internal/client/terminal"
 	"ydx-goadv-gophkeeper/pkg/logger"
@@ -24,6 +31,22 @@ var (
 	defaultConfigPath = "cmd/client/config.json"
 )
 
+const (
+	agentMode  = "agent"
+	lockMode   = "lock"
+	unlockMode = "unlock"
+	statusMode = "status"
+	runMode    = "run"
+	injectMode = "inject"
+	gitMode    = "git-credential"
+	sshMode    = "ssh-agent"
+	saveMode   = "save"
+	fileMode   = "get-file"
+
+	// git looks for 'git-credential-gophkeeper' binary when helper is configured as 'gophkeeper'
+	gitHelperBinary = "git-credential-gophkeeper"
+)
+
 func main() {
 	log := logger.NewLogger("main")
 	log.Infof("Server args: %s", os.Args[1:])
@@ -48,12 +71,116 @@ func main() {
 	exitHandler.ToClose([]io.Closer{grpcConn})
 
 	authService := services.NewAuthService(pb.NewAuthClient(grpcConn), tokenHolder)
+	agentClient := agent.NewClient(appConfig.AgentSocket)
 	fileService := intsrv.NewFileService()
-	cryptoService := services.NewCryptService(appConfig.PrivateKey)
-	resourceService := services.NewResourceService(pb.NewResourcesClient(grpcConn), fileService, cryptoService)
+
+	mode, modeArgs := readMode()
+	switch mode {
+	case "":
+	case agentMode:
+		// the signal stops serving, so the socket is removed
+		agentCtx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+		defer stop()
+		agentServer := agent.NewServer(appConfig.AgentSocket, appConfig.AgentIdleTimeout, appConfig.PrivateKeyPath, authService, tokenHolder)
+		if err = modes.RunAgent(agentCtx, appConfig, agentServer); err != nil {
+			log.Fatal(err)
+		}
+		return
+	case lockMode:
+		exitOnErr(modes.RunLock(agentClient))
+		return
+	case unlockMode:
+		exitOnErr(modes.RunUnlock(appConfig, agentClient))
+		return
+	case statusMode:
+		exitOnErr(modes.RunAgentStatus(agentClient))
+		return
+	case runMode:
+		cryptoService, err := modes.AgentSession(agentClient, tokenHolder)
+		exitOnErr(err)
+		resourceService := services.NewResourceService(pb.NewResourcesClient(grpcConn), fileService, cryptoService, appConfig.Compression)
+		code, err := modes.RunWithSecrets(ctx, services.NewSecretResolver(resourceService), modeArgs)
+		exitOnErr(err)
+		os.Exit(code)
+	case injectMode:
+		cryptoService, err := modes.AgentSession(agentClient, tokenHolder)
+		exitOnErr(err)
+		resourceService := services.NewResourceService(pb.NewResourcesClient(grpcConn), fileService, cryptoService, appConfig.Compression)
+		exitOnErr(modes.Inject(ctx, services.NewSecretResolver(resourceService), resourceService, modeArgs))
+		return
+	case gitMode:
+		cryptoService, err := modes.AgentSession(agentClient, tokenHolder)
+		exitOnErr(err)
+		resourceService := services.NewResourceService(pb.NewResourcesClient(grpcConn), fileService, cryptoService, appConfig.Compression)
+		exitOnErr(modes.GitCredential(ctx, resourceService, modeArgs, os.Stdin, os.Stdout))
+		return
+	case sshMode:
+		sshCtx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+		defer stop()
+		cryptoService, err := modes.AgentSession(agentClient, tokenHolder)
+		exitOnErr(err)
+		resourceService := services.NewResourceService(pb.NewResourcesClient(grpcConn), fileService, cryptoService, appConfig.Compression)
+		exitOnErr(modes.RunSshAgent(sshCtx, appConfig, resourceService, modeArgs))
+		return
+	case saveMode:
+		cryptoService, err := modes.AgentSession(agentClient, tokenHolder)
+		exitOnErr(err)
+		resourceService := services.NewResourceService(pb.NewResourcesClient(grpcConn), fileService, cryptoService, appConfig.Compression)
+		exitOnErr(modes.Save(ctx, resourceService, modeArgs, os.Stdin, os.Stdout))
+		return
+	case fileMode:
+		cryptoService, err := modes.AgentSession(agentClient, tokenHolder)
+		exitOnErr(err)
+		resourceService := services.NewResourceService(pb.NewResourcesClient(grpcConn), fileService, cryptoService, appConfig.Compression)
+		exitOnErr(modes.GetFile(ctx, resourceService, modeArgs, os.Stdout))
+		return
+	default:
+		log.Fatalf("mode '%s' is not supported, available modes: %s", mode, strings.Join(supportedModes, ", "))
+	}
+
+	cryptoService, err := modes.Session(appConfig, agentClient, tokenHolder)
+	if err != nil {
+		log.Fatal(err)
+	}
+	resourceService := services.NewResourceService(pb.NewResourcesClient(grpcConn), fileService, cryptoService, appConfig.Compression)
 	exit := exitHandler.ProperExitDefer()
 
-	commandProcessor := terminal.NewCommandParser(buildVersion, buildDate, authService, resourceService, exitHandler)
+	commandProcessor := terminal.NewCommandParser(
+		buildVersion,
+		buildDate,
+		authService,
+		resourceService,
+		services.NewReportService(resourceService, appConfig.HibpFile),
+		services.NewImportService(resourceService),
+		services.NewBackupService(resourceService),
+		services.NewSearchService(resourceService),
+		services.NewLabelService(resourceService),
+		generator.NewGenerator(),
+		exitHandler,
+	)
 	commandProcessor.Start(exit)
 	<-ctx.Done()
 }
+
+// readMode returns the first positional argument and the rest args,
+// the interactive terminal is started without it
+func readMode() (string, []string) {
+	binary := strings.TrimSuffix(filepath.Base(os.Args[0]), filepath.Ext(os.Args[0]))
+	if binary == gitHelperBinary {
+		return gitMode, os.Args[1:]
+	}
+	if len(os.Args) < 2 || strings.HasPrefix(os.Args[1], "-") {
+		return "", nil
+	}
+	return os.Args[1], os.Args[2:]
+}
+
+var supportedModes = []string{agentMode, lockMode, unlockMode, statusMode, runMode, injectMode, gitMode, sshMode, saveMode, fileMode}
+
+// exitOnErr finishes the mode with non-zero code on error
+func exitOnErr(err error) {
+	if err != nil {
+		os.Stderr.WriteString("error: " + err.Error() + "\n")
+		os.Exit(1)
+	}
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"os"
+	"time"
 
 	"ydx-goadv-gophkeeper/internal/server/configs"
 	servers "ydx-goadv-gophkeeper/internal/server/grpc_servers"
@@ -49,11 +50,14 @@ func main() {
 
 	userSrv := services.NewUserService(userRepo)
 	resSrv := services.NewResourceService(resRepo)
+	quotaSrv := services.NewQuotaService(resRepo, userRepo, appConfig.Quotas)
+	blobSrv := services.NewBlobService(resRepo, appConfig.BlobsDir)
 	tokenSrv := services.NewTokenService(appConfig.TokenKey)
 	fileProcessor := intsrv.NewFileService()
 
 	authServer := servers.NewAuthServer(userSrv, tokenSrv)
-	resourcesServer := servers.NewResourcesServer(resSrv, fileProcessor, exitHandler)
+	resourcesServer := servers.NewResourcesServer(resSrv, quotaSrv, blobSrv, fileProcessor, exitHandler)
+	go blobSrv.RunGC(ctx, time.Duration(appConfig.BlobGCMinutes)*time.Minute)
 
 	serverManager, err := servers.NewServerManager(tokenSrv)
 	if err != nil {
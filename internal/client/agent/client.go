@@ -0,0 +1,101 @@
+package agent
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"ydx-goadv-gophkeeper/internal/client/services"
+)
+
+const dialTimeout = 2 * time.Second
+
+//go:generate mockgen -source=client.go -destination=../mocks/agent/client.go -package=agent
+
+// Client talks to the running agent, it can be used as CryptService
+type Client interface {
+	services.CryptService
+	Status() (*Status, error)
+	Lock() error
+	Unlock(passphrase []byte, username string, password string) error
+	Token() (string, error)
+}
+
+type agentClient struct {
+	socketPath string
+}
+
+func NewClient(socketPath string) Client {
+	return &agentClient{socketPath: socketPath}
+}
+
+func (c *agentClient) Status() (*Status, error) {
+	resp, err := c.call(&request{Op: opStatus})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Status, nil
+}
+
+func (c *agentClient) Lock() error {
+	_, err := c.call(&request{Op: opLock})
+	return err
+}
+
+func (c *agentClient) Unlock(passphrase []byte, username string, password string) error {
+	_, err := c.call(&request{Op: opUnlock, Passphrase: passphrase, Username: username, Password: password})
+	return err
+}
+
+func (c *agentClient) Token() (string, error) {
+	resp, err := c.call(&request{Op: opToken})
+	if err != nil {
+		return "", err
+	}
+	return resp.Token, nil
+}
+
+func (c *agentClient) Encrypt(data []byte) ([]byte, error) {
+	resp, err := c.call(&request{Op: opEncrypt, Data: data})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+func (c *agentClient) Decrypt(data []byte) ([]byte, error) {
+	resp, err := c.call(&request{Op: opDecrypt, Data: data})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+func (c *agentClient) BlindIndex(data []byte) ([]byte, error) {
+	resp, err := c.call(&request{Op: opIndex, Data: data})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+func (c *agentClient) call(req *request) (*response, error) {
+	conn, err := net.DialTimeout("unix", c.socketPath, dialTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("agent is not running on '%s': %v", c.socketPath, err)
+	}
+	defer conn.Close()
+	if err = json.NewEncoder(conn).Encode(req); err != nil {
+		return nil, fmt.Errorf("failed to send agent request: %v", err)
+	}
+	var resp response
+	if err = json.NewDecoder(conn).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("failed to read agent response: %v", err)
+	}
+	if resp.Error != "" {
+		return nil, errors.New(resp.Error)
+	}
+	return &resp, nil
+}
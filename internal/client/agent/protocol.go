@@ -0,0 +1,31 @@
+package agent
+
+const (
+	opStatus  = "status"
+	opLock    = "lock"
+	opUnlock  = "unlock"
+	opToken   = "token"
+	opEncrypt = "encrypt"
+	opDecrypt = "decrypt"
+	opIndex   = "index"
+)
+
+type request struct {
+	Op         string `json:"op"`
+	Data       []byte `json:"data,omitempty"`
+	Passphrase []byte `json:"passphrase,omitempty"`
+	Username   string `json:"username,omitempty"`
+	Password   string `json:"password,omitempty"`
+}
+
+type response struct {
+	Data   []byte  `json:"data,omitempty"`
+	Token  string  `json:"token,omitempty"`
+	Status *Status `json:"status,omitempty"`
+	Error  string  `json:"error,omitempty"`
+}
+
+type Status struct {
+	Locked   bool   `json:"locked"`
+	IdleLeft string `json:"idleLeft,omitempty"`
+}
@@ -0,0 +1,259 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"ydx-goadv-gophkeeper/internal/client/configs"
+	"ydx-goadv-gophkeeper/internal/client/model"
+	"ydx-goadv-gophkeeper/internal/client/services"
+	"ydx-goadv-gophkeeper/pkg/logger"
+)
+
+const idleCheckPeriod = 5 * time.Second
+
+var (
+	ErrLocked         = errors.New("agent is locked, run 'gophkeeper unlock'")
+	ErrSessionExpired = errors.New("agent session token has expired, run 'gophkeeper unlock'")
+
+	errSocketInUse = errors.New("socket is in use")
+)
+
+//go:generate mockgen -source=server.go -destination=../mocks/agent/server.go -package=agent
+
+// Server holds unlocked private key and session token in memory and serves them over unix socket
+type Server interface {
+	Unlock(ctx context.Context, passphrase []byte, username string, password string) error
+	Lock()
+	Serve(ctx context.Context) error
+}
+
+type agentServer struct {
+	log         *zap.SugaredLogger
+	socketPath  string
+	idleTimeout time.Duration
+	keyPath     string
+	authService services.AuthService
+	tokenHolder *model.TokenHolder
+
+	mu           sync.Mutex
+	cryptService services.CryptService
+	lastUsed     time.Time
+	// expireAt is the session token expiration, the agent is locked once the token is not accepted by the server
+	expireAt time.Time
+}
+
+func NewServer(
+	socketPath string,
+	idleTimeout time.Duration,
+	keyPath string,
+	authService services.AuthService,
+	tokenHolder *model.TokenHolder,
+) Server {
+	return &agentServer{
+		log:         logger.NewLogger("agent"),
+		socketPath:  socketPath,
+		idleTimeout: idleTimeout,
+		keyPath:     keyPath,
+		authService: authService,
+		tokenHolder: tokenHolder,
+	}
+}
+
+func (s *agentServer) Unlock(ctx context.Context, passphrase []byte, username string, password string) error {
+	var cryptService services.CryptService
+	if s.keyPath != "" {
+		key, err := configs.ReadRsaPrivateKey(s.keyPath, passphrase)
+		if err != nil {
+			return err
+		}
+		cryptService = services.NewCryptService(key)
+	} else {
+		cryptService = services.NewCryptService(nil)
+	}
+	tokenData, err := s.authService.Login(ctx, username, password)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cryptService = cryptService
+	s.lastUsed = time.Now()
+	s.expireAt = time.Time{}
+	if tokenData.GetExpireAt() != nil {
+		s.expireAt = tokenData.GetExpireAt().AsTime()
+	}
+	s.log.Info("Agent is unlocked")
+	return nil
+}
+
+func (s *agentServer) Lock() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lock()
+}
+
+func (s *agentServer) lock() {
+	if s.cryptService == nil {
+		return
+	}
+	s.cryptService = nil
+	s.tokenHolder.Set("")
+	s.log.Info("Agent is locked")
+}
+
+func (s *agentServer) Serve(ctx context.Context) error {
+	listener, err := s.listen()
+	if err != nil {
+		return err
+	}
+	defer os.Remove(s.socketPath)
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+	}()
+	go s.watchIdle(ctx)
+
+	s.log.Infof("Agent is listening on %s", s.socketPath)
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("failed to accept agent connection: %v", err)
+		}
+		go s.handle(ctx, conn)
+	}
+}
+
+func (s *agentServer) listen() (net.Listener, error) {
+	listener, err := listenSocket(s.socketPath)
+	if errors.Is(err, errSocketInUse) {
+		return nil, fmt.Errorf("agent is already running on %s", s.socketPath)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("agent: %w", err)
+	}
+	return listener, nil
+}
+
+func (s *agentServer) watchIdle(ctx context.Context) {
+	ticker := time.NewTicker(idleCheckPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.mu.Lock()
+			if s.cryptService != nil && time.Since(s.lastUsed) > s.idleTimeout {
+				s.log.Infof("Agent was idle for %v", s.idleTimeout)
+				s.lock()
+			}
+			if s.cryptService != nil && s.expired() {
+				s.log.Info("Agent session token has expired")
+				s.lock()
+			}
+			s.mu.Unlock()
+		}
+	}
+}
+
+func (s *agentServer) handle(ctx context.Context, conn net.Conn) {
+	defer conn.Close()
+	var req request
+	if err := json.NewDecoder(conn).Decode(&req); err != nil {
+		s.log.Errorf("failed to decode agent request: %v", err)
+		return
+	}
+	resp := s.process(ctx, &req)
+	if err := json.NewEncoder(conn).Encode(resp); err != nil {
+		s.log.Errorf("failed to send agent response: %v", err)
+	}
+}
+
+func (s *agentServer) process(ctx context.Context, req *request) *response {
+	switch req.Op {
+	case opStatus:
+		return &response{Status: s.status()}
+	case opLock:
+		s.Lock()
+		return &response{}
+	case opUnlock:
+		if err := s.Unlock(ctx, req.Passphrase, req.Username, req.Password); err != nil {
+			return &response{Error: err.Error()}
+		}
+		return &response{}
+	}
+
+	cryptService, err := s.touch()
+	if err != nil {
+		return &response{Error: err.Error()}
+	}
+	switch req.Op {
+	case opToken:
+		return &response{Token: s.tokenHolder.Get()}
+	case opEncrypt:
+		data, err := cryptService.Encrypt(req.Data)
+		if err != nil {
+			return &response{Error: err.Error()}
+		}
+		return &response{Data: data}
+	case opDecrypt:
+		data, err := cryptService.Decrypt(req.Data)
+		if err != nil {
+			return &response{Error: err.Error()}
+		}
+		return &response{Data: data}
+	case opIndex:
+		data, err := cryptService.BlindIndex(req.Data)
+		if err != nil {
+			return &response{Error: err.Error()}
+		}
+		return &response{Data: data}
+	default:
+		return &response{Error: fmt.Sprintf("operation '%s' is not supported", req.Op)}
+	}
+}
+
+// touch returns unlocked crypt service and prolongs agent idle timeout
+func (s *agentServer) touch() (services.CryptService, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cryptService != nil && s.expired() {
+		s.lock()
+		return nil, ErrSessionExpired
+	}
+	if s.cryptService == nil {
+		return nil, ErrLocked
+	}
+	s.lastUsed = time.Now()
+	return s.cryptService, nil
+}
+
+// expired reports whether the session token has expired, it is not refreshed as the agent keeps no password
+func (s *agentServer) expired() bool {
+	return !s.expireAt.IsZero() && !time.Now().Before(s.expireAt)
+}
+
+func (s *agentServer) status() *Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cryptService != nil && s.expired() {
+		s.lock()
+	}
+	if s.cryptService == nil {
+		return &Status{Locked: true}
+	}
+	idleLeft := s.idleTimeout - time.Since(s.lastUsed)
+	return &Status{IdleLeft: idleLeft.Round(time.Second).String()}
+}
@@ -0,0 +1,81 @@
+package agent
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"ydx-goadv-gophkeeper/internal/client/mocks/services"
+	"ydx-goadv-gophkeeper/internal/client/model"
+	"ydx-goadv-gophkeeper/pkg/pb"
+)
+
+func TestAgentServer_LockUnlock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	socketPath := filepath.Join(t.TempDir(), "agent", "agent.sock")
+	tokenHolder := &model.TokenHolder{}
+	authService := services.NewMockAuthService(ctrl)
+	authService.EXPECT().
+		Login(gomock.Any(), "user", "pass").
+		Do(func(ctx context.Context, username string, password string) { tokenHolder.Set("token") }).
+		Return(nil, nil)
+
+	server := NewServer(socketPath, time.Minute, "", authService, tokenHolder)
+	client := NewClient(socketPath)
+	go func() {
+		assert.NoError(t, server.Serve(ctx))
+	}()
+	assert.Eventually(t, func() bool {
+		_, err := client.Status()
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+
+	status, err := client.Status()
+	assert.NoError(t, err)
+	assert.True(t, status.Locked)
+	_, err = client.Token()
+	assert.EqualError(t, err, ErrLocked.Error())
+
+	assert.NoError(t, client.Unlock(nil, "user", "pass"))
+	token, err := client.Token()
+	assert.NoError(t, err)
+	assert.Equal(t, "token", token)
+	data, err := client.Encrypt([]byte("data"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("data"), data)
+
+	assert.NoError(t, client.Lock())
+	status, err = client.Status()
+	assert.NoError(t, err)
+	assert.True(t, status.Locked)
+	assert.Equal(t, "", tokenHolder.Get())
+}
+
+func TestAgentServer_SessionExpired(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tokenHolder := &model.TokenHolder{}
+	authService := services.NewMockAuthService(ctrl)
+	authService.EXPECT().
+		Login(gomock.Any(), "user", "pass").
+		Do(func(ctx context.Context, username string, password string) { tokenHolder.Set("token") }).
+		Return(&pb.TokenData{Token: "token", ExpireAt: timestamppb.New(time.Now().Add(-time.Second))}, nil)
+
+	server := NewServer(filepath.Join(t.TempDir(), "agent", "agent.sock"), time.Minute, "", authService, tokenHolder).(*agentServer)
+	assert.NoError(t, server.Unlock(context.Background(), nil, "user", "pass"))
+	resp := server.process(context.Background(), &request{Op: opToken})
+	assert.Equal(t, ErrSessionExpired.Error(), resp.Error)
+	assert.True(t, server.status().Locked)
+	assert.Equal(t, "", tokenHolder.Get())
+}
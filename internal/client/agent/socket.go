@@ -0,0 +1,54 @@
+//go:build unix
+
+package agent
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// listenSocket listens the unix socket in the private directory, the socket left by the stopped agent is replaced,
+// errSocketInUse is returned if the agent is running
+func listenSocket(path string) (net.Listener, error) {
+	if err := privateDir(filepath.Dir(path)); err != nil {
+		return nil, err
+	}
+	if conn, err := net.Dial("unix", path); err == nil {
+		conn.Close()
+		return nil, errSocketInUse
+	}
+	_ = os.Remove(path)
+	// the socket is created closed to other users instead of being restricted after listen
+	mask := syscall.Umask(0177)
+	listener, err := net.Listen("unix", path)
+	syscall.Umask(mask)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen socket '%s': %v", path, err)
+	}
+	return listener, nil
+}
+
+// privateDir creates the socket directory or checks that the existing one is a directory of the current user
+// closed to others, otherwise another user could plant the socket and collect requests with the master password
+func privateDir(dir string) error {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create socket dir: %v", err)
+	}
+	info, err := os.Lstat(dir)
+	if err != nil {
+		return fmt.Errorf("failed to check socket dir: %v", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("socket dir '%s' is not a directory", dir)
+	}
+	if stat, ok := info.Sys().(*syscall.Stat_t); !ok || int(stat.Uid) != os.Getuid() {
+		return fmt.Errorf("socket dir '%s' is not owned by the current user", dir)
+	}
+	if info.Mode().Perm() != 0700 {
+		return fmt.Errorf("socket dir '%s' must have 0700 permissions, has %o", dir, info.Mode().Perm())
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+//go:build unix
+
+package agent
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListenSocket_PrivateDir(t *testing.T) {
+	root := t.TempDir()
+	shared := filepath.Join(root, "shared")
+	assert.NoError(t, os.Mkdir(shared, 0700))
+	assert.NoError(t, os.Chmod(shared, 0755))
+	link := filepath.Join(root, "link")
+	assert.NoError(t, os.Symlink(t.TempDir(), link))
+
+	tests := []struct {
+		name    string
+		dir     string
+		wantErr bool
+	}{
+		{name: "created", dir: filepath.Join(root, "new")},
+		{name: "open to others", dir: shared, wantErr: true},
+		{name: "symlink", dir: link, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			listener, err := listenSocket(filepath.Join(tt.dir, "agent.sock"))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			defer listener.Close()
+			info, err := os.Stat(filepath.Join(tt.dir, "agent.sock"))
+			assert.NoError(t, err)
+			assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+		})
+	}
+}
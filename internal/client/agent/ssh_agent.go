@@ -0,0 +1,213 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+
+	"go.uber.org/zap"
+	"golang.org/x/crypto/ssh"
+	sshagent "golang.org/x/crypto/ssh/agent"
+
+	"ydx-goadv-gophkeeper/internal/client/model/resources"
+	"ydx-goadv-gophkeeper/internal/client/services"
+	"ydx-goadv-gophkeeper/pkg/logger"
+	"ydx-goadv-gophkeeper/pkg/model/enum"
+)
+
+var errReadOnlyAgent = errors.New("keys are managed by gophkeeper vault")
+
+// ConfirmFunc asks the user whether the key can be used
+type ConfirmFunc func(key *sshagent.Key) bool
+
+type vaultKey struct {
+	signer  ssh.Signer
+	comment string
+}
+
+//go:generate mockgen -source=ssh_agent.go -destination=../mocks/agent/ssh_agent.go -package=agent
+
+// SshAgent serves ssh keys of the vault with ssh-agent protocol, keys never leave the process memory
+type SshAgent interface {
+	sshagent.ExtendedAgent
+	Serve(ctx context.Context, socketPath string) error
+}
+
+type sshAgent struct {
+	log             *zap.SugaredLogger
+	resourceService services.ResourceService
+	confirm         ConfirmFunc
+
+	mu   sync.Mutex
+	keys []*vaultKey
+}
+
+func NewSshAgent(resourceService services.ResourceService, confirm ConfirmFunc) SshAgent {
+	return &sshAgent{
+		log:             logger.NewLogger("ssh-agent"),
+		resourceService: resourceService,
+		confirm:         confirm,
+	}
+}
+
+func (a *sshAgent) Serve(ctx context.Context, socketPath string) error {
+	listener, err := listenSocket(socketPath)
+	if errors.Is(err, errSocketInUse) {
+		return fmt.Errorf("ssh agent is already running on %s", socketPath)
+	}
+	if err != nil {
+		return fmt.Errorf("ssh agent: %w", err)
+	}
+	defer os.Remove(socketPath)
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+	}()
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("failed to accept ssh agent connection: %v", err)
+		}
+		go func() {
+			defer conn.Close()
+			if err := sshagent.ServeAgent(a, conn); err != nil && !errors.Is(err, net.ErrClosed) {
+				a.log.Debugf("ssh agent connection is closed: %v", err)
+			}
+		}()
+	}
+}
+
+// List reloads keys from the vault, so locked vault agent or deleted resources are respected
+func (a *sshAgent) List() ([]*sshagent.Key, error) {
+	if err := a.reload(context.Background()); err != nil {
+		a.log.Errorf("failed to load ssh keys: %v", err)
+		return nil, err
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	keys := make([]*sshagent.Key, 0, len(a.keys))
+	for _, key := range a.keys {
+		keys = append(keys, toAgentKey(key))
+	}
+	return keys, nil
+}
+
+func (a *sshAgent) Sign(key ssh.PublicKey, data []byte) (*ssh.Signature, error) {
+	return a.SignWithFlags(key, data, 0)
+}
+
+func (a *sshAgent) SignWithFlags(key ssh.PublicKey, data []byte, flags sshagent.SignatureFlags) (*ssh.Signature, error) {
+	vk := a.find(key)
+	if vk == nil {
+		return nil, errors.New("key is not found")
+	}
+	if a.confirm != nil && !a.confirm(toAgentKey(vk)) {
+		return nil, errors.New("key usage is not confirmed")
+	}
+	a.log.Infof("Signing with '%s' key", vk.comment)
+	algorithmSigner, ok := vk.signer.(ssh.AlgorithmSigner)
+	if !ok || flags == 0 {
+		return vk.signer.Sign(nil, data)
+	}
+	switch {
+	case flags&sshagent.SignatureFlagRsaSha256 != 0:
+		return algorithmSigner.SignWithAlgorithm(nil, data, ssh.KeyAlgoRSASHA256)
+	case flags&sshagent.SignatureFlagRsaSha512 != 0:
+		return algorithmSigner.SignWithAlgorithm(nil, data, ssh.KeyAlgoRSASHA512)
+	default:
+		return vk.signer.Sign(nil, data)
+	}
+}
+
+func (a *sshAgent) Signers() ([]ssh.Signer, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	signers := make([]ssh.Signer, 0, len(a.keys))
+	for _, key := range a.keys {
+		signers = append(signers, key.signer)
+	}
+	return signers, nil
+}
+
+func (a *sshAgent) Add(_ sshagent.AddedKey) error {
+	return errReadOnlyAgent
+}
+
+func (a *sshAgent) Remove(_ ssh.PublicKey) error {
+	return errReadOnlyAgent
+}
+
+func (a *sshAgent) RemoveAll() error {
+	return errReadOnlyAgent
+}
+
+func (a *sshAgent) Lock(_ []byte) error {
+	return errors.New("use 'gophkeeper lock' to lock the vault")
+}
+
+func (a *sshAgent) Unlock(_ []byte) error {
+	return errors.New("use 'gophkeeper unlock' to unlock the vault")
+}
+
+func (a *sshAgent) Extension(_ string, _ []byte) ([]byte, error) {
+	return nil, sshagent.ErrExtensionUnsupported
+}
+
+func (a *sshAgent) reload(ctx context.Context) error {
+	descriptions, err := a.resourceService.GetDescriptions(ctx, enum.SshKey)
+	if err != nil {
+		return err
+	}
+	keys := make([]*vaultKey, 0, len(descriptions))
+	for _, description := range descriptions {
+		info, err := a.resourceService.Get(ctx, description.Id)
+		if err != nil {
+			return err
+		}
+		sshKey, ok := info.Resource.(*resources.SshKey)
+		if !ok {
+			continue
+		}
+		signer, err := sshKey.Signer()
+		if err != nil {
+			a.log.Errorf("failed to load '%d' ssh key: %v", description.Id, err)
+			continue
+		}
+		comment := sshKey.Comment
+		if comment == "" {
+			comment = string(info.Meta)
+		}
+		keys = append(keys, &vaultKey{signer: signer, comment: comment})
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.keys = keys
+	return nil
+}
+
+func (a *sshAgent) find(key ssh.PublicKey) *vaultKey {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	wanted := key.Marshal()
+	for _, vk := range a.keys {
+		if bytes.Equal(vk.signer.PublicKey().Marshal(), wanted) {
+			return vk
+		}
+	}
+	return nil
+}
+
+func toAgentKey(key *vaultKey) *sshagent.Key {
+	return &sshagent.Key{
+		Format:  key.signer.PublicKey().Type(),
+		Blob:    key.signer.PublicKey().Marshal(),
+		Comment: key.comment,
+	}
+}
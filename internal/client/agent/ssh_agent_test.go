@@ -0,0 +1,84 @@
+package agent
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ssh"
+	sshagent "golang.org/x/crypto/ssh/agent"
+
+	"ydx-goadv-gophkeeper/internal/client/mocks/services"
+	"ydx-goadv-gophkeeper/internal/client/model/resources"
+	"ydx-goadv-gophkeeper/internal/server/model"
+	"ydx-goadv-gophkeeper/pkg/model/enum"
+)
+
+func TestSshAgent_Sign(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	privateKeyPem := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey)})
+	sshKey, err := resources.NewSshKey(string(privateKeyPem), "", "test key")
+	assert.NoError(t, err)
+
+	resourceService := services.NewMockResourceService(ctrl)
+	resourceService.EXPECT().
+		GetDescriptions(gomock.Any(), enum.SshKey).
+		Return([]*model.ResourceDescription{{Id: 1, Type: enum.SshKey}}, nil)
+	resourceService.EXPECT().
+		Get(gomock.Any(), int32(1)).
+		Return(&resources.Info{Resource: sshKey}, nil)
+
+	confirmed := 0
+	vaultAgent := NewSshAgent(resourceService, func(key *sshagent.Key) bool {
+		confirmed++
+		return key.Comment == "test key"
+	})
+
+	keys, err := vaultAgent.List()
+	assert.NoError(t, err)
+	assert.Len(t, keys, 1)
+	assert.Equal(t, "test key", keys[0].Comment)
+
+	data := []byte("data to sign")
+	signature, err := vaultAgent.SignWithFlags(keys[0], data, sshagent.SignatureFlagRsaSha256)
+	assert.NoError(t, err)
+	assert.Equal(t, ssh.KeyAlgoRSASHA256, signature.Format)
+	assert.NoError(t, keys[0].Verify(data, signature))
+	assert.Equal(t, 1, confirmed)
+
+	assert.Error(t, vaultAgent.Add(sshagent.AddedKey{PrivateKey: privateKey}))
+}
+
+func TestSshAgent_ServeRunning(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	socketPath := filepath.Join(t.TempDir(), "agent", "ssh.sock")
+	go func() {
+		assert.NoError(t, NewSshAgent(nil, nil).Serve(ctx, socketPath))
+	}()
+	assert.Eventually(t, func() bool {
+		conn, err := net.Dial("unix", socketPath)
+		if err == nil {
+			conn.Close()
+		}
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+
+	// the second agent does not remove the socket of the running one
+	assert.EqualError(t, NewSshAgent(nil, nil).Serve(ctx, socketPath), "ssh agent is already running on "+socketPath)
+	conn, err := net.Dial("unix", socketPath)
+	assert.NoError(t, err)
+	conn.Close()
+}
@@ -0,0 +1,220 @@
+package backup
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	magic     = "GPKV"
+	version   = 1
+	saltSize  = 16
+	keySize   = 32
+	chunkSize = 64 * 1024
+	// nonce is the random prefix, chunk counter and the last chunk flag
+	prefixSize = 7
+	headerSize = len(magic) + 4 + saltSize + prefixSize
+)
+
+var (
+	ErrWrongPassphrase = errors.New("wrong passphrase or corrupted archive")
+	ErrTruncated       = errors.New("archive is truncated")
+	ErrNotArchive      = errors.New("file is not a gophkeeper vault archive")
+)
+
+// scrypt cost is stored in the header as log2 N, r and p
+var scryptLogN, scryptR, scryptP byte = 15, 8, 1
+
+type header struct {
+	raw    []byte
+	salt   []byte
+	prefix []byte
+	logN   byte
+	r      byte
+	p      byte
+}
+
+func (h *header) aead(passphrase []byte) (cipher.AEAD, error) {
+	if h.logN > 30 {
+		return nil, ErrNotArchive
+	}
+	key, err := scrypt.Key(passphrase, h.salt, 1<<h.logN, int(h.r), int(h.p), keySize)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func (h *header) nonce(counter uint32, last bool) []byte {
+	nonce := make([]byte, 0, prefixSize+5)
+	nonce = append(nonce, h.prefix...)
+	nonce = binary.BigEndian.AppendUint32(nonce, counter)
+	if last {
+		return append(nonce, 1)
+	}
+	return append(nonce, 0)
+}
+
+// writer encrypts the stream by AES-GCM chunks, header is authenticated with every chunk
+type writer struct {
+	out     io.Writer
+	header  *header
+	aead    cipher.AEAD
+	buf     []byte
+	counter uint32
+	closed  bool
+}
+
+// NewWriter writes archive header and returns writer encrypting data with the key derived from passphrase,
+// Close must be called to write the last chunk
+func NewWriter(out io.Writer, passphrase []byte) (io.WriteCloser, error) {
+	h := &header{logN: scryptLogN, r: scryptR, p: scryptP, salt: make([]byte, saltSize), prefix: make([]byte, prefixSize)}
+	if _, err := rand.Read(h.salt); err != nil {
+		return nil, err
+	}
+	if _, err := rand.Read(h.prefix); err != nil {
+		return nil, err
+	}
+	h.raw = append([]byte(magic), version, h.logN, h.r, h.p)
+	h.raw = append(append(h.raw, h.salt...), h.prefix...)
+	aead, err := h.aead(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := out.Write(h.raw); err != nil {
+		return nil, err
+	}
+	return &writer{out: out, header: h, aead: aead, buf: make([]byte, 0, chunkSize)}, nil
+}
+
+func (w *writer) Write(p []byte) (int, error) {
+	if w.closed {
+		return 0, errors.New("write to closed archive")
+	}
+	written := 0
+	for len(p) > 0 {
+		n := copy(w.buf[len(w.buf):chunkSize], p)
+		w.buf = w.buf[:len(w.buf)+n]
+		p = p[n:]
+		written += n
+		if len(w.buf) == chunkSize {
+			if err := w.seal(false); err != nil {
+				return written, err
+			}
+		}
+	}
+	return written, nil
+}
+
+func (w *writer) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	return w.seal(true)
+}
+
+func (w *writer) seal(last bool) error {
+	sealed := w.aead.Seal(nil, w.header.nonce(w.counter, last), w.buf, w.header.raw)
+	w.counter++
+	w.buf = w.buf[:0]
+	length := binary.BigEndian.AppendUint32(nil, uint32(len(sealed)))
+	if _, err := w.out.Write(length); err != nil {
+		return err
+	}
+	_, err := w.out.Write(sealed)
+	return err
+}
+
+type reader struct {
+	in      io.Reader
+	header  *header
+	aead    cipher.AEAD
+	buf     []byte
+	counter uint32
+	last    bool
+}
+
+// NewReader reads archive header and returns reader decrypting data,
+// it fails with ErrTruncated if the last chunk is missing
+func NewReader(in io.Reader, passphrase []byte) (io.Reader, error) {
+	raw := make([]byte, headerSize)
+	if _, err := io.ReadFull(in, raw); err != nil {
+		return nil, ErrNotArchive
+	}
+	if !bytes.Equal(raw[:len(magic)], []byte(magic)) {
+		return nil, ErrNotArchive
+	}
+	if raw[len(magic)] != version {
+		return nil, fmt.Errorf("archive version %d is not supported", raw[len(magic)])
+	}
+	offset := len(magic) + 4
+	h := &header{
+		raw:    raw,
+		logN:   raw[len(magic)+1],
+		r:      raw[len(magic)+2],
+		p:      raw[len(magic)+3],
+		salt:   raw[offset : offset+saltSize],
+		prefix: raw[offset+saltSize:],
+	}
+	aead, err := h.aead(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return &reader{in: in, header: h, aead: aead}, nil
+}
+
+func (r *reader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		if r.last {
+			return 0, io.EOF
+		}
+		if err := r.open(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+func (r *reader) open() error {
+	length := make([]byte, 4)
+	if _, err := io.ReadFull(r.in, length); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return ErrTruncated
+		}
+		return err
+	}
+	size := binary.BigEndian.Uint32(length)
+	if size > chunkSize+uint32(r.aead.Overhead()) {
+		return ErrWrongPassphrase
+	}
+	sealed := make([]byte, size)
+	if _, err := io.ReadFull(r.in, sealed); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return ErrTruncated
+		}
+		return err
+	}
+	for _, last := range []bool{false, true} {
+		if plain, err := r.aead.Open(sealed[:0:0], r.header.nonce(r.counter, last), sealed, r.header.raw); err == nil {
+			r.buf, r.last = plain, last
+			r.counter++
+			return nil
+		}
+	}
+	return ErrWrongPassphrase
+}
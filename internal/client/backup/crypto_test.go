@@ -0,0 +1,58 @@
+package backup
+
+import (
+	"bytes"
+	"crypto/rand"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func encrypt(t *testing.T, data []byte, passphrase string) []byte {
+	var out bytes.Buffer
+	writer, err := NewWriter(&out, []byte(passphrase))
+	assert.NoError(t, err)
+	_, err = writer.Write(data)
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+	return out.Bytes()
+}
+
+func TestArchiveCrypto(t *testing.T) {
+	scryptLogN = 10
+	data := make([]byte, 3*chunkSize+100)
+	_, err := rand.Read(data)
+	assert.NoError(t, err)
+
+	for _, size := range []int{0, 10, chunkSize, len(data)} {
+		encrypted := encrypt(t, data[:size], "secret")
+		reader, err := NewReader(bytes.NewReader(encrypted), []byte("secret"))
+		assert.NoError(t, err)
+		decrypted, err := io.ReadAll(reader)
+		assert.NoError(t, err)
+		assert.Equal(t, data[:size], decrypted)
+	}
+
+	encrypted := encrypt(t, data, "secret")
+	reader, err := NewReader(bytes.NewReader(encrypted), []byte("wrong"))
+	assert.NoError(t, err)
+	_, err = io.ReadAll(reader)
+	assert.ErrorIs(t, err, ErrWrongPassphrase)
+
+	// the last chunk is dropped
+	reader, err = NewReader(bytes.NewReader(encrypted[:headerSize+3*(4+chunkSize+16)]), []byte("secret"))
+	assert.NoError(t, err)
+	_, err = io.ReadAll(reader)
+	assert.ErrorIs(t, err, ErrTruncated)
+
+	tampered := append([]byte(nil), encrypted...)
+	tampered[headerSize+10] ^= 1
+	reader, err = NewReader(bytes.NewReader(tampered), []byte("secret"))
+	assert.NoError(t, err)
+	_, err = io.ReadAll(reader)
+	assert.ErrorIs(t, err, ErrWrongPassphrase)
+
+	_, err = NewReader(bytes.NewReader([]byte("not an archive at all, really")), []byte("secret"))
+	assert.ErrorIs(t, err, ErrNotArchive)
+}
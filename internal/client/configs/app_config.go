@@ -8,19 +8,35 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"time"
 
 	"github.com/spf13/pflag"
+
+	"ydx-goadv-gophkeeper/internal/client/model"
 )
 
 const (
-	defaultPort           = ":3200"
-	defaultPrivateKeyPath = "cmd/client/privkey.pem"
+	defaultPort             = ":3200"
+	defaultPrivateKeyPath   = "cmd/client/privkey.pem"
+	defaultAgentIdleTimeout = 15 * time.Minute
+	agentSocketEnvVar       = "GOPHKEEPER_AGENT_SOCK"
+	hibpFileEnvVar          = "GOPHKEEPER_HIBP_FILE"
 )
 
+var ErrPrivateKeyEncrypted = errors.New("private key is encrypted, passphrase is required")
+
 type AppConfig struct {
-	ServerPort     string `env:"SERVER_PORT" json:"server_port"`
-	PrivateKey     *rsa.PrivateKey
-	PrivateKeyPath string `env:"CRYPTO_KEY_PATH" json:"crypto_key_path"`
+	ServerPort          string `env:"SERVER_PORT" json:"server_port"`
+	PrivateKey          *rsa.PrivateKey
+	PrivateKeyPath      string `env:"CRYPTO_KEY_PATH" json:"crypto_key_path"`
+	PrivateKeyEncrypted bool
+	AgentSocket         string `env:"GOPHKEEPER_AGENT_SOCK" json:"agent_socket"`
+	AgentIdleTimeoutStr string `json:"agent_idle_timeout"`
+	AgentIdleTimeout    time.Duration
+	HibpFile            string `env:"GOPHKEEPER_HIBP_FILE" json:"hibp_file"`
+	// Compression is off unless types are set
+	Compression model.CompressionPolicy `json:"compression"`
 }
 
 func InitAppConfig(configPath string) (*AppConfig, error) {
@@ -29,6 +45,13 @@ func InitAppConfig(configPath string) (*AppConfig, error) {
 		return nil, err
 	}
 	setupConfigByFlags(config)
+	if hibpFile := os.Getenv(hibpFileEnvVar); hibpFile != "" {
+		config.HibpFile = hibpFile
+	}
+	err = setupAgent(config)
+	if err != nil {
+		return nil, err
+	}
 	err = setupRSAKey(config)
 	if err != nil {
 		return nil, err
@@ -58,6 +81,8 @@ func setupConfigByFlags(cfg *AppConfig) {
 	var privateKeyPathF string
 	pflag.StringVarP(&privateKeyPathF, "f", "f", defaultPrivateKeyPath, "Path of Backup store file")
 
+	// flags of the client modes (run, inject, etc.) are parsed by the modes themselves
+	pflag.CommandLine.ParseErrorsWhitelist.UnknownFlags = true
 	pflag.Parse()
 
 	if cfg.ServerPort == "" && serverPortF != "" {
@@ -68,9 +93,35 @@ func setupConfigByFlags(cfg *AppConfig) {
 	}
 }
 
+func setupAgent(config *AppConfig) error {
+	if socket := os.Getenv(agentSocketEnvVar); socket != "" {
+		config.AgentSocket = socket
+	}
+	// runtime dir is private to the user, the temp dir is shared and the socket dir there is checked by the agent
+	if runtimeDir := os.Getenv("XDG_RUNTIME_DIR"); config.AgentSocket == "" && runtimeDir != "" {
+		config.AgentSocket = filepath.Join(runtimeDir, "gophkeeper", "agent.sock")
+	}
+	if config.AgentSocket == "" {
+		config.AgentSocket = filepath.Join(os.TempDir(), fmt.Sprintf("gophkeeper-%d", os.Getuid()), "agent.sock")
+	}
+	config.AgentIdleTimeout = defaultAgentIdleTimeout
+	if config.AgentIdleTimeoutStr != "" {
+		timeout, err := time.ParseDuration(config.AgentIdleTimeoutStr)
+		if err != nil {
+			return fmt.Errorf("failed to parse agent idle timeout '%s': %v", config.AgentIdleTimeoutStr, err)
+		}
+		config.AgentIdleTimeout = timeout
+	}
+	return nil
+}
+
 func setupRSAKey(config *AppConfig) error {
 	if config.PrivateKeyPath != "" {
-		key, err := readRsaPrivateKey(config.PrivateKeyPath)
+		key, err := ReadRsaPrivateKey(config.PrivateKeyPath, nil)
+		if errors.Is(err, ErrPrivateKeyEncrypted) {
+			config.PrivateKeyEncrypted = true
+			return nil
+		}
 		if err != nil {
 			return err
 		}
@@ -79,6 +130,16 @@ func setupRSAKey(config *AppConfig) error {
 	return nil
 }
 
+// UnlockPrivateKey decrypts passphrase protected private key
+func (cfg *AppConfig) UnlockPrivateKey(passphrase []byte) error {
+	key, err := ReadRsaPrivateKey(cfg.PrivateKeyPath, passphrase)
+	if err != nil {
+		return err
+	}
+	cfg.PrivateKey = key
+	return nil
+}
+
 func readConfig(configFilePath string) (*AppConfig, error) {
 	if configFilePath == "" {
 		return nil, errors.New("failed to init configuration: file path is not specified")
@@ -95,13 +156,28 @@ func readConfig(configFilePath string) (*AppConfig, error) {
 	return &config, nil
 }
 
-func readRsaPrivateKey(cryptoKeyPath string) (*rsa.PrivateKey, error) {
+// ReadRsaPrivateKey reads PKCS1 private key, passphrase is used only for encrypted PEM blocks
+func ReadRsaPrivateKey(cryptoKeyPath string, passphrase []byte) (*rsa.PrivateKey, error) {
 	pemBytes, err := os.ReadFile(cryptoKeyPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read publicKey by '%s': %v", cryptoKeyPath, err)
 	}
 	block, _ := pem.Decode(pemBytes)
-	key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if block == nil {
+		return nil, fmt.Errorf("failed to decode pem block of '%s'", cryptoKeyPath)
+	}
+	keyBytes := block.Bytes
+	// legacy PEM encryption is the one produced by 'openssl genrsa -aes256'
+	if x509.IsEncryptedPEMBlock(block) {
+		if len(passphrase) == 0 {
+			return nil, ErrPrivateKeyEncrypted
+		}
+		keyBytes, err = x509.DecryptPEMBlock(block, passphrase)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt private key: %v", err)
+		}
+	}
+	key, err := x509.ParsePKCS1PrivateKey(keyBytes)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse publicKey: %v", err)
 	}
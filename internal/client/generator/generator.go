@@ -0,0 +1,225 @@
+package generator
+
+import (
+	"crypto/rand"
+	_ "embed"
+	"errors"
+	"io"
+	"math"
+	"math/big"
+	"strings"
+	"unicode"
+)
+
+const (
+	lowerChars     = "abcdefghijklmnopqrstuvwxyz"
+	upperChars     = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	digitChars     = "0123456789"
+	symbolChars    = "!@#$%^&*()-_=+[]{};:,.<>/?~"
+	ambiguousChars = "Il1O0o|"
+
+	maxLength = 1024
+	maxWords  = 64
+)
+
+// effWordlist is EFF's large wordlist for diceware passphrases:
+// https://www.eff.org/files/2016/07/18/eff_large_wordlist.txt
+//
+//go:embed eff_large_wordlist.txt
+var effWordlist string
+
+var effWords = parseWordlist(effWordlist)
+
+type PasswordOptions struct {
+	Length           int
+	Lower            bool
+	Upper            bool
+	Digits           bool
+	Symbols          bool
+	ExcludeAmbiguous bool
+}
+
+func DefaultPasswordOptions() PasswordOptions {
+	return PasswordOptions{Length: 20, Lower: true, Upper: true, Digits: true, Symbols: true, ExcludeAmbiguous: true}
+}
+
+type PassphraseOptions struct {
+	Words      int
+	Separator  string
+	Capitalize bool
+}
+
+func DefaultPassphraseOptions() PassphraseOptions {
+	return PassphraseOptions{Words: 6, Separator: "-"}
+}
+
+//go:generate mockgen -source=generator.go -destination=../mocks/generator/generator.go -package=generator
+type Generator interface {
+	// Password returns random password with at least one char of every enabled class and its entropy in bits
+	Password(opts PasswordOptions) (string, float64, error)
+	// Passphrase returns diceware passphrase and its entropy in bits
+	Passphrase(opts PassphraseOptions) (string, float64, error)
+}
+
+type generator struct {
+	random io.Reader
+	words  []string
+}
+
+func NewGenerator() Generator {
+	return newGenerator(rand.Reader)
+}
+
+func newGenerator(random io.Reader) *generator {
+	return &generator{random: random, words: effWords}
+}
+
+// parseWordlist reads words from 'dice rolls<TAB>word' lines
+func parseWordlist(wordlist string) []string {
+	var words []string
+	for _, line := range strings.Split(strings.TrimSpace(wordlist), "\n") {
+		_, word, _ := strings.Cut(line, "\t")
+		words = append(words, strings.TrimSpace(word))
+	}
+	return words
+}
+
+func (g *generator) Password(opts PasswordOptions) (string, float64, error) {
+	classes := opts.classes()
+	if len(classes) == 0 {
+		return "", 0, errors.New("at least one character class should be enabled")
+	}
+	if opts.Length < len(classes) || opts.Length > maxLength {
+		return "", 0, errors.New("password length should be between number of enabled classes and 1024")
+	}
+	pool := strings.Join(classes, "")
+
+	password := make([]byte, opts.Length)
+	for {
+		for i := range password {
+			index, err := g.intn(len(pool))
+			if err != nil {
+				return "", 0, err
+			}
+			password[i] = pool[index]
+		}
+		if containsAll(string(password), classes) {
+			return string(password), float64(opts.Length) * math.Log2(float64(len(pool))), nil
+		}
+	}
+}
+
+func (g *generator) Passphrase(opts PassphraseOptions) (string, float64, error) {
+	if opts.Words <= 0 || opts.Words > maxWords {
+		return "", 0, errors.New("passphrase words count should be between 1 and 64")
+	}
+	words := make([]string, opts.Words)
+	for i := range words {
+		index, err := g.intn(len(g.words))
+		if err != nil {
+			return "", 0, err
+		}
+		words[i] = g.words[index]
+		if opts.Capitalize {
+			words[i] = strings.ToUpper(words[i][:1]) + words[i][1:]
+		}
+	}
+	return strings.Join(words, opts.Separator), float64(opts.Words) * math.Log2(float64(len(g.words))), nil
+}
+
+func (g *generator) intn(n int) (int, error) {
+	index, err := rand.Int(g.random, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, err
+	}
+	return int(index.Int64()), nil
+}
+
+func (opts PasswordOptions) classes() []string {
+	var classes []string
+	for _, class := range []struct {
+		enabled bool
+		chars   string
+	}{
+		{opts.Lower, lowerChars},
+		{opts.Upper, upperChars},
+		{opts.Digits, digitChars},
+		{opts.Symbols, symbolChars},
+	} {
+		if !class.enabled {
+			continue
+		}
+		chars := class.chars
+		if opts.ExcludeAmbiguous {
+			chars = strings.Map(func(r rune) rune {
+				if strings.ContainsRune(ambiguousChars, r) {
+					return -1
+				}
+				return r
+			}, chars)
+		}
+		classes = append(classes, chars)
+	}
+	return classes
+}
+
+func containsAll(password string, classes []string) bool {
+	for _, class := range classes {
+		if !strings.ContainsAny(password, class) {
+			return false
+		}
+	}
+	return true
+}
+
+// EstimateEntropy estimates entropy in bits of the password typed by user by its char classes
+func EstimateEntropy(password string) float64 {
+	var lower, upper, digit, symbol, other bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		case strings.ContainsRune(symbolChars, r):
+			symbol = true
+		default:
+			other = true
+		}
+	}
+	pool := 0
+	for _, class := range []struct {
+		present bool
+		size    int
+	}{
+		{lower, len(lowerChars)},
+		{upper, len(upperChars)},
+		{digit, len(digitChars)},
+		{symbol, len(symbolChars)},
+		{other, 32},
+	} {
+		if class.present {
+			pool += class.size
+		}
+	}
+	if pool == 0 {
+		return 0
+	}
+	return float64(len([]rune(password))) * math.Log2(float64(pool))
+}
+
+// Strength describes entropy in bits
+func Strength(entropy float64) string {
+	switch {
+	case entropy < 40:
+		return "weak"
+	case entropy < 60:
+		return "fair"
+	case entropy < 80:
+		return "strong"
+	default:
+		return "very strong"
+	}
+}
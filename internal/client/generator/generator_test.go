@@ -0,0 +1,73 @@
+package generator
+
+import (
+	"crypto/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerator_Password(t *testing.T) {
+	gen := newGenerator(rand.Reader)
+
+	password, entropy, err := gen.Password(DefaultPasswordOptions())
+	assert.NoError(t, err)
+	assert.Len(t, password, 20)
+	assert.False(t, strings.ContainsAny(password, ambiguousChars))
+	assert.True(t, strings.ContainsAny(password, digitChars))
+	assert.InDelta(t, 127.5, entropy, 0.1)
+
+	password, _, err = gen.Password(PasswordOptions{Length: 8, Digits: true})
+	assert.NoError(t, err)
+	assert.Equal(t, "", strings.Trim(password, digitChars))
+
+	_, _, err = gen.Password(PasswordOptions{Length: 8})
+	assert.Error(t, err)
+	_, _, err = gen.Password(PasswordOptions{Length: 2, Lower: true, Upper: true, Digits: true})
+	assert.Error(t, err)
+}
+
+func TestGenerator_Passphrase(t *testing.T) {
+	gen := newGenerator(rand.Reader)
+	assert.Len(t, gen.words, 7776)
+
+	passphrase, entropy, err := gen.Passphrase(PassphraseOptions{Words: 5, Separator: " ", Capitalize: true})
+	assert.NoError(t, err)
+	words := strings.Split(passphrase, " ")
+	assert.Len(t, words, 5)
+	for _, word := range words {
+		assert.Equal(t, strings.ToUpper(word[:1]), word[:1])
+	}
+	assert.InDelta(t, 64.6, entropy, 0.1)
+}
+
+func TestEstimateEntropy(t *testing.T) {
+	assert.Equal(t, float64(0), EstimateEntropy(""))
+	assert.InDelta(t, 41.4, EstimateEntropy("qwerty12"), 0.1)
+	assert.Equal(t, "weak", Strength(EstimateEntropy("qwerty")))
+}
+
+func TestAnalyze(t *testing.T) {
+	tests := []struct {
+		password string
+		score    int
+		patterns []string
+	}{
+		{password: "P@ssw0rd", score: 0, patterns: []string{"common password"}},
+		{password: "qwertyuiop", score: 0, patterns: []string{"common password"}},
+		{password: "sdfghj2019", score: 0, patterns: []string{"keyboard pattern", "year"}},
+		{password: "Dragonfly1234", score: 0, patterns: []string{"dictionary word", "sequence"}},
+		{password: "aaaaaaaaaaaa", score: 0, patterns: []string{"repeated characters"}},
+		{password: "correct-horse-battery-staple", score: 4, patterns: []string{"dictionary word"}},
+		{password: "x7#Kp9!vQ2$mZ4", score: 4},
+	}
+
+	for _, test := range tests {
+		t.Run(test.password, func(t *testing.T) {
+			analysis := Analyze(test.password)
+			assert.Equal(t, test.score, analysis.Score, "entropy %.1f", analysis.Entropy)
+			assert.Equal(t, test.patterns, analysis.Patterns)
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package generator
+
+import (
+	_ "embed"
+	"math"
+	"strings"
+	"unicode"
+)
+
+const (
+	minPatternLength = 3
+	minWordLength    = 4
+)
+
+//go:embed common_passwords.txt
+var commonPasswordsList string
+
+var (
+	commonPasswords = toSet(strings.Fields(commonPasswordsList))
+	dictionary      = toSet(append(strings.Fields(commonPasswordsList), effWords...))
+	keyboardRows    = []string{"`1234567890-=", "qwertyuiop[]\\", "asdfghjkl;'", "zxcvbnm,./", "1qaz2wsx3edc4rfv5tgb6yhn7ujm8ik9ol0p"}
+	leetReplacer    = strings.NewReplacer("@", "a", "4", "a", "3", "e", "1", "i", "!", "i", "0", "o", "$", "s", "5", "s", "7", "t")
+)
+
+// Analysis is a zxcvbn-like password estimation: password is split into the known patterns
+// which are cheaper to guess than random characters
+type Analysis struct {
+	Entropy  float64  `json:"entropy"`
+	Score    int      `json:"score"`
+	Patterns []string `json:"patterns,omitempty"`
+}
+
+// Analyze estimates password entropy taking into account common passwords, dictionary words,
+// keyboard walks, sequences, repeats and years
+func Analyze(password string) Analysis {
+	lower := strings.ToLower(password)
+	if commonPasswords[lower] || commonPasswords[leetReplacer.Replace(lower)] {
+		return Analysis{Entropy: math.Log2(float64(len(commonPasswords))), Score: 0, Patterns: []string{"common password"}}
+	}
+
+	runes := []rune(lower)
+	original := []rune(password)
+	var patterns []string
+	var entropy float64
+	// unmatched characters are estimated together as a brute force run
+	var run []rune
+	for i := 0; i < len(runes); {
+		length, bits, pattern := matchPattern(runes[i:], original[i:])
+		if length == 0 {
+			run = append(run, original[i])
+			i++
+			continue
+		}
+		entropy += EstimateEntropy(string(run)) + bits
+		run = run[:0]
+		patterns = appendUnique(patterns, pattern)
+		i += length
+	}
+	entropy += EstimateEntropy(string(run))
+	return Analysis{Entropy: entropy, Score: score(entropy), Patterns: patterns}
+}
+
+// matchPattern returns the longest pattern from the start of the runes
+func matchPattern(runes []rune, original []rune) (int, float64, string) {
+	var bestLength int
+	var bestBits float64
+	var bestPattern string
+	try := func(length int, bits float64, pattern string) {
+		if length > bestLength {
+			bestLength, bestBits, bestPattern = length, bits, pattern
+		}
+	}
+
+	for length := len(runes); length >= minWordLength; length-- {
+		word := string(runes[:length])
+		unleet := leetReplacer.Replace(word)
+		if dictionary[word] || dictionary[unleet] {
+			bits := math.Log2(float64(len(dictionary)))
+			if word != unleet {
+				bits++
+			}
+			if hasUpper(original[:length]) {
+				bits++
+			}
+			try(length, bits, "dictionary word")
+			break
+		}
+	}
+	if length := repeatLength(runes); length >= minPatternLength {
+		try(length, EstimateEntropy(string(runes[0]))+math.Log2(float64(length)), "repeated characters")
+	}
+	if length := sequenceLength(runes); length >= minPatternLength {
+		try(length, math.Log2(26)+math.Log2(float64(length)), "sequence")
+	}
+	if length := keyboardLength(runes); length >= minPatternLength {
+		try(length, math.Log2(float64(len(keyboardRows)*26))+math.Log2(float64(length)), "keyboard pattern")
+	}
+	if len(runes) >= 4 {
+		year := string(runes[:4])
+		if (strings.HasPrefix(year, "19") || strings.HasPrefix(year, "20")) && isDigits(year) {
+			try(4, math.Log2(200), "year")
+		}
+	}
+	return bestLength, bestBits, bestPattern
+}
+
+func repeatLength(runes []rune) int {
+	length := 1
+	for length < len(runes) && runes[length] == runes[0] {
+		length++
+	}
+	return length
+}
+
+func sequenceLength(runes []rune) int {
+	if len(runes) < 2 {
+		return len(runes)
+	}
+	step := runes[1] - runes[0]
+	if step != 1 && step != -1 {
+		return 1
+	}
+	length := 2
+	for length < len(runes) && runes[length]-runes[length-1] == step {
+		length++
+	}
+	return length
+}
+
+func keyboardLength(runes []rune) int {
+	best := 1
+	for _, row := range keyboardRows {
+		for _, reversed := range []bool{false, true} {
+			line := row
+			if reversed {
+				line = reverse(row)
+			}
+			start := strings.IndexRune(line, runes[0])
+			if start < 0 {
+				continue
+			}
+			lineRunes := []rune(line)
+			length := 1
+			for length < len(runes) && start+length < len(lineRunes) && runes[length] == lineRunes[start+length] {
+				length++
+			}
+			if length > best {
+				best = length
+			}
+		}
+	}
+	return best
+}
+
+func score(entropy float64) int {
+	switch {
+	case entropy < 28:
+		return 0
+	case entropy < 36:
+		return 1
+	case entropy < 60:
+		return 2
+	case entropy < 80:
+		return 3
+	default:
+		return 4
+	}
+}
+
+func hasUpper(runes []rune) bool {
+	for _, r := range runes {
+		if unicode.IsUpper(r) {
+			return true
+		}
+	}
+	return false
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func reverse(s string) string {
+	runes := []rune(s)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+	return string(runes)
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
+
+func toSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, value := range values {
+		set[value] = true
+	}
+	return set
+}
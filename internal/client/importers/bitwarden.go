@@ -0,0 +1,231 @@
+package importers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"ydx-goadv-gophkeeper/internal/client/model/resources"
+)
+
+const (
+	bitwardenLogin = iota + 1
+	bitwardenSecureNote
+	bitwardenCard
+	bitwardenIdentity
+
+	bitwardenHiddenField = 1
+)
+
+type bitwardenExport struct {
+	Encrypted bool `json:"encrypted"`
+	Folders   []struct {
+		Id   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"folders"`
+	Items []bitwardenItem `json:"items"`
+}
+
+type bitwardenItem struct {
+	Type     int    `json:"type"`
+	FolderId string `json:"folderId"`
+	Name     string `json:"name"`
+	Notes    string `json:"notes"`
+	Fields   []struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+		Type  int    `json:"type"`
+	} `json:"fields"`
+	Login *bitwardenItemLogin `json:"login"`
+	Card  *struct {
+		CardholderName string `json:"cardholderName"`
+		Number         string `json:"number"`
+		ExpMonth       string `json:"expMonth"`
+		ExpYear        string `json:"expYear"`
+		Code           string `json:"code"`
+	} `json:"card"`
+	Identity *struct {
+		FirstName      string `json:"firstName"`
+		LastName       string `json:"lastName"`
+		Email          string `json:"email"`
+		Phone          string `json:"phone"`
+		Address1       string `json:"address1"`
+		Address2       string `json:"address2"`
+		City           string `json:"city"`
+		PostalCode     string `json:"postalCode"`
+		Country        string `json:"country"`
+		PassportNumber string `json:"passportNumber"`
+		Ssn            string `json:"ssn"`
+		LicenseNumber  string `json:"licenseNumber"`
+	} `json:"identity"`
+}
+
+type bitwardenItemLogin struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Totp     string `json:"totp"`
+	Uris     []struct {
+		Uri string `json:"uri"`
+	} `json:"uris"`
+}
+
+// bitwardenJsonParser parses unencrypted Bitwarden json export
+type bitwardenJsonParser struct{}
+
+func (p *bitwardenJsonParser) Parse(path string) ([]Entry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var export bitwardenExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, fmt.Errorf("failed to parse Bitwarden json: %v", err)
+	}
+	if export.Encrypted {
+		return nil, fmt.Errorf("encrypted Bitwarden export is not supported, export vault as unencrypted json")
+	}
+	folders := make(map[string]string, len(export.Folders))
+	for _, folder := range export.Folders {
+		folders[folder.Id] = folder.Name
+	}
+	var entries []Entry
+	for _, item := range export.Items {
+		entry, ok := item.entry()
+		if ok {
+			entry.Folder = folders[item.FolderId]
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func (item *bitwardenItem) entry() (Entry, bool) {
+	var resource resources.ResourceClIFormatter
+	switch {
+	case item.Type == bitwardenLogin && item.Login != nil:
+		var url string
+		if len(item.Login.Uris) != 0 {
+			url = item.Login.Uris[0].Uri
+		}
+		lp := resources.NewLoginPassword(item.Login.Username, item.Login.Password, url)
+		addField(lp, notesField, item.Notes, false)
+		addField(lp, "totp", item.Login.Totp, true)
+		resource = lp
+	case item.Type == bitwardenCard && item.Card != nil:
+		name, surname := splitName(item.Card.CardholderName)
+		card := resources.NewBankCard(item.Card.Number, cardExpiry(item.Card.ExpMonth, item.Card.ExpYear), name, surname)
+		addField(card, "cvv", item.Card.Code, true)
+		addField(card, notesField, item.Notes, false)
+		resource = card
+	case item.Type == bitwardenIdentity && item.Identity != nil:
+		id := item.Identity
+		identity := resources.NewIdentity(id.FirstName, id.LastName, "", id.Email, id.Phone)
+		address := strings.Join(nonEmpty(id.Address1, id.Address2, id.City, id.PostalCode, id.Country), ", ")
+		if address != "" {
+			identity.Addresses = []string{address}
+		}
+		identity.PassportNumber = id.PassportNumber
+		identity.IdNumber = id.Ssn
+		addField(identity, "licenseNumber", id.LicenseNumber, false)
+		addField(identity, notesField, item.Notes, false)
+		resource = identity
+	case item.Type == bitwardenSecureNote:
+		resource = resources.NewSecureNote(item.Notes)
+	default:
+		return Entry{}, false
+	}
+	if holder, ok := resource.(resources.CustomFieldsHolder); ok {
+		for _, field := range item.Fields {
+			addField(holder, field.Name, field.Value, field.Type == bitwardenHiddenField)
+		}
+	}
+	return Entry{Resource: resource, Description: item.Name}, true
+}
+
+// bitwardenCsvParser parses Bitwarden csv export, it contains only logins and notes
+type bitwardenCsvParser struct{}
+
+func (p *bitwardenCsvParser) Parse(path string) ([]Entry, error) {
+	rows, err := readCsv(path)
+	if err != nil {
+		return nil, err
+	}
+	var entries []Entry
+	for _, row := range rows {
+		item := bitwardenItem{Name: row["name"], Notes: row["notes"]}
+		switch row["type"] {
+		case "login":
+			item.Type = bitwardenLogin
+			item.Login = &bitwardenItemLogin{Username: row["login_username"], Password: row["login_password"], Totp: row["login_totp"]}
+			if uri := strings.Split(row["login_uri"], ",")[0]; uri != "" {
+				item.Login.Uris = append(item.Login.Uris, struct {
+					Uri string `json:"uri"`
+				}{Uri: uri})
+			}
+		case "note":
+			item.Type = bitwardenSecureNote
+		default:
+			continue
+		}
+		entry, _ := item.entry()
+		entry.Folder = row["folder"]
+		if holder, ok := entry.Resource.(resources.CustomFieldsHolder); ok {
+			// fields are exported as 'name: value' lines
+			for _, line := range strings.Split(row["fields"], "\n") {
+				if name, value, ok := strings.Cut(line, ": "); ok {
+					addField(holder, name, value, false)
+				}
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// readCsv reads rows as maps by header names
+func readCsv(path string) ([]map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read csv header: %v", err)
+	}
+	for i := range header {
+		header[i] = strings.TrimSpace(strings.TrimPrefix(header[i], "\ufeff"))
+	}
+	var rows []map[string]string
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read csv: %v", err)
+		}
+		row := make(map[string]string, len(header))
+		for i, value := range record {
+			if i < len(header) {
+				row[header[i]] = value
+			}
+		}
+		rows = append(rows, row)
+	}
+}
+
+func nonEmpty(values ...string) []string {
+	var result []string
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			result = append(result, value)
+		}
+	}
+	return result
+}
@@ -0,0 +1,113 @@
+package importers
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"ydx-goadv-gophkeeper/internal/client/model/resources"
+)
+
+const (
+	mappingType        = "type"
+	mappingDescription = "description"
+	// mappingFieldPrefix maps column to text custom field, i.e. 'field:pin=PIN'
+	mappingFieldPrefix = "field:"
+)
+
+// csvParser maps csv columns to the resource schema fields
+type csvParser struct {
+	kind    resources.Kind
+	columns map[string]string
+	// customFields are mapped custom field names in order of mapping
+	customFields []string
+}
+
+func newCsvParser(mapping string) (*csvParser, error) {
+	p := &csvParser{columns: make(map[string]string)}
+	alias := "lp"
+	for _, pair := range strings.Split(mapping, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		field, column, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("mapping '%s' is not in format field=column", pair)
+		}
+		field, column = strings.TrimSpace(field), strings.TrimSpace(column)
+		if field == mappingType {
+			alias = column
+			continue
+		}
+		if strings.HasPrefix(field, mappingFieldPrefix) {
+			p.customFields = append(p.customFields, field)
+		}
+		p.columns[field] = column
+	}
+	kind, ok := resources.KindByAlias(alias)
+	if !ok || len(kind.Schema) == 0 {
+		return nil, fmt.Errorf("type '%s' can't be imported from csv", alias)
+	}
+	p.kind = kind
+	for field := range p.columns {
+		if field != mappingDescription && !strings.HasPrefix(field, mappingFieldPrefix) && p.schemaField(field) == nil {
+			return nil, fmt.Errorf("field '%s' is not a field of %s", field, kind.Name)
+		}
+	}
+	return p, nil
+}
+
+func (p *csvParser) schemaField(name string) *resources.SchemaField {
+	for i := range p.kind.Schema {
+		if p.kind.Schema[i].Name == name {
+			return &p.kind.Schema[i]
+		}
+	}
+	return nil
+}
+
+func (p *csvParser) Parse(path string) ([]Entry, error) {
+	rows, err := readCsv(path)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]Entry, 0, len(rows))
+	for i, row := range rows {
+		data := make(map[string]interface{})
+		for field, column := range p.columns {
+			schemaField := p.schemaField(field)
+			value := row[column]
+			if schemaField == nil || value == "" {
+				continue
+			}
+			switch schemaField.Input {
+			case resources.NumberInput:
+				number, err := strconv.ParseInt(value, 10, 32)
+				if err != nil {
+					return nil, fmt.Errorf("row %d: column '%s' is not a number", i+1, column)
+				}
+				data[field] = number
+			case resources.ListInput:
+				data[field] = strings.Split(value, "\n")
+			default:
+				data[field] = value
+			}
+		}
+		dataJson, err := json.Marshal(data)
+		if err != nil {
+			return nil, err
+		}
+		resource := p.kind.New()
+		if err := json.Unmarshal(dataJson, resource); err != nil {
+			return nil, fmt.Errorf("row %d: %v", i+1, err)
+		}
+		if holder, ok := resource.(resources.CustomFieldsHolder); ok {
+			for _, field := range p.customFields {
+				addField(holder, strings.TrimPrefix(field, mappingFieldPrefix), row[p.columns[field]], false)
+			}
+		}
+		entries = append(entries, Entry{Resource: resource, Description: row[p.columns[mappingDescription]]})
+	}
+	return entries, nil
+}
@@ -0,0 +1,106 @@
+package importers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"ydx-goadv-gophkeeper/internal/client/model/resources"
+)
+
+const (
+	BitwardenJson = "bitwarden-json"
+	BitwardenCsv  = "bitwarden-csv"
+	KeePassXml    = "keepass-xml"
+	OnePassword   = "1pux"
+	GenericCsv    = "csv"
+
+	notesField = "notes"
+)
+
+// Entry is the parsed resource, File entries have Content instead of Resource data
+type Entry struct {
+	Resource    resources.ResourceClIFormatter
+	Description string
+	// Folder is the slash separated folder of the source password manager
+	Folder   string
+	FileName string
+	Content  []byte
+}
+
+//go:generate mockgen -source=importer.go -destination=../mocks/importers/importer.go -package=importers
+
+type Parser interface {
+	Parse(path string) ([]Entry, error)
+}
+
+// NewParser returns parser of the export format, mapping is used by generic csv only:
+// 'type=lp,login=Username,password=Password,url=URL,description=Title'
+func NewParser(format string, mapping string) (Parser, error) {
+	switch format {
+	case BitwardenJson:
+		return &bitwardenJsonParser{}, nil
+	case BitwardenCsv:
+		return &bitwardenCsvParser{}, nil
+	case KeePassXml:
+		return &keePassXmlParser{}, nil
+	case OnePassword:
+		return &onePasswordParser{}, nil
+	case GenericCsv:
+		return newCsvParser(mapping)
+	default:
+		return nil, fmt.Errorf("import format '%s' is not supported, available: %s", format, strings.Join(Formats(), ", "))
+	}
+}
+
+func Formats() []string {
+	return []string{BitwardenJson, BitwardenCsv, KeePassXml, OnePassword, GenericCsv}
+}
+
+// addField adds text or hidden custom field if the value is not empty
+func addField(holder resources.CustomFieldsHolder, name string, value string, hidden bool) {
+	if strings.TrimSpace(value) == "" {
+		return
+	}
+	fieldType := resources.TextField
+	if hidden {
+		fieldType = resources.HiddenField
+	}
+	holder.Custom().Set(resources.CustomField{Name: name, Type: fieldType, Value: value})
+}
+
+// addFields adds custom fields sorted by name
+func addFields(holder resources.CustomFieldsHolder, fields map[string]string, hidden map[string]bool) {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		addField(holder, name, fields[name], hidden[name])
+	}
+}
+
+// splitName splits card holder name into name and surname
+func splitName(fullName string) (string, string) {
+	name, surname, _ := strings.Cut(strings.TrimSpace(fullName), " ")
+	return name, strings.TrimSpace(surname)
+}
+
+// cardExpiry formats month and year, i.e. '3' and '2027', as MM/YY
+func cardExpiry(month string, year string) string {
+	if month == "" || year == "" {
+		return ""
+	}
+	if len(month) == 1 {
+		month = "0" + month
+	}
+	if len(year) > 2 {
+		year = year[len(year)-2:]
+	}
+	return month + "/" + year
+}
+
+func secureNote(title string, text string) Entry {
+	return Entry{Resource: resources.NewSecureNote(text), Description: title}
+}
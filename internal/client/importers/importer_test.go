@@ -0,0 +1,167 @@
+package importers
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"ydx-goadv-gophkeeper/internal/client/model/resources"
+)
+
+func writeFile(t *testing.T, name string, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func parse(t *testing.T, format string, mapping string, path string) []Entry {
+	parser, err := NewParser(format, mapping)
+	assert.NoError(t, err)
+	entries, err := parser.Parse(path)
+	assert.NoError(t, err)
+	return entries
+}
+
+func TestBitwardenJsonParser(t *testing.T) {
+	path := writeFile(t, "bitwarden.json", `{"encrypted": false, "folders": [{"id": "f1", "name": "work/dev"}], "items": [
+		{"type": 1, "folderId": "f1", "name": "github", "notes": "work", "fields": [{"name": "pin", "value": "1234", "type": 1}],
+			"login": {"username": "octocat", "password": "secret", "totp": "JBSWY3DPEHPK3PXP", "uris": [{"uri": "https://github.com"}]}},
+		{"type": 2, "name": "wifi", "notes": "password: qwerty"},
+		{"type": 3, "name": "visa", "card": {"cardholderName": "John Smith", "number": "4111111111111111", "expMonth": "3", "expYear": "2030", "code": "123"}}
+	]}`)
+
+	entries := parse(t, BitwardenJson, "", path)
+	assert.Len(t, entries, 3)
+
+	lp := resources.NewLoginPassword("octocat", "secret", "https://github.com")
+	lp.Fields = []resources.CustomField{
+		{Name: "notes", Type: resources.TextField, Value: "work"},
+		{Name: "totp", Type: resources.HiddenField, Value: "JBSWY3DPEHPK3PXP"},
+		{Name: "pin", Type: resources.HiddenField, Value: "1234"},
+	}
+	assert.Equal(t, Entry{Resource: lp, Description: "github", Folder: "work/dev"}, entries[0])
+	assert.Equal(t, Entry{Resource: resources.NewSecureNote("password: qwerty"), Description: "wifi"}, entries[1])
+
+	card := resources.NewBankCard("4111111111111111", "03/30", "John", "Smith")
+	card.Fields = []resources.CustomField{{Name: "cvv", Type: resources.HiddenField, Value: "123"}}
+	assert.Equal(t, Entry{Resource: card, Description: "visa"}, entries[2])
+
+	encrypted := writeFile(t, "encrypted.json", `{"encrypted": true}`)
+	_, err := (&bitwardenJsonParser{}).Parse(encrypted)
+	assert.Error(t, err)
+}
+
+func TestBitwardenCsvParser(t *testing.T) {
+	path := writeFile(t, "bitwarden.csv", "folder,favorite,type,name,notes,fields,reprompt,login_uri,login_username,login_password,login_totp\n"+
+		",,login,github,,\"pin: 1234\",0,https://github.com,octocat,secret,\n"+
+		",,note,wifi,qwerty,,0,,,,\n")
+
+	entries := parse(t, BitwardenCsv, "", path)
+	assert.Len(t, entries, 2)
+	lp := resources.NewLoginPassword("octocat", "secret", "https://github.com")
+	lp.Fields = []resources.CustomField{{Name: "pin", Type: resources.TextField, Value: "1234"}}
+	assert.Equal(t, Entry{Resource: lp, Description: "github"}, entries[0])
+	assert.Equal(t, Entry{Resource: resources.NewSecureNote("qwerty"), Description: "wifi"}, entries[1])
+}
+
+func TestKeePassXmlParser(t *testing.T) {
+	path := writeFile(t, "keepass.xml", `<?xml version="1.0" encoding="utf-8"?>
+<KeePassFile>
+	<Meta>
+		<RecycleBinEnabled>True</RecycleBinEnabled>
+		<RecycleBinUUID>bin</RecycleBinUUID>
+		<Binaries><Binary ID="0" Compressed="False">aGVsbG8=</Binary></Binaries>
+	</Meta>
+	<Root><Group><UUID>root</UUID><Name>Root</Name>
+		<Entry>
+			<String><Key>Title</Key><Value>mail</Value></String>
+			<String><Key>UserName</Key><Value>john</Value></String>
+			<String><Key>Password</Key><Value ProtectInMemory="True">secret</Value></String>
+			<String><Key>URL</Key><Value>https://mail.example.com</Value></String>
+			<String><Key>Notes</Key><Value></Value></String>
+			<String><Key>pin</Key><Value ProtectInMemory="True">1234</Value></String>
+			<Binary><Key>hello.txt</Key><Value Ref="0"/></Binary>
+		</Entry>
+		<Group><UUID>notes</UUID><Name>Notes</Name>
+			<Entry><String><Key>Title</Key><Value>wifi</Value></String><String><Key>Notes</Key><Value>qwerty</Value></String></Entry>
+		</Group>
+		<Group><UUID>bin</UUID><Name>Recycle Bin</Name>
+			<Entry><String><Key>Title</Key><Value>deleted</Value></String></Entry>
+		</Group>
+	</Group></Root>
+</KeePassFile>`)
+
+	entries := parse(t, KeePassXml, "", path)
+	assert.Len(t, entries, 3)
+	lp := resources.NewLoginPassword("john", "secret", "https://mail.example.com")
+	lp.Fields = []resources.CustomField{{Name: "pin", Type: resources.HiddenField, Value: "1234"}}
+	assert.Equal(t, Entry{Resource: lp, Description: "mail"}, entries[0])
+	assert.Equal(t, Entry{
+		Resource:    &resources.File{Name: "hello.txt", Extension: ".txt", Size: 5},
+		Description: "mail: hello.txt",
+		FileName:    "hello.txt",
+		Content:     []byte("hello"),
+	}, entries[1])
+	assert.Equal(t, Entry{Resource: resources.NewSecureNote("qwerty"), Description: "wifi", Folder: "Notes"}, entries[2])
+}
+
+func TestOnePasswordParser(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "export.1pux")
+	file, err := os.Create(path)
+	assert.NoError(t, err)
+	archive := zip.NewWriter(file)
+	writer, err := archive.Create("export.data")
+	assert.NoError(t, err)
+	_, err = writer.Write([]byte(`{"accounts": [{"vaults": [{"items": [
+		{"state": "active", "categoryUuid": "001", "overview": {"title": "github", "url": "https://github.com"},
+			"details": {"loginFields": [{"value": "octocat", "designation": "username"}, {"value": "secret", "designation": "password"}],
+				"sections": [{"fields": [{"title": "pin", "id": "f1", "value": {"concealed": "1234"}}]}]}},
+		{"state": "active", "categoryUuid": "002", "overview": {"title": "visa"},
+			"details": {"sections": [{"fields": [
+				{"title": "cardholder name", "id": "cardholder", "value": {"string": "John Smith"}},
+				{"title": "number", "id": "ccnum", "value": {"creditCardNumber": "4111111111111111"}},
+				{"title": "expiry date", "id": "expiry", "value": {"monthYear": 203003}}]}]}},
+		{"state": "archived", "categoryUuid": "003", "overview": {"title": "old"}, "details": {"notesPlain": "old"}},
+		{"state": "active", "categoryUuid": "006", "overview": {"title": "passport scan"},
+			"details": {"documentAttributes": {"fileName": "scan.txt", "documentId": "doc1"}}}
+	]}]}]}`))
+	assert.NoError(t, err)
+	writer, err = archive.Create("files/doc1__scan.txt")
+	assert.NoError(t, err)
+	_, err = writer.Write([]byte("scan"))
+	assert.NoError(t, err)
+	assert.NoError(t, archive.Close())
+	assert.NoError(t, file.Close())
+
+	entries := parse(t, OnePassword, "", path)
+	assert.Len(t, entries, 3)
+	lp := resources.NewLoginPassword("octocat", "secret", "https://github.com")
+	lp.Fields = []resources.CustomField{{Name: "pin", Type: resources.HiddenField, Value: "1234"}}
+	assert.Equal(t, Entry{Resource: lp, Description: "github"}, entries[0])
+	assert.Equal(t, Entry{Resource: resources.NewBankCard("4111111111111111", "03/30", "John", "Smith"), Description: "visa"}, entries[1])
+	assert.Equal(t, Entry{
+		Resource:    &resources.File{Name: "scan.txt", Extension: ".txt", Size: 4},
+		Description: "passport scan",
+		FileName:    "scan.txt",
+		Content:     []byte("scan"),
+	}, entries[2])
+}
+
+func TestCsvParser(t *testing.T) {
+	path := writeFile(t, "export.csv", "Title,Username,Password,PIN\ngithub,octocat,secret,1234\n")
+
+	entries := parse(t, GenericCsv, "login=Username,password=Password,description=Title,field:pin=PIN", path)
+	lp := resources.NewLoginPassword("octocat", "secret", "")
+	lp.Fields = []resources.CustomField{{Name: "pin", Type: resources.TextField, Value: "1234"}}
+	assert.Equal(t, []Entry{{Resource: lp, Description: "github"}}, entries)
+
+	_, err := NewParser(GenericCsv, "type=lp,cvv=CVV")
+	assert.Error(t, err)
+	_, err = NewParser(GenericCsv, "type=fl")
+	assert.Error(t, err)
+	_, err = NewParser("lastpass", "")
+	assert.Error(t, err)
+}
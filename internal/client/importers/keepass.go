@@ -0,0 +1,163 @@
+package importers
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"ydx-goadv-gophkeeper/internal/client/model/resources"
+)
+
+type keePassFile struct {
+	Meta struct {
+		RecycleBinEnabled string `xml:"RecycleBinEnabled"`
+		RecycleBinUUID    string `xml:"RecycleBinUUID"`
+		Binaries          []struct {
+			ID         string `xml:"ID,attr"`
+			Compressed string `xml:"Compressed,attr"`
+			Value      string `xml:",chardata"`
+		} `xml:"Binaries>Binary"`
+	} `xml:"Meta"`
+	Root struct {
+		Groups []keePassGroup `xml:"Group"`
+	} `xml:"Root"`
+}
+
+type keePassGroup struct {
+	UUID    string         `xml:"UUID"`
+	Name    string         `xml:"Name"`
+	Entries []keePassEntry `xml:"Entry"`
+	Groups  []keePassGroup `xml:"Group"`
+}
+
+type keePassEntry struct {
+	Strings []struct {
+		Key   string `xml:"Key"`
+		Value struct {
+			Protected string `xml:"ProtectInMemory,attr"`
+			Value     string `xml:",chardata"`
+		} `xml:"Value"`
+	} `xml:"String"`
+	Binaries []struct {
+		Key   string `xml:"Key"`
+		Value struct {
+			Ref string `xml:"Ref,attr"`
+		} `xml:"Value"`
+	} `xml:"Binary"`
+}
+
+// keePassXmlParser parses KeePass 2 xml export, KDBX database should be exported to xml first
+type keePassXmlParser struct{}
+
+func (p *keePassXmlParser) Parse(filePath string) ([]Entry, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	var file keePassFile
+	if err := xml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse KeePass xml: %v", err)
+	}
+	binaries := make(map[string][]byte, len(file.Meta.Binaries))
+	for _, binary := range file.Meta.Binaries {
+		content, err := decodeKeePassBinary(binary.Value, strings.EqualFold(binary.Compressed, "true"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode KeePass attachment %s: %v", binary.ID, err)
+		}
+		binaries[binary.ID] = content
+	}
+	recycleBin := ""
+	if strings.EqualFold(file.Meta.RecycleBinEnabled, "true") {
+		recycleBin = file.Meta.RecycleBinUUID
+	}
+
+	var entries []Entry
+	// the root group is the database itself, its subgroups are folders
+	var walk func(group keePassGroup, folder string)
+	walk = func(group keePassGroup, folder string) {
+		if recycleBin != "" && group.UUID == recycleBin {
+			return
+		}
+		for _, entry := range group.Entries {
+			for _, parsed := range entry.entries(binaries) {
+				parsed.Folder = folder
+				entries = append(entries, parsed)
+			}
+		}
+		for _, child := range group.Groups {
+			walk(child, path.Join(folder, child.Name))
+		}
+	}
+	for _, group := range file.Root.Groups {
+		walk(group, "")
+	}
+	return entries, nil
+}
+
+// entries returns LoginPassword or SecureNote if there is no credentials and File per attachment
+func (e *keePassEntry) entries(binaries map[string][]byte) []Entry {
+	values := make(map[string]string, len(e.Strings))
+	hidden := make(map[string]bool)
+	for _, str := range e.Strings {
+		values[str.Key] = str.Value.Value
+		hidden[str.Key] = strings.EqualFold(str.Value.Protected, "true")
+	}
+	title := values["Title"]
+	var resource resources.ResourceClIFormatter
+	if values["UserName"] == "" && values["Password"] == "" {
+		resource = resources.NewSecureNote(values["Notes"])
+	} else {
+		lp := resources.NewLoginPassword(values["UserName"], values["Password"], values["URL"])
+		addField(lp, notesField, values["Notes"], false)
+		resource = lp
+	}
+	for _, key := range []string{"Title", "UserName", "Password", "URL", "Notes"} {
+		delete(values, key)
+	}
+	addFields(resource.(resources.CustomFieldsHolder), values, hidden)
+	entries := []Entry{{Resource: resource, Description: title}}
+
+	for _, binary := range e.Binaries {
+		content, ok := binaries[binary.Value.Ref]
+		if !ok {
+			continue
+		}
+		entries = append(entries, fileEntry(binary.Key, content, title))
+	}
+	return entries
+}
+
+func decodeKeePassBinary(value string, compressed bool) ([]byte, error) {
+	content, err := base64.StdEncoding.DecodeString(strings.TrimSpace(value))
+	if err != nil || !compressed {
+		return content, err
+	}
+	reader, err := gzip.NewReader(bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
+
+// fileEntry describes attachment as '<owner title>: <file name>'
+func fileEntry(name string, content []byte, owner string) Entry {
+	name = filepath.Base(name)
+	description := name
+	if owner != "" {
+		description = owner + ": " + name
+	}
+	return Entry{
+		Resource:    &resources.File{Name: name, Extension: filepath.Ext(name), Size: int64(len(content))},
+		Description: description,
+		FileName:    name,
+		Content:     content,
+	}
+}
@@ -0,0 +1,247 @@
+package importers
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"ydx-goadv-gophkeeper/internal/client/model/resources"
+)
+
+const (
+	onePasswordData = "export.data"
+
+	onePasswordLogin      = "001"
+	onePasswordCard       = "002"
+	onePasswordSecureNote = "003"
+	onePasswordIdentity   = "004"
+	onePasswordPassword   = "005"
+	onePasswordDocument   = "006"
+)
+
+type onePasswordExport struct {
+	Accounts []struct {
+		Vaults []struct {
+			Attrs struct {
+				Name string `json:"name"`
+			} `json:"attrs"`
+			Items []onePasswordItem `json:"items"`
+		} `json:"vaults"`
+	} `json:"accounts"`
+}
+
+type onePasswordItem struct {
+	State        string `json:"state"`
+	CategoryUuid string `json:"categoryUuid"`
+	Overview     struct {
+		Title string `json:"title"`
+		Url   string `json:"url"`
+	} `json:"overview"`
+	Details struct {
+		LoginFields []struct {
+			Value       string `json:"value"`
+			Designation string `json:"designation"`
+		} `json:"loginFields"`
+		NotesPlain string `json:"notesPlain"`
+		Password   string `json:"password"`
+		Sections   []struct {
+			Fields []struct {
+				Title string                     `json:"title"`
+				Id    string                     `json:"id"`
+				Value map[string]json.RawMessage `json:"value"`
+			} `json:"fields"`
+		} `json:"sections"`
+		DocumentAttributes *struct {
+			FileName   string `json:"fileName"`
+			DocumentId string `json:"documentId"`
+		} `json:"documentAttributes"`
+	} `json:"details"`
+}
+
+// onePasswordParser parses 1Password 1PUX export, it is a zip with export.data json and attached files
+type onePasswordParser struct{}
+
+func (p *onePasswordParser) Parse(path string) ([]Entry, error) {
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open 1PUX archive: %v", err)
+	}
+	defer archive.Close()
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, file := range archive.File {
+		files[file.Name] = file
+	}
+	dataFile, ok := files[onePasswordData]
+	if !ok {
+		return nil, fmt.Errorf("1PUX archive has no %s", onePasswordData)
+	}
+	data, err := readZipFile(dataFile)
+	if err != nil {
+		return nil, err
+	}
+	var export onePasswordExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, fmt.Errorf("failed to parse 1PUX %s: %v", onePasswordData, err)
+	}
+
+	var entries []Entry
+	for _, account := range export.Accounts {
+		for _, vault := range account.Vaults {
+			for _, item := range vault.Items {
+				if item.State == "archived" {
+					continue
+				}
+				entry, err := item.entry(files)
+				if err != nil {
+					return nil, err
+				}
+				// vaults are imported as folders
+				entry.Folder = vault.Attrs.Name
+				entries = append(entries, entry)
+			}
+		}
+	}
+	return entries, nil
+}
+
+func (item *onePasswordItem) entry(files map[string]*zip.File) (Entry, error) {
+	title := item.Overview.Title
+	fields := make(map[string]string)
+	hidden := make(map[string]bool)
+	for _, section := range item.Details.Sections {
+		for _, field := range section.Fields {
+			kind, value := onePasswordValue(field.Value)
+			name := field.Title
+			if name == "" {
+				name = field.Id
+			}
+			fields[name] = value
+			hidden[name] = kind == "concealed" || kind == "totp"
+		}
+	}
+	// take known fields by title or id out of the custom fields
+	take := func(names ...string) string {
+		for _, section := range item.Details.Sections {
+			for _, field := range section.Fields {
+				for _, name := range names {
+					if field.Id == name || strings.EqualFold(field.Title, name) {
+						delete(fields, field.Title)
+						delete(fields, field.Id)
+						_, value := onePasswordValue(field.Value)
+						return value
+					}
+				}
+			}
+		}
+		return ""
+	}
+
+	var resource resources.ResourceClIFormatter
+	switch item.CategoryUuid {
+	case onePasswordLogin, onePasswordPassword:
+		var login, password string
+		for _, field := range item.Details.LoginFields {
+			switch field.Designation {
+			case "username":
+				login = field.Value
+			case "password":
+				password = field.Value
+			}
+		}
+		if password == "" {
+			password = item.Details.Password
+		}
+		resource = resources.NewLoginPassword(login, password, item.Overview.Url)
+	case onePasswordCard:
+		name, surname := splitName(take("cardholder"))
+		resource = resources.NewBankCard(take("ccnum"), onePasswordExpiry(take("expiry")), name, surname)
+		take("type")
+	case onePasswordIdentity:
+		identity := resources.NewIdentity(take("firstname"), take("lastname"), take("birthdate"), take("email"), take("defphone", "cellphone"))
+		if address := take("address"); address != "" {
+			identity.Addresses = []string{address}
+		}
+		resource = identity
+	case onePasswordDocument:
+		attributes := item.Details.DocumentAttributes
+		if attributes == nil {
+			return Entry{}, fmt.Errorf("1PUX document '%s' has no attributes", title)
+		}
+		file, ok := files["files/"+attributes.DocumentId+"__"+attributes.FileName]
+		if !ok {
+			return Entry{}, fmt.Errorf("1PUX archive has no file of document '%s'", title)
+		}
+		content, err := readZipFile(file)
+		if err != nil {
+			return Entry{}, err
+		}
+		entry := fileEntry(attributes.FileName, content, "")
+		entry.Description = title
+		return entry, nil
+	default:
+		// secure notes and not supported categories are imported as notes with custom fields
+		resource = resources.NewSecureNote(item.Details.NotesPlain)
+	}
+	holder := resource.(resources.CustomFieldsHolder)
+	if _, ok := resource.(*resources.SecureNote); !ok {
+		addField(holder, notesField, item.Details.NotesPlain, false)
+	}
+	addFields(holder, fields, hidden)
+	return Entry{Resource: resource, Description: title}, nil
+}
+
+// onePasswordValue returns the type and string value of 1PUX field value like {"concealed": "secret"}
+func onePasswordValue(value map[string]json.RawMessage) (string, string) {
+	kinds := make([]string, 0, len(value))
+	for kind := range value {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	for _, kind := range kinds {
+		raw := value[kind]
+		var str string
+		if err := json.Unmarshal(raw, &str); err == nil {
+			return kind, str
+		}
+		var number int64
+		if err := json.Unmarshal(raw, &number); err == nil {
+			if kind == "date" {
+				return kind, time.Unix(number, 0).UTC().Format(resources.CustomFieldDateLayout)
+			}
+			return kind, strconv.FormatInt(number, 10)
+		}
+		var object map[string]interface{}
+		if err := json.Unmarshal(raw, &object); err == nil {
+			var parts []string
+			for _, key := range []string{"email_address", "street", "city", "state", "zip", "country"} {
+				if part, ok := object[key].(string); ok && part != "" {
+					parts = append(parts, part)
+				}
+			}
+			return kind, strings.Join(parts, ", ")
+		}
+	}
+	return "", ""
+}
+
+// onePasswordExpiry formats monthYear value like 202703 as MM/YY
+func onePasswordExpiry(monthYear string) string {
+	if len(monthYear) != 6 {
+		return monthYear
+	}
+	return cardExpiry(monthYear[4:], monthYear[:4])
+}
+
+func readZipFile(file *zip.File) ([]byte, error) {
+	reader, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
@@ -0,0 +1,196 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ssh_agent.go
+
+// Package agent is a generated GoMock package.
+package agent
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	ssh "golang.org/x/crypto/ssh"
+	agent "golang.org/x/crypto/ssh/agent"
+)
+
+// MockSshAgent is a mock of SshAgent interface.
+type MockSshAgent struct {
+	ctrl     *gomock.Controller
+	recorder *MockSshAgentMockRecorder
+}
+
+// MockSshAgentMockRecorder is the mock recorder for MockSshAgent.
+type MockSshAgentMockRecorder struct {
+	mock *MockSshAgent
+}
+
+// NewMockSshAgent creates a new mock instance.
+func NewMockSshAgent(ctrl *gomock.Controller) *MockSshAgent {
+	mock := &MockSshAgent{ctrl: ctrl}
+	mock.recorder = &MockSshAgentMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSshAgent) EXPECT() *MockSshAgentMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *MockSshAgent) Add(key agent.AddedKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockSshAgentMockRecorder) Add(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockSshAgent)(nil).Add), key)
+}
+
+// Extension mocks base method.
+func (m *MockSshAgent) Extension(extensionType string, contents []byte) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Extension", extensionType, contents)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Extension indicates an expected call of Extension.
+func (mr *MockSshAgentMockRecorder) Extension(extensionType, contents interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Extension", reflect.TypeOf((*MockSshAgent)(nil).Extension), extensionType, contents)
+}
+
+// List mocks base method.
+func (m *MockSshAgent) List() ([]*agent.Key, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List")
+	ret0, _ := ret[0].([]*agent.Key)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockSshAgentMockRecorder) List() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockSshAgent)(nil).List))
+}
+
+// Lock mocks base method.
+func (m *MockSshAgent) Lock(passphrase []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lock", passphrase)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Lock indicates an expected call of Lock.
+func (mr *MockSshAgentMockRecorder) Lock(passphrase interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lock", reflect.TypeOf((*MockSshAgent)(nil).Lock), passphrase)
+}
+
+// Remove mocks base method.
+func (m *MockSshAgent) Remove(key ssh.PublicKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Remove", key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Remove indicates an expected call of Remove.
+func (mr *MockSshAgentMockRecorder) Remove(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Remove", reflect.TypeOf((*MockSshAgent)(nil).Remove), key)
+}
+
+// RemoveAll mocks base method.
+func (m *MockSshAgent) RemoveAll() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveAll")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveAll indicates an expected call of RemoveAll.
+func (mr *MockSshAgentMockRecorder) RemoveAll() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveAll", reflect.TypeOf((*MockSshAgent)(nil).RemoveAll))
+}
+
+// Serve mocks base method.
+func (m *MockSshAgent) Serve(ctx context.Context, socketPath string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Serve", ctx, socketPath)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Serve indicates an expected call of Serve.
+func (mr *MockSshAgentMockRecorder) Serve(ctx, socketPath interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Serve", reflect.TypeOf((*MockSshAgent)(nil).Serve), ctx, socketPath)
+}
+
+// Sign mocks base method.
+func (m *MockSshAgent) Sign(key ssh.PublicKey, data []byte) (*ssh.Signature, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Sign", key, data)
+	ret0, _ := ret[0].(*ssh.Signature)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Sign indicates an expected call of Sign.
+func (mr *MockSshAgentMockRecorder) Sign(key, data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sign", reflect.TypeOf((*MockSshAgent)(nil).Sign), key, data)
+}
+
+// SignWithFlags mocks base method.
+func (m *MockSshAgent) SignWithFlags(key ssh.PublicKey, data []byte, flags agent.SignatureFlags) (*ssh.Signature, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SignWithFlags", key, data, flags)
+	ret0, _ := ret[0].(*ssh.Signature)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SignWithFlags indicates an expected call of SignWithFlags.
+func (mr *MockSshAgentMockRecorder) SignWithFlags(key, data, flags interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignWithFlags", reflect.TypeOf((*MockSshAgent)(nil).SignWithFlags), key, data, flags)
+}
+
+// Signers mocks base method.
+func (m *MockSshAgent) Signers() ([]ssh.Signer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Signers")
+	ret0, _ := ret[0].([]ssh.Signer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Signers indicates an expected call of Signers.
+func (mr *MockSshAgentMockRecorder) Signers() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Signers", reflect.TypeOf((*MockSshAgent)(nil).Signers))
+}
+
+// Unlock mocks base method.
+func (m *MockSshAgent) Unlock(passphrase []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unlock", passphrase)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unlock indicates an expected call of Unlock.
+func (mr *MockSshAgentMockRecorder) Unlock(passphrase interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unlock", reflect.TypeOf((*MockSshAgent)(nil).Unlock), passphrase)
+}
@@ -0,0 +1,67 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: generator.go
+
+// Package generator is a generated GoMock package.
+package generator
+
+import (
+	reflect "reflect"
+	generator "ydx-goadv-gophkeeper/internal/client/generator"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockGenerator is a mock of Generator interface.
+type MockGenerator struct {
+	ctrl     *gomock.Controller
+	recorder *MockGeneratorMockRecorder
+}
+
+// MockGeneratorMockRecorder is the mock recorder for MockGenerator.
+type MockGeneratorMockRecorder struct {
+	mock *MockGenerator
+}
+
+// NewMockGenerator creates a new mock instance.
+func NewMockGenerator(ctrl *gomock.Controller) *MockGenerator {
+	mock := &MockGenerator{ctrl: ctrl}
+	mock.recorder = &MockGeneratorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGenerator) EXPECT() *MockGeneratorMockRecorder {
+	return m.recorder
+}
+
+// Passphrase mocks base method.
+func (m *MockGenerator) Passphrase(opts generator.PassphraseOptions) (string, float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Passphrase", opts)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(float64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Passphrase indicates an expected call of Passphrase.
+func (mr *MockGeneratorMockRecorder) Passphrase(opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Passphrase", reflect.TypeOf((*MockGenerator)(nil).Passphrase), opts)
+}
+
+// Password mocks base method.
+func (m *MockGenerator) Password(opts generator.PasswordOptions) (string, float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Password", opts)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(float64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Password indicates an expected call of Password.
+func (mr *MockGeneratorMockRecorder) Password(opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Password", reflect.TypeOf((*MockGenerator)(nil).Password), opts)
+}
@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: importer.go
+
+// Package importers is a generated GoMock package.
+package importers
+
+import (
+	reflect "reflect"
+	importers "ydx-goadv-gophkeeper/internal/client/importers"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockParser is a mock of Parser interface.
+type MockParser struct {
+	ctrl     *gomock.Controller
+	recorder *MockParserMockRecorder
+}
+
+// MockParserMockRecorder is the mock recorder for MockParser.
+type MockParserMockRecorder struct {
+	mock *MockParser
+}
+
+// NewMockParser creates a new mock instance.
+func NewMockParser(ctrl *gomock.Controller) *MockParser {
+	mock := &MockParser{ctrl: ctrl}
+	mock.recorder = &MockParserMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockParser) EXPECT() *MockParserMockRecorder {
+	return m.recorder
+}
+
+// Parse mocks base method.
+func (m *MockParser) Parse(path string) ([]importers.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Parse", path)
+	ret0, _ := ret[0].([]importers.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Parse indicates an expected call of Parse.
+func (mr *MockParserMockRecorder) Parse(path interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Parse", reflect.TypeOf((*MockParser)(nil).Parse), path)
+}
@@ -0,0 +1,68 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: backup_service.go
+
+// Package services is a generated GoMock package.
+package services
+
+import (
+	context "context"
+	io "io"
+	reflect "reflect"
+	model "ydx-goadv-gophkeeper/internal/client/model"
+	services "ydx-goadv-gophkeeper/internal/client/services"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockBackupService is a mock of BackupService interface.
+type MockBackupService struct {
+	ctrl     *gomock.Controller
+	recorder *MockBackupServiceMockRecorder
+}
+
+// MockBackupServiceMockRecorder is the mock recorder for MockBackupService.
+type MockBackupServiceMockRecorder struct {
+	mock *MockBackupService
+}
+
+// NewMockBackupService creates a new mock instance.
+func NewMockBackupService(ctrl *gomock.Controller) *MockBackupService {
+	mock := &MockBackupService{ctrl: ctrl}
+	mock.recorder = &MockBackupServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBackupService) EXPECT() *MockBackupServiceMockRecorder {
+	return m.recorder
+}
+
+// Export mocks base method.
+func (m *MockBackupService) Export(ctx context.Context, out io.Writer, passphrase []byte) (*model.BackupManifest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", ctx, out, passphrase)
+	ret0, _ := ret[0].(*model.BackupManifest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockBackupServiceMockRecorder) Export(ctx, out, passphrase interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockBackupService)(nil).Export), ctx, out, passphrase)
+}
+
+// Restore mocks base method.
+func (m *MockBackupService) Restore(ctx context.Context, in io.Reader, passphrase []byte, onConflict services.ConflictPolicy) (*model.RestoreSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, in, passphrase, onConflict)
+	ret0, _ := ret[0].(*model.RestoreSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockBackupServiceMockRecorder) Restore(ctx, in, passphrase, onConflict interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockBackupService)(nil).Restore), ctx, in, passphrase, onConflict)
+}
@@ -0,0 +1,63 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: breach_checker.go
+
+// Package services is a generated GoMock package.
+package services
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockBreachChecker is a mock of BreachChecker interface.
+type MockBreachChecker struct {
+	ctrl     *gomock.Controller
+	recorder *MockBreachCheckerMockRecorder
+}
+
+// MockBreachCheckerMockRecorder is the mock recorder for MockBreachChecker.
+type MockBreachCheckerMockRecorder struct {
+	mock *MockBreachChecker
+}
+
+// NewMockBreachChecker creates a new mock instance.
+func NewMockBreachChecker(ctrl *gomock.Controller) *MockBreachChecker {
+	mock := &MockBreachChecker{ctrl: ctrl}
+	mock.recorder = &MockBreachCheckerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBreachChecker) EXPECT() *MockBreachCheckerMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method.
+func (m *MockBreachChecker) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockBreachCheckerMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockBreachChecker)(nil).Close))
+}
+
+// Count mocks base method.
+func (m *MockBreachChecker) Count(password string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Count", password)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Count indicates an expected call of Count.
+func (mr *MockBreachCheckerMockRecorder) Count(password interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockBreachChecker)(nil).Count), password)
+}
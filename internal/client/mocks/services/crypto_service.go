@@ -33,6 +33,21 @@ func (m *MockCryptService) EXPECT() *MockCryptServiceMockRecorder {
 	return m.recorder
 }
 
+// BlindIndex mocks base method.
+func (m *MockCryptService) BlindIndex(data []byte) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlindIndex", data)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BlindIndex indicates an expected call of BlindIndex.
+func (mr *MockCryptServiceMockRecorder) BlindIndex(data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlindIndex", reflect.TypeOf((*MockCryptService)(nil).BlindIndex), data)
+}
+
 // Decrypt mocks base method.
 func (m *MockCryptService) Decrypt(data []byte) ([]byte, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,77 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: label_service.go
+
+// Package services is a generated GoMock package.
+package services
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockLabelService is a mock of LabelService interface.
+type MockLabelService struct {
+	ctrl     *gomock.Controller
+	recorder *MockLabelServiceMockRecorder
+}
+
+// MockLabelServiceMockRecorder is the mock recorder for MockLabelService.
+type MockLabelServiceMockRecorder struct {
+	mock *MockLabelService
+}
+
+// NewMockLabelService creates a new mock instance.
+func NewMockLabelService(ctrl *gomock.Controller) *MockLabelService {
+	mock := &MockLabelService{ctrl: ctrl}
+	mock.recorder = &MockLabelServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLabelService) EXPECT() *MockLabelServiceMockRecorder {
+	return m.recorder
+}
+
+// Move mocks base method.
+func (m *MockLabelService) Move(ctx context.Context, folder string, ids []int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Move", ctx, folder, ids)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Move indicates an expected call of Move.
+func (mr *MockLabelServiceMockRecorder) Move(ctx, folder, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Move", reflect.TypeOf((*MockLabelService)(nil).Move), ctx, folder, ids)
+}
+
+// Tag mocks base method.
+func (m *MockLabelService) Tag(ctx context.Context, tag string, ids []int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Tag", ctx, tag, ids)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Tag indicates an expected call of Tag.
+func (mr *MockLabelServiceMockRecorder) Tag(ctx, tag, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Tag", reflect.TypeOf((*MockLabelService)(nil).Tag), ctx, tag, ids)
+}
+
+// Untag mocks base method.
+func (m *MockLabelService) Untag(ctx context.Context, tag string, ids []int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Untag", ctx, tag, ids)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Untag indicates an expected call of Untag.
+func (mr *MockLabelServiceMockRecorder) Untag(ctx, tag, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Untag", reflect.TypeOf((*MockLabelService)(nil).Untag), ctx, tag, ids)
+}
@@ -0,0 +1,67 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: report_service.go
+
+// Package services is a generated GoMock package.
+package services
+
+import (
+	context "context"
+	reflect "reflect"
+	model "ydx-goadv-gophkeeper/internal/client/model"
+	services "ydx-goadv-gophkeeper/internal/client/services"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockReportService is a mock of ReportService interface.
+type MockReportService struct {
+	ctrl     *gomock.Controller
+	recorder *MockReportServiceMockRecorder
+}
+
+// MockReportServiceMockRecorder is the mock recorder for MockReportService.
+type MockReportServiceMockRecorder struct {
+	mock *MockReportService
+}
+
+// NewMockReportService creates a new mock instance.
+func NewMockReportService(ctrl *gomock.Controller) *MockReportService {
+	mock := &MockReportService{ctrl: ctrl}
+	mock.recorder = &MockReportServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReportService) EXPECT() *MockReportServiceMockRecorder {
+	return m.recorder
+}
+
+// Breaches mocks base method.
+func (m *MockReportService) Breaches(ctx context.Context, hibpFile string) (*model.Report, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Breaches", ctx, hibpFile)
+	ret0, _ := ret[0].(*model.Report)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Breaches indicates an expected call of Breaches.
+func (mr *MockReportServiceMockRecorder) Breaches(ctx, hibpFile interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Breaches", reflect.TypeOf((*MockReportService)(nil).Breaches), ctx, hibpFile)
+}
+
+// Build mocks base method.
+func (m *MockReportService) Build(ctx context.Context, opts services.ReportOptions) (*model.Report, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Build", ctx, opts)
+	ret0, _ := ret[0].(*model.Report)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Build indicates an expected call of Build.
+func (mr *MockReportServiceMockRecorder) Build(ctx, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Build", reflect.TypeOf((*MockReportService)(nil).Build), ctx, opts)
+}
@@ -6,10 +6,13 @@ package services
 
 import (
 	context "context"
+	io "io"
 	reflect "reflect"
+	model "ydx-goadv-gophkeeper/internal/client/model"
 	resources "ydx-goadv-gophkeeper/internal/client/model/resources"
-	model "ydx-goadv-gophkeeper/internal/server/model"
+	model0 "ydx-goadv-gophkeeper/internal/server/model"
 	enum "ydx-goadv-gophkeeper/pkg/model/enum"
+	pb "ydx-goadv-gophkeeper/pkg/pb"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -37,6 +40,21 @@ func (m *MockResourceService) EXPECT() *MockResourceServiceMockRecorder {
 	return m.recorder
 }
 
+// Batch mocks base method.
+func (m *MockResourceService) Batch(ctx context.Context, operations []model.BatchOperation, atomic bool) ([]model.BatchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Batch", ctx, operations, atomic)
+	ret0, _ := ret[0].([]model.BatchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Batch indicates an expected call of Batch.
+func (mr *MockResourceServiceMockRecorder) Batch(ctx, operations, atomic interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Batch", reflect.TypeOf((*MockResourceService)(nil).Batch), ctx, operations, atomic)
+}
+
 // Delete mocks base method.
 func (m *MockResourceService) Delete(ctx context.Context, resId int32) error {
 	m.ctrl.T.Helper()
@@ -51,6 +69,21 @@ func (mr *MockResourceServiceMockRecorder) Delete(ctx, resId interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockResourceService)(nil).Delete), ctx, resId)
 }
 
+// FileVersions mocks base method.
+func (m *MockResourceService) FileVersions(ctx context.Context, resId int32) (model.FileVersions, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FileVersions", ctx, resId)
+	ret0, _ := ret[0].(model.FileVersions)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FileVersions indicates an expected call of FileVersions.
+func (mr *MockResourceServiceMockRecorder) FileVersions(ctx, resId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FileVersions", reflect.TypeOf((*MockResourceService)(nil).FileVersions), ctx, resId)
+}
+
 // Get mocks base method.
 func (m *MockResourceService) Get(ctx context.Context, resId int32) (*resources.Info, error) {
 	m.ctrl.T.Helper()
@@ -67,10 +100,10 @@ func (mr *MockResourceServiceMockRecorder) Get(ctx, resId interface{}) *gomock.C
 }
 
 // GetDescriptions mocks base method.
-func (m *MockResourceService) GetDescriptions(ctx context.Context, resType enum.ResourceType) ([]*model.ResourceDescription, error) {
+func (m *MockResourceService) GetDescriptions(ctx context.Context, resType enum.ResourceType) ([]*model0.ResourceDescription, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetDescriptions", ctx, resType)
-	ret0, _ := ret[0].([]*model.ResourceDescription)
+	ret0, _ := ret[0].([]*model0.ResourceDescription)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -81,19 +114,75 @@ func (mr *MockResourceServiceMockRecorder) GetDescriptions(ctx, resType interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDescriptions", reflect.TypeOf((*MockResourceService)(nil).GetDescriptions), ctx, resType)
 }
 
-// GetFile mocks base method.
-func (m *MockResourceService) GetFile(ctx context.Context, resId int32) (string, error) {
+// GetFileTo mocks base method.
+func (m *MockResourceService) GetFileTo(ctx context.Context, resId int32, path string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFileTo", ctx, resId, path)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetFileTo indicates an expected call of GetFileTo.
+func (mr *MockResourceServiceMockRecorder) GetFileTo(ctx, resId, path interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFileTo", reflect.TypeOf((*MockResourceService)(nil).GetFileTo), ctx, resId, path)
+}
+
+// GetFileVersionTo mocks base method.
+func (m *MockResourceService) GetFileVersionTo(ctx context.Context, resId, version int32, path string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFileVersionTo", ctx, resId, version, path)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetFileVersionTo indicates an expected call of GetFileVersionTo.
+func (mr *MockResourceServiceMockRecorder) GetFileVersionTo(ctx, resId, version, path interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFileVersionTo", reflect.TypeOf((*MockResourceService)(nil).GetFileVersionTo), ctx, resId, version, path)
+}
+
+// ListDescriptions mocks base method.
+func (m *MockResourceService) ListDescriptions(ctx context.Context, query model.ListQuery) (*model.DescriptionsPage, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetFile", ctx, resId)
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "ListDescriptions", ctx, query)
+	ret0, _ := ret[0].(*model.DescriptionsPage)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetFile indicates an expected call of GetFile.
-func (mr *MockResourceServiceMockRecorder) GetFile(ctx, resId interface{}) *gomock.Call {
+// ListDescriptions indicates an expected call of ListDescriptions.
+func (mr *MockResourceServiceMockRecorder) ListDescriptions(ctx, query interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFile", reflect.TypeOf((*MockResourceService)(nil).GetFile), ctx, resId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDescriptions", reflect.TypeOf((*MockResourceService)(nil).ListDescriptions), ctx, query)
+}
+
+// RenameFile mocks base method.
+func (m *MockResourceService) RenameFile(ctx context.Context, resId int32, name string, meta []byte, fields []resources.CustomField) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameFile", ctx, resId, name, meta, fields)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RenameFile indicates an expected call of RenameFile.
+func (mr *MockResourceServiceMockRecorder) RenameFile(ctx, resId, name, meta, fields interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameFile", reflect.TypeOf((*MockResourceService)(nil).RenameFile), ctx, resId, name, meta, fields)
+}
+
+// ReplaceFile mocks base method.
+func (m *MockResourceService) ReplaceFile(ctx context.Context, resId int32, path string, meta []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceFile", ctx, resId, path, meta)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceFile indicates an expected call of ReplaceFile.
+func (mr *MockResourceServiceMockRecorder) ReplaceFile(ctx, resId, path, meta interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceFile", reflect.TypeOf((*MockResourceService)(nil).ReplaceFile), ctx, resId, path, meta)
 }
 
 // Save mocks base method.
@@ -112,18 +201,18 @@ func (mr *MockResourceServiceMockRecorder) Save(ctx, resType, data, meta interfa
 }
 
 // SaveFile mocks base method.
-func (m *MockResourceService) SaveFile(ctx context.Context, path string, meta []byte) (int32, error) {
+func (m *MockResourceService) SaveFile(ctx context.Context, path string, meta []byte, fields []resources.CustomField) (int32, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SaveFile", ctx, path, meta)
+	ret := m.ctrl.Call(m, "SaveFile", ctx, path, meta, fields)
 	ret0, _ := ret[0].(int32)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // SaveFile indicates an expected call of SaveFile.
-func (mr *MockResourceServiceMockRecorder) SaveFile(ctx, path, meta interface{}) *gomock.Call {
+func (mr *MockResourceServiceMockRecorder) SaveFile(ctx, path, meta, fields interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveFile", reflect.TypeOf((*MockResourceService)(nil).SaveFile), ctx, path, meta)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveFile", reflect.TypeOf((*MockResourceService)(nil).SaveFile), ctx, path, meta, fields)
 }
 
 // Update mocks base method.
@@ -139,3 +228,98 @@ func (mr *MockResourceServiceMockRecorder) Update(ctx, resId, resType, data, met
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockResourceService)(nil).Update), ctx, resId, resType, data, meta)
 }
+
+// UpdateLabels mocks base method.
+func (m *MockResourceService) UpdateLabels(ctx context.Context, labels map[int32]resources.Labels) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLabels", ctx, labels)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLabels indicates an expected call of UpdateLabels.
+func (mr *MockResourceServiceMockRecorder) UpdateLabels(ctx, labels interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLabels", reflect.TypeOf((*MockResourceService)(nil).UpdateLabels), ctx, labels)
+}
+
+// Usage mocks base method.
+func (m *MockResourceService) Usage(ctx context.Context) (*model.Usage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Usage", ctx)
+	ret0, _ := ret[0].(*model.Usage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Usage indicates an expected call of Usage.
+func (mr *MockResourceServiceMockRecorder) Usage(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Usage", reflect.TypeOf((*MockResourceService)(nil).Usage), ctx)
+}
+
+// WriteFile mocks base method.
+func (m *MockResourceService) WriteFile(ctx context.Context, resId int32, w io.Writer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WriteFile", ctx, resId, w)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WriteFile indicates an expected call of WriteFile.
+func (mr *MockResourceServiceMockRecorder) WriteFile(ctx, resId, w interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteFile", reflect.TypeOf((*MockResourceService)(nil).WriteFile), ctx, resId, w)
+}
+
+// WriteFileVersion mocks base method.
+func (m *MockResourceService) WriteFileVersion(ctx context.Context, resId, version int32, w io.Writer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WriteFileVersion", ctx, resId, version, w)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WriteFileVersion indicates an expected call of WriteFileVersion.
+func (mr *MockResourceServiceMockRecorder) WriteFileVersion(ctx, resId, version, w interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteFileVersion", reflect.TypeOf((*MockResourceService)(nil).WriteFileVersion), ctx, resId, version, w)
+}
+
+// MockfileChunkReceiver is a mock of fileChunkReceiver interface.
+type MockfileChunkReceiver struct {
+	ctrl     *gomock.Controller
+	recorder *MockfileChunkReceiverMockRecorder
+}
+
+// MockfileChunkReceiverMockRecorder is the mock recorder for MockfileChunkReceiver.
+type MockfileChunkReceiverMockRecorder struct {
+	mock *MockfileChunkReceiver
+}
+
+// NewMockfileChunkReceiver creates a new mock instance.
+func NewMockfileChunkReceiver(ctrl *gomock.Controller) *MockfileChunkReceiver {
+	mock := &MockfileChunkReceiver{ctrl: ctrl}
+	mock.recorder = &MockfileChunkReceiverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockfileChunkReceiver) EXPECT() *MockfileChunkReceiverMockRecorder {
+	return m.recorder
+}
+
+// Recv mocks base method.
+func (m *MockfileChunkReceiver) Recv() (*pb.FileChunk, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Recv")
+	ret0, _ := ret[0].(*pb.FileChunk)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recv indicates an expected call of Recv.
+func (mr *MockfileChunkReceiverMockRecorder) Recv() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recv", reflect.TypeOf((*MockfileChunkReceiver)(nil).Recv))
+}
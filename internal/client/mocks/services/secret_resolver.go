@@ -0,0 +1,65 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: secret_resolver.go
+
+// Package services is a generated GoMock package.
+package services
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockSecretResolver is a mock of SecretResolver interface.
+type MockSecretResolver struct {
+	ctrl     *gomock.Controller
+	recorder *MockSecretResolverMockRecorder
+}
+
+// MockSecretResolverMockRecorder is the mock recorder for MockSecretResolver.
+type MockSecretResolverMockRecorder struct {
+	mock *MockSecretResolver
+}
+
+// NewMockSecretResolver creates a new mock instance.
+func NewMockSecretResolver(ctrl *gomock.Controller) *MockSecretResolver {
+	mock := &MockSecretResolver{ctrl: ctrl}
+	mock.recorder = &MockSecretResolverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSecretResolver) EXPECT() *MockSecretResolverMockRecorder {
+	return m.recorder
+}
+
+// Resolve mocks base method.
+func (m *MockSecretResolver) Resolve(ctx context.Context, ref string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resolve", ctx, ref)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Resolve indicates an expected call of Resolve.
+func (mr *MockSecretResolverMockRecorder) Resolve(ctx, ref interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resolve", reflect.TypeOf((*MockSecretResolver)(nil).Resolve), ctx, ref)
+}
+
+// ResolveField mocks base method.
+func (m *MockSecretResolver) ResolveField(ctx context.Context, resId int32, field string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveField", ctx, resId, field)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveField indicates an expected call of ResolveField.
+func (mr *MockSecretResolverMockRecorder) ResolveField(ctx, resId, field interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveField", reflect.TypeOf((*MockSecretResolver)(nil).ResolveField), ctx, resId, field)
+}
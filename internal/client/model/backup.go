@@ -0,0 +1,49 @@
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"ydx-goadv-gophkeeper/internal/client/model/resources"
+)
+
+const BackupVersion = 1
+
+// BackupManifest is written at the end of the archive and lists sha256 of every archive entry
+type BackupManifest struct {
+	Version   int               `json:"version"`
+	CreatedAt time.Time         `json:"createdAt"`
+	Resources []BackupResource  `json:"resources"`
+	Checksums map[string]string `json:"checksums"`
+}
+
+type BackupResource struct {
+	Id          int32     `json:"id"`
+	Type        string    `json:"type"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+	// DataPath is the resource json, File resource json is the file description
+	DataPath string `json:"dataPath"`
+	// FilePath is the File resource content
+	FilePath string           `json:"filePath,omitempty"`
+	Labels   resources.Labels `json:"labels"`
+}
+
+type RestoreSummary struct {
+	Restored    int
+	Overwritten int
+	Skipped     int
+	Unchanged   int
+	// Warnings are not fatal restore issues, i.e. broken links between resources
+	Warnings []string
+}
+
+func (s *RestoreSummary) String() string {
+	result := fmt.Sprintf("restored: %d, overwritten: %d, skipped conflicts: %d, unchanged: %d",
+		s.Restored, s.Overwritten, s.Skipped, s.Unchanged)
+	for _, warning := range s.Warnings {
+		result += "\nwarning: " + warning
+	}
+	return result
+}
@@ -0,0 +1,26 @@
+package model
+
+import "ydx-goadv-gophkeeper/pkg/model/enum"
+
+type BatchKind uint8
+
+const (
+	BatchCreate BatchKind = iota
+	BatchUpdate
+	BatchDelete
+)
+
+// BatchOperation changes resource, data is encrypted before sending
+type BatchOperation struct {
+	Kind BatchKind
+	// Id is the id of updated or deleted resource
+	Id   int32
+	Type enum.ResourceType
+	Data []byte
+	Meta []byte
+}
+
+type BatchResult struct {
+	Id  int32
+	Err error
+}
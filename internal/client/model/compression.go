@@ -0,0 +1,11 @@
+package model
+
+// CompressionPolicy chooses resources compressed before encryption
+type CompressionPolicy struct {
+	// Types are aliases of compressed resource types, e.g. nt or fl
+	Types []string `json:"types"`
+	// MinSize is the size of payloads too small to gain from compression
+	MinSize int `json:"min_size"`
+	// SkipExtensions are extensions of already compressed files in addition to the well-known ones
+	SkipExtensions []string `json:"skip_extensions"`
+}
@@ -0,0 +1,33 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// FileVersion is the previous content of the file replaced by the newer one
+type FileVersion struct {
+	Version    int32
+	Name       string
+	Size       int64
+	ReplacedAt time.Time
+}
+
+// FileVersions are ordered from the latest one
+type FileVersions []FileVersion
+
+func (v FileVersions) String() string {
+	if len(v) == 0 {
+		return "there are no previous versions"
+	}
+	var builder strings.Builder
+	writer := tabwriter.NewWriter(&builder, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "VERSION\tNAME\tSIZE\tREPLACED")
+	for _, version := range v {
+		fmt.Fprintf(writer, "%d\t%s\t%s\t%s\n", version.Version, version.Name, FormatBytes(version.Size), version.ReplacedAt.Local().Format("2006-01-02 15:04:05"))
+	}
+	writer.Flush()
+	return strings.TrimSuffix(builder.String(), "\n")
+}
@@ -0,0 +1,86 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+type ImportStats struct {
+	Added      int
+	Duplicates int
+	Invalid    int
+	Failed     int
+}
+
+type ImportFailure struct {
+	Type        string
+	Description string
+	Reason      string
+}
+
+// ImportSummary counts imported resources by type alias
+type ImportSummary struct {
+	DryRun   bool
+	Stats    map[string]*ImportStats
+	Failures []ImportFailure
+}
+
+func NewImportSummary(dryRun bool) *ImportSummary {
+	return &ImportSummary{DryRun: dryRun, Stats: make(map[string]*ImportStats)}
+}
+
+func (s *ImportSummary) Stat(alias string) *ImportStats {
+	stat, ok := s.Stats[alias]
+	if !ok {
+		stat = &ImportStats{}
+		s.Stats[alias] = stat
+	}
+	return stat
+}
+
+func (s *ImportSummary) Fail(alias string, description string, reason string) {
+	s.Stat(alias).Invalid++
+	s.Failures = append(s.Failures, ImportFailure{Type: alias, Description: description, Reason: reason})
+}
+
+// SaveFailed records valid entry which is not saved
+func (s *ImportSummary) SaveFailed(alias string, description string, reason string) {
+	s.Stat(alias).Failed++
+	s.Failures = append(s.Failures, ImportFailure{Type: alias, Description: description, Reason: reason})
+}
+
+func (s *ImportSummary) String() string {
+	added := "ADDED"
+	if s.DryRun {
+		added = "TO IMPORT"
+	}
+	aliases := make([]string, 0, len(s.Stats))
+	for alias := range s.Stats {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+
+	var builder strings.Builder
+	writer := tabwriter.NewWriter(&builder, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(writer, "TYPE\t%s\tDUPLICATES\tINVALID\tFAILED\n", added)
+	var total ImportStats
+	for _, alias := range aliases {
+		stat := s.Stats[alias]
+		fmt.Fprintf(writer, "%s\t%d\t%d\t%d\t%d\n", alias, stat.Added, stat.Duplicates, stat.Invalid, stat.Failed)
+		total.Added += stat.Added
+		total.Duplicates += stat.Duplicates
+		total.Invalid += stat.Invalid
+		total.Failed += stat.Failed
+	}
+	fmt.Fprintf(writer, "total\t%d\t%d\t%d\t%d\n", total.Added, total.Duplicates, total.Invalid, total.Failed)
+	writer.Flush()
+	for _, failure := range s.Failures {
+		builder.WriteString(fmt.Sprintf("%s '%s': %s\n", failure.Type, failure.Description, failure.Reason))
+	}
+	if s.DryRun {
+		builder.WriteString("dry run, nothing is saved")
+	}
+	return strings.TrimSuffix(builder.String(), "\n")
+}
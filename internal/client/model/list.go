@@ -0,0 +1,37 @@
+package model
+
+import (
+	"time"
+
+	srvmodel "ydx-goadv-gophkeeper/internal/server/model"
+	"ydx-goadv-gophkeeper/pkg/model/enum"
+)
+
+type ListOrder string
+
+const (
+	OrderById      ListOrder = "id"
+	OrderByCreated ListOrder = "created"
+	OrderByUpdated ListOrder = "updated"
+)
+
+// ListQuery filters resource descriptions on server, zero Limit lists all of them
+type ListQuery struct {
+	Types        []enum.ResourceType
+	Ids          []int32
+	UpdatedSince time.Time
+	// Folder lists resources of the folder and its subfolders, empty is all folders
+	Folder     string
+	Tags       []string
+	Order      ListOrder
+	Descending bool
+	Limit      int
+	// Cursor continues listing after the previous page
+	Cursor string
+}
+
+type DescriptionsPage struct {
+	Descriptions []*srvmodel.ResourceDescription
+	// Next is the cursor of the next page, it is empty on the last page
+	Next string
+}
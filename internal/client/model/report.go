@@ -0,0 +1,58 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+)
+
+type ReportIssue string
+
+const (
+	IssueReused ReportIssue = "reused"
+	IssueWeak   ReportIssue = "weak"
+	IssueOld    ReportIssue = "old"
+	// IssueAgeUnknown is the password saved before update time was tracked, so it may be old
+	IssueAgeUnknown ReportIssue = "age unknown"
+	IssueExpiring   ReportIssue = "expiring"
+	IssueExpired    ReportIssue = "expired"
+	IssueBreached   ReportIssue = "breached"
+)
+
+type ReportEntry struct {
+	Id          int32       `json:"id"`
+	Type        string      `json:"type"`
+	Description string      `json:"description"`
+	Issue       ReportIssue `json:"issue"`
+	Details     string      `json:"details"`
+}
+
+// Report is the vault health report
+type Report struct {
+	Checked int           `json:"checked"`
+	Entries []ReportEntry `json:"entries"`
+}
+
+func (r *Report) Table() string {
+	if len(r.Entries) == 0 {
+		return fmt.Sprintf("checked %d resources, no issues found", r.Checked)
+	}
+	var builder strings.Builder
+	writer := tabwriter.NewWriter(&builder, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "ID\tTYPE\tISSUE\tDESCRIPTION\tDETAILS")
+	for _, entry := range r.Entries {
+		fmt.Fprintf(writer, "%d\t%s\t%s\t%s\t%s\n", entry.Id, entry.Type, entry.Issue, entry.Description, entry.Details)
+	}
+	writer.Flush()
+	builder.WriteString(fmt.Sprintf("checked %d resources, %d issues found", r.Checked, len(r.Entries)))
+	return builder.String()
+}
+
+func (r *Report) JSON() (string, error) {
+	if r.Entries == nil {
+		r.Entries = []ReportEntry{}
+	}
+	data, err := json.MarshalIndent(r, "", "  ")
+	return string(data), err
+}
@@ -11,6 +11,8 @@ type BankCard struct {
 	ExpireAt string `json:"expireAt"`
 	Name     string `json:"name,omitempty"`
 	Surname  string `json:"surname,omitempty"`
+	Brand    string `json:"brand,omitempty"`
+	CustomFields
 }
 
 func NewBankCard(number string, expireAt string, name string, surname string) *BankCard {
@@ -18,11 +20,16 @@ func NewBankCard(number string, expireAt string, name string, surname string) *B
 }
 
 func (b *BankCard) Format(description string) string {
-	return fmt.Sprintf("number: %v\nexpireAt: %v\nname: %v\nsurname: %v\ndescription: %v",
+	var brand string
+	if b.Brand != "" {
+		brand = fmt.Sprintf("brand: %v\n", b.Brand)
+	}
+	return fmt.Sprintf("number: %v\nexpireAt: %v\nname: %v\nsurname: %v\n%sdescription: %v",
 		b.Number,
 		b.ExpireAt,
 		b.Name,
 		b.Surname,
+		brand,
 		description,
 	)
 }
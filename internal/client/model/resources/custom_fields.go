@@ -0,0 +1,118 @@
+package resources
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type CustomFieldType string
+
+const (
+	TextField   CustomFieldType = "text"
+	HiddenField CustomFieldType = "hidden"
+	UrlField    CustomFieldType = "url"
+	EmailField  CustomFieldType = "email"
+	DateField   CustomFieldType = "date"
+
+	CustomFieldDateLayout = "2006-01-02"
+	hiddenMask            = "*****"
+)
+
+var CustomFieldTypes = []CustomFieldType{TextField, HiddenField, UrlField, EmailField, DateField}
+
+type CustomField struct {
+	Name  string          `json:"name"`
+	Type  CustomFieldType `json:"type"`
+	Value string          `json:"value,omitempty"`
+}
+
+// NewCustomField checks value matches the field type
+func NewCustomField(name string, fieldType CustomFieldType, value string) (CustomField, error) {
+	field := CustomField{Name: strings.TrimSpace(name), Type: fieldType, Value: value}
+	if field.Name == "" {
+		return field, fmt.Errorf("custom field name is empty")
+	}
+	switch fieldType {
+	case TextField, HiddenField:
+	case UrlField:
+		if u, err := url.Parse(value); err != nil || u.Scheme == "" || u.Host == "" {
+			return field, fmt.Errorf("custom field '%s' is not a valid url: '%s'", name, value)
+		}
+	case EmailField:
+		if _, err := mail.ParseAddress(value); err != nil {
+			return field, fmt.Errorf("custom field '%s' is not a valid email: '%s'", name, value)
+		}
+	case DateField:
+		if _, err := time.Parse(CustomFieldDateLayout, value); err != nil {
+			return field, fmt.Errorf("custom field '%s' is not a valid date in format YYYY-MM-DD: '%s'", name, value)
+		}
+	default:
+		return field, fmt.Errorf("custom field type '%s' is not supported", fieldType)
+	}
+	return field, nil
+}
+
+// CustomFields are embedded into every resource
+type CustomFields struct {
+	Fields []CustomField `json:"fields,omitempty"`
+}
+
+type CustomFieldsHolder interface {
+	Custom() *CustomFields
+}
+
+func (c *CustomFields) Custom() *CustomFields {
+	return c
+}
+
+// Get returns custom field by name
+func (c *CustomFields) Get(name string) (CustomField, bool) {
+	for _, field := range c.Fields {
+		if strings.EqualFold(field.Name, name) {
+			return field, true
+		}
+	}
+	return CustomField{}, false
+}
+
+// Set replaces field with the same name or appends a new one
+func (c *CustomFields) Set(field CustomField) {
+	for i := range c.Fields {
+		if strings.EqualFold(c.Fields[i].Name, field.Name) {
+			c.Fields[i] = field
+			return
+		}
+	}
+	c.Fields = append(c.Fields, field)
+}
+
+// Remove deletes custom field by name
+func (c *CustomFields) Remove(name string) bool {
+	for i := range c.Fields {
+		if strings.EqualFold(c.Fields[i].Name, name) {
+			c.Fields = append(c.Fields[:i], c.Fields[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Format prints custom fields, hidden values are masked unless reveal is set
+func (c *CustomFields) Format(reveal bool) string {
+	if len(c.Fields) == 0 {
+		return ""
+	}
+	var builder strings.Builder
+	builder.WriteString("fields:")
+	for _, field := range c.Fields {
+		value := field.Value
+		if field.Type == HiddenField && !reveal {
+			value = hiddenMask
+		}
+		builder.WriteString(fmt.Sprintf("\n  %s (%s): %s", field.Name, field.Type, value))
+	}
+	return builder.String()
+}
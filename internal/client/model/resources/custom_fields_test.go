@@ -0,0 +1,50 @@
+package resources
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCustomField(t *testing.T) {
+	tests := []struct {
+		name      string
+		fieldType CustomFieldType
+		value     string
+		wantErr   bool
+	}{
+		{name: "text", fieldType: TextField, value: "anything"},
+		{name: "url", fieldType: UrlField, value: "https://example.com/login"},
+		{name: "bad url", fieldType: UrlField, value: "example", wantErr: true},
+		{name: "email", fieldType: EmailField, value: "john@example.com"},
+		{name: "bad email", fieldType: EmailField, value: "john", wantErr: true},
+		{name: "date", fieldType: DateField, value: "2024-02-29"},
+		{name: "bad date", fieldType: DateField, value: "29.02.2024", wantErr: true},
+		{name: "unknown", fieldType: "number", value: "1", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := NewCustomField(test.name, test.fieldType, test.value)
+			assert.Equal(t, test.wantErr, err != nil)
+		})
+	}
+}
+
+func TestInfo_FormatCustomFields(t *testing.T) {
+	card := NewBankCard("4111111111111111", "01/30", "John", "Doe")
+	card.Set(CustomField{Name: "cvv", Type: HiddenField, Value: "123"})
+	card.Set(CustomField{Name: "bank", Type: TextField, Value: "ACME"})
+	info := &Info{Resource: card, Meta: []byte("main")}
+
+	expected := "number: 4111111111111111\nexpireAt: 01/30\nname: John\nsurname: Doe\ndescription: main\n" +
+		"fields:\n  cvv (hidden): *****\n  bank (text): ACME"
+	assert.Equal(t, expected, info.Format())
+
+	info.Reveal = true
+	assert.Contains(t, info.Format(), "cvv (hidden): 123")
+
+	cvv, err := info.Field("cvv")
+	assert.NoError(t, err)
+	assert.Equal(t, "123", cvv)
+}
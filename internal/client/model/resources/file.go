@@ -2,14 +2,25 @@ package resources
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 
 	"ydx-goadv-gophkeeper/pkg/model/enum"
 )
 
+// File is the description of the file content stored separately
 type File struct {
-	Name      string
-	Extension string
-	Size      int64
+	Name      string `json:"name,omitempty"`
+	Extension string `json:"extension,omitempty"`
+	Size      int64  `json:"size,omitempty"`
+	// Key is the encrypted key of the file content, files without key are encrypted by the vault key
+	Key []byte `json:"key,omitempty"`
+	// Digest is SHA-256 of the encrypted file
+	Digest []byte `json:"digest,omitempty"`
+	// Compression is the algorithm of the content compressed before encryption
+	Compression string `json:"compression,omitempty"`
+	CustomFields
 }
 
 func (p *File) Format(description string) string {
@@ -19,3 +30,16 @@ func (p *File) Format(description string) string {
 func (p *File) Type() enum.ResourceType {
 	return enum.File
 }
+
+// Destination returns path to save the file to: dest itself, the file name in dest directory
+// or in the working directory if dest is empty. Directories of the stored name are dropped.
+func (p *File) Destination(dest string) string {
+	name := filepath.Base(filepath.Clean("/" + p.Name))
+	if dest == "" {
+		return name
+	}
+	if info, err := os.Stat(dest); err == nil && info.IsDir() || strings.HasSuffix(dest, string(filepath.Separator)) {
+		return filepath.Join(dest, name)
+	}
+	return dest
+}
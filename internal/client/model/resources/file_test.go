@@ -0,0 +1,19 @@
+package resources
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFile_LegacyDescription(t *testing.T) {
+	// descriptions stored before json tags have capitalized keys
+	var file File
+	assert.NoError(t, json.Unmarshal([]byte(`{"Name":"cert.pem","Extension":".pem","Size":42,"Compression":"zstd"}`), &file))
+	assert.Equal(t, File{Name: "cert.pem", Extension: ".pem", Size: 42, Compression: "zstd"}, file)
+
+	data, err := json.Marshal(&file)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"name":"cert.pem","extension":".pem","size":42,"compression":"zstd"}`, string(data))
+}
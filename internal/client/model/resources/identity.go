@@ -0,0 +1,42 @@
+package resources
+
+import (
+	"fmt"
+	"strings"
+
+	"ydx-goadv-gophkeeper/pkg/model/enum"
+)
+
+type Identity struct {
+	FirstName      string   `json:"firstName,omitempty"`
+	LastName       string   `json:"lastName,omitempty"`
+	BirthDate      string   `json:"birthDate,omitempty"`
+	Email          string   `json:"email,omitempty"`
+	Phone          string   `json:"phone,omitempty"`
+	Addresses      []string `json:"addresses,omitempty"`
+	PassportNumber string   `json:"passportNumber,omitempty"`
+	IdNumber       string   `json:"idNumber,omitempty"`
+	CustomFields
+}
+
+func NewIdentity(firstName string, lastName string, birthDate string, email string, phone string) *Identity {
+	return &Identity{FirstName: firstName, LastName: lastName, BirthDate: birthDate, Email: email, Phone: phone}
+}
+
+func (i *Identity) Format(description string) string {
+	return fmt.Sprintf("firstName: %s\nlastName: %s\nbirthDate: %s\nemail: %s\nphone: %s\naddresses: %s\npassportNumber: %s\nidNumber: %s\ndescription: %s",
+		i.FirstName,
+		i.LastName,
+		i.BirthDate,
+		i.Email,
+		i.Phone,
+		strings.Join(i.Addresses, "; "),
+		i.PassportNumber,
+		i.IdNumber,
+		description,
+	)
+}
+
+func (i *Identity) Type() enum.ResourceType {
+	return enum.Identity
+}
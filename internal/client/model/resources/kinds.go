@@ -0,0 +1,175 @@
+package resources
+
+import (
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	"ydx-goadv-gophkeeper/pkg/model/enum"
+	"ydx-goadv-gophkeeper/pkg/model/registry"
+)
+
+type InputKind uint8
+
+const (
+	TextInput InputKind = iota
+	// SecretInput is read without echo
+	SecretInput
+	// MultilineInput is edited in $EDITOR
+	MultilineInput
+	// FileContentInput reads file content by the input path
+	FileContentInput
+	NumberInput
+	// ListInput reads values until empty input
+	ListInput
+)
+
+// SchemaField describes json field of the resource data and how to read it
+type SchemaField struct {
+	Name   string
+	Prompt string
+	Input  InputKind
+	// Generate offers to generate SecretInput value
+	Generate bool
+}
+
+// Kind is the client side of registry.Kind
+type Kind struct {
+	registry.Kind
+	New func() ResourceClIFormatter
+	// Schema is empty for resources which can't be read as json data, i.e. File
+	Schema []SchemaField
+	// validate checks resource read by Schema and fills its derived fields
+	validate func(resource ResourceClIFormatter, v *Validation)
+}
+
+var kinds = map[enum.ResourceType]Kind{
+	enum.LoginPassword: {
+		New: func() ResourceClIFormatter { return &LoginPassword{} },
+		Schema: []SchemaField{
+			{Name: "login", Prompt: "input login"},
+			{Name: "password", Input: SecretInput, Generate: true},
+			{Name: "url", Prompt: "input url (optional)"},
+			{Name: "totpId", Prompt: "input linked totp id (optional)", Input: NumberInput},
+		},
+		validate: validateLoginPassword,
+	},
+	enum.BankCard: {
+		New: func() ResourceClIFormatter { return &BankCard{} },
+		Schema: []SchemaField{
+			{Name: "number", Prompt: "input number"},
+			{Name: "expireAt", Prompt: "input expireAt in format: MM/YY"},
+			{Name: "name", Prompt: "input name"},
+			{Name: "surname", Prompt: "input surname"},
+		},
+		validate: validateBankCard,
+	},
+	enum.File: {
+		New: func() ResourceClIFormatter { return &File{} },
+	},
+	enum.SshKey: {
+		New: func() ResourceClIFormatter { return &SshKey{} },
+		Schema: []SchemaField{
+			{Name: "privateKey", Prompt: "input private key file path", Input: FileContentInput},
+			{Name: "passphrase", Prompt: "input key passphrase, empty if key is not encrypted", Input: SecretInput},
+			{Name: "comment", Prompt: "input comment"},
+		},
+		validate: func(resource ResourceClIFormatter, v *Validation) {
+			if err := resource.(*SshKey).fillPublicKey(); err != nil {
+				v.Fail("privateKey", "%v", err)
+			}
+		},
+	},
+	enum.SecureNote: {
+		New: func() ResourceClIFormatter { return &SecureNote{} },
+		Schema: []SchemaField{
+			{Name: "text", Prompt: "input text", Input: MultilineInput},
+		},
+	},
+	enum.Totp: {
+		New: func() ResourceClIFormatter { return &Totp{} },
+		Schema: []SchemaField{
+			{Name: "uri", Prompt: "input otpauth:// uri or QR code image file path"},
+		},
+		validate: func(resource ResourceClIFormatter, v *Validation) {
+			totp := resource.(*Totp)
+			var parsed *Totp
+			var err error
+			if strings.HasPrefix(totp.Uri, "otpauth://") {
+				parsed, err = NewTotpFromUri(totp.Uri)
+			} else {
+				parsed, err = NewTotpFromQrImage(totp.Uri)
+			}
+			if err != nil {
+				v.Fail("uri", "%v", err)
+				return
+			}
+			parsed.CustomFields = totp.CustomFields
+			*totp = *parsed
+		},
+	},
+	enum.Identity: {
+		New: func() ResourceClIFormatter { return &Identity{} },
+		Schema: []SchemaField{
+			{Name: "firstName", Prompt: "input first name"},
+			{Name: "lastName", Prompt: "input last name"},
+			{Name: "birthDate", Prompt: "input birth date in format: YYYY-MM-DD (optional)"},
+			{Name: "email", Prompt: "input email (optional)"},
+			{Name: "phone", Prompt: "input phone (optional)"},
+			{Name: "addresses", Prompt: "input address, empty to finish", Input: ListInput},
+			{Name: "passportNumber", Prompt: "input passport number (optional)"},
+			{Name: "idNumber", Prompt: "input ID number (optional)"},
+		},
+		validate: func(resource ResourceClIFormatter, v *Validation) {
+			identity := resource.(*Identity)
+			if identity.FirstName == "" && identity.LastName == "" {
+				v.Fail("firstName", "first or last name is required")
+			}
+			if identity.BirthDate != "" {
+				if _, err := time.Parse(CustomFieldDateLayout, identity.BirthDate); err != nil {
+					v.Fail("birthDate", "'%s' is not in format YYYY-MM-DD", identity.BirthDate)
+				}
+			}
+			if identity.Email != "" {
+				if _, err := mail.ParseAddress(identity.Email); err != nil {
+					v.Fail("email", "'%s' is not a valid email", identity.Email)
+				}
+			}
+		},
+	},
+}
+
+func init() {
+	for _, kind := range registry.All() {
+		clientKind, ok := kinds[kind.Type]
+		if !ok {
+			panic(fmt.Sprintf("resource kind %s is not registered on client", kind.Name))
+		}
+		clientKind.Kind = kind
+		kinds[kind.Type] = clientKind
+	}
+}
+
+// Kinds returns client kinds in registry order
+func Kinds() []Kind {
+	all := registry.All()
+	result := make([]Kind, 0, len(all))
+	for _, kind := range all {
+		result = append(result, kinds[kind.Type])
+	}
+	return result
+}
+
+func KindOf(resType enum.ResourceType) (Kind, bool) {
+	kind, ok := kinds[resType]
+	return kind, ok
+}
+
+func KindByAlias(alias string) (Kind, bool) {
+	kind, ok := registry.ByAlias(alias)
+	if !ok {
+		return Kind{}, false
+	}
+	return kinds[kind.Type], true
+}
@@ -0,0 +1,33 @@
+package resources
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKinds_SchemaMatchesJson(t *testing.T) {
+	for _, kind := range Kinds() {
+		t.Run(kind.Name, func(t *testing.T) {
+			resource := kind.New()
+			assert.Equal(t, kind.Type, resource.Type())
+			for _, field := range kind.Schema {
+				values := map[string]any{field.Name: "1"}
+				if field.Input == NumberInput {
+					values[field.Name] = 1
+				}
+				if field.Input == ListInput {
+					values[field.Name] = []string{"1"}
+				}
+				data, _ := json.Marshal(values)
+				assert.NoError(t, json.Unmarshal(data, resource))
+
+				info := &Info{Resource: resource}
+				value, err := info.Field(field.Name)
+				assert.NoError(t, err, "schema field %s is not a json field", field.Name)
+				assert.Contains(t, value, "1")
+			}
+		})
+	}
+}
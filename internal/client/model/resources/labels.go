@@ -0,0 +1,127 @@
+package resources
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Labels organize resources, they are encrypted like resource data
+type Labels struct {
+	// Folder is the slash separated path, empty is the root folder
+	Folder string   `json:"folder,omitempty"`
+	Tags   []string `json:"tags,omitempty"`
+}
+
+// ParseLabels parses decrypted labels json, empty data is no labels
+func ParseLabels(data []byte) (Labels, error) {
+	var labels Labels
+	if len(data) == 0 {
+		return labels, nil
+	}
+	err := json.Unmarshal(data, &labels)
+	return labels, err
+}
+
+func (l Labels) IsEmpty() bool {
+	return l.Folder == "" && len(l.Tags) == 0
+}
+
+// NormalizeFolder cleans folder path, empty path and '/' are the root folder
+func NormalizeFolder(folder string) string {
+	return strings.Trim(path.Clean("/"+strings.TrimSpace(folder)), "/")
+}
+
+func NormalizeTag(tag string) (string, error) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if tag == "" || strings.ContainsAny(tag, ", \t\n") {
+		return "", fmt.Errorf("tag '%s' is not valid, it should be a non empty word", tag)
+	}
+	return tag, nil
+}
+
+func (l *Labels) HasTag(tag string) bool {
+	for _, t := range l.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *Labels) AddTag(tag string) {
+	if !l.HasTag(tag) {
+		l.Tags = append(l.Tags, tag)
+		sort.Strings(l.Tags)
+	}
+}
+
+func (l *Labels) RemoveTag(tag string) {
+	for i, t := range l.Tags {
+		if t == tag {
+			l.Tags = append(l.Tags[:i], l.Tags[i+1:]...)
+			return
+		}
+	}
+}
+
+// InFolder checks the resource is in the folder or its subfolders if recursive is set
+func (l *Labels) InFolder(folder string, recursive bool) bool {
+	if l.Folder == folder {
+		return true
+	}
+	return recursive && (folder == "" || strings.HasPrefix(l.Folder, folder+"/"))
+}
+
+// Subfolder returns the direct subfolder name of the parent folder which contains the resource
+func (l *Labels) Subfolder(parent string) (string, bool) {
+	rest := l.Folder
+	if parent != "" {
+		if !strings.HasPrefix(l.Folder, parent+"/") {
+			return "", false
+		}
+		rest = strings.TrimPrefix(l.Folder, parent+"/")
+	}
+	if rest == "" {
+		return "", false
+	}
+	name, _, _ := strings.Cut(rest, "/")
+	return name, true
+}
+
+// IndexTerms are plain terms of the labels, their blind indexes let server filter resources by tag and folder
+func (l Labels) IndexTerms() []string {
+	terms := make([]string, 0, len(l.Tags)+strings.Count(l.Folder, "/")+1)
+	for _, tag := range l.Tags {
+		terms = append(terms, TagTerm(tag))
+	}
+	if l.Folder != "" {
+		parts := strings.Split(l.Folder, "/")
+		for i := range parts {
+			terms = append(terms, FolderTerm(strings.Join(parts[:i+1], "/")))
+		}
+	}
+	return terms
+}
+
+func TagTerm(tag string) string {
+	return "tag:" + tag
+}
+
+// FolderTerm matches resources of the folder and its subfolders
+func FolderTerm(folder string) string {
+	return "in:" + folder
+}
+
+func (l Labels) Format() string {
+	var result string
+	if l.Folder != "" {
+		result += fmt.Sprintf("folder: %s\n", l.Folder)
+	}
+	if len(l.Tags) != 0 {
+		result += fmt.Sprintf("tags: %s\n", strings.Join(l.Tags, ", "))
+	}
+	return result
+}
@@ -0,0 +1,61 @@
+package resources
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeFolder(t *testing.T) {
+	tests := []struct {
+		folder   string
+		expected string
+	}{
+		{folder: "", expected: ""},
+		{folder: "/", expected: ""},
+		{folder: " work//dev/ ", expected: "work/dev"},
+		{folder: "/work/./dev", expected: "work/dev"},
+		{folder: "work/../../personal", expected: "personal"},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, NormalizeFolder(test.folder), test.folder)
+	}
+}
+
+func TestLabels_Folders(t *testing.T) {
+	labels := Labels{Folder: "work/dev/backend"}
+	assert.True(t, labels.InFolder("work/dev/backend", false))
+	assert.False(t, labels.InFolder("work", false))
+	assert.True(t, labels.InFolder("work", true))
+	assert.True(t, labels.InFolder("", true))
+	assert.False(t, labels.InFolder("wo", true))
+
+	subfolder, ok := labels.Subfolder("work")
+	assert.True(t, ok)
+	assert.Equal(t, "dev", subfolder)
+	subfolder, ok = labels.Subfolder("")
+	assert.True(t, ok)
+	assert.Equal(t, "work", subfolder)
+	_, ok = labels.Subfolder("work/dev/backend")
+	assert.False(t, ok)
+
+	labels.Tags = []string{"prod"}
+	assert.Equal(t, []string{"tag:prod", "in:work", "in:work/dev", "in:work/dev/backend"}, labels.IndexTerms())
+}
+
+func TestLabels_Tags(t *testing.T) {
+	var labels Labels
+	labels.AddTag("prod")
+	labels.AddTag("aws")
+	labels.AddTag("prod")
+	assert.Equal(t, []string{"aws", "prod"}, labels.Tags)
+	labels.RemoveTag("aws")
+	assert.Equal(t, []string{"prod"}, labels.Tags)
+	assert.Equal(t, "tags: prod\n", labels.Format())
+
+	_, err := NormalizeTag("two words")
+	assert.Error(t, err)
+	tag, err := NormalizeTag(" Prod ")
+	assert.NoError(t, err)
+	assert.Equal(t, "prod", tag)
+}
@@ -9,14 +9,24 @@ import (
 type LoginPassword struct {
 	Login    string `json:"login,omitempty"`
 	Password string `json:"password,omitempty"`
+	Url      string `json:"url,omitempty"`
+	TotpId   int32  `json:"totpId,omitempty"`
+	CustomFields
 }
 
-func NewLoginPassword(login string, password string) *LoginPassword {
-	return &LoginPassword{Login: login, Password: password}
+func NewLoginPassword(login string, password string, url string) *LoginPassword {
+	return &LoginPassword{Login: login, Password: password, Url: url}
 }
 
 func (p *LoginPassword) Format(description string) string {
-	return fmt.Sprintf("login: %s\npassword: %s\ndescription: %s", p.Login, p.Password, description)
+	var optional string
+	if p.Url != "" {
+		optional += fmt.Sprintf("url: %s\n", p.Url)
+	}
+	if p.TotpId != 0 {
+		optional += fmt.Sprintf("totp: %d\n", p.TotpId)
+	}
+	return fmt.Sprintf("login: %s\npassword: %s\n%sdescription: %s", p.Login, p.Password, optional, description)
 }
 
 func (p *LoginPassword) Type() enum.ResourceType {
@@ -1,6 +1,13 @@
 package resources
 
-import "ydx-goadv-gophkeeper/pkg/model/enum"
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"ydx-goadv-gophkeeper/pkg/model/enum"
+)
 
 type ResourceClIFormatter interface {
 	Format(description string) string
@@ -10,8 +17,52 @@ type ResourceClIFormatter interface {
 type Info struct {
 	Resource ResourceClIFormatter
 	Meta     []byte
+	Labels   Labels
+	// Reveal shows hidden custom fields values in Format
+	Reveal bool
 }
 
 func (rd *Info) Format() string {
-	return rd.Resource.Format(string(rd.Meta))
+	formatted := rd.Resource.Format(string(rd.Meta))
+	if holder, ok := rd.Resource.(CustomFieldsHolder); ok {
+		if fields := holder.Custom().Format(rd.Reveal); fields != "" {
+			formatted = strings.TrimSuffix(formatted, "\n") + "\n" + fields
+		}
+	}
+	if labels := rd.Labels.Format(); labels != "" {
+		formatted = strings.TrimSuffix(formatted, "\n") + "\n" + strings.TrimSuffix(labels, "\n")
+	}
+	return formatted
+}
+
+// Field returns value of the resource field or custom field by its name, 'description' is resource meta,
+// 'code' is the current code of the Totp resource
+func (rd *Info) Field(name string) (string, error) {
+	if name == "description" {
+		return string(rd.Meta), nil
+	}
+	if totp, ok := rd.Resource.(*Totp); ok && name == "code" {
+		code, _, err := totp.Code(time.Now())
+		return code, err
+	}
+	if holder, ok := rd.Resource.(CustomFieldsHolder); ok {
+		if field, ok := holder.Custom().Get(name); ok {
+			return field.Value, nil
+		}
+	}
+	value := reflect.Indirect(reflect.ValueOf(rd.Resource))
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if field.Anonymous {
+			continue
+		}
+		jsonName, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if jsonName == "" {
+			jsonName = field.Name
+		}
+		if strings.EqualFold(jsonName, name) {
+			return fmt.Sprint(value.Field(i).Interface()), nil
+		}
+	}
+	return "", fmt.Errorf("field '%s' is not found", name)
 }
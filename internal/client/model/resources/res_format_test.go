@@ -104,6 +104,26 @@ func TestLoginPassword_Format(t *testing.T) {
 			descr:          "",
 			expectedString: "login: again?\npassword: top secret!@#$%ABC123\ndescription: ",
 		},
+		{
+			name: "With url",
+			res: LoginPassword{
+				Login:    "git",
+				Password: "token",
+				Url:      "https://github.com",
+			},
+			descr:          "github",
+			expectedString: "login: git\npassword: token\nurl: https://github.com\ndescription: github",
+		},
+		{
+			name: "With totp",
+			res: LoginPassword{
+				Login:    "admin",
+				Password: "secret",
+				TotpId:   7,
+			},
+			descr:          "aws",
+			expectedString: "login: admin\npassword: secret\ntotp: 7\ndescription: aws",
+		},
 	}
 
 	for _, test := range tests {
@@ -111,3 +131,29 @@ func TestLoginPassword_Format(t *testing.T) {
 		assert.Equal(t, test.expectedString, test.res.Format(test.descr))
 	}
 }
+
+func TestSecureNote_Format(t *testing.T) {
+	tests := []struct {
+		name           string
+		res            SecureNote
+		descr          string
+		expectedString string
+	}{
+		{
+			name:           "Multi-line note",
+			res:            SecureNote{Text: "recovery codes:\n1234-5678\n8765-4321"},
+			descr:          "github",
+			expectedString: "text:\nrecovery codes:\n1234-5678\n8765-4321\ndescription: github",
+		},
+		{
+			name:           "Empty note",
+			res:            SecureNote{},
+			descr:          "",
+			expectedString: "text:\n\ndescription: ",
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expectedString, test.res.Format(test.descr))
+	}
+}
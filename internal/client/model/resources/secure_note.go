@@ -0,0 +1,24 @@
+package resources
+
+import (
+	"fmt"
+
+	"ydx-goadv-gophkeeper/pkg/model/enum"
+)
+
+type SecureNote struct {
+	Text string `json:"text,omitempty"`
+	CustomFields
+}
+
+func NewSecureNote(text string) *SecureNote {
+	return &SecureNote{Text: text}
+}
+
+func (n *SecureNote) Format(description string) string {
+	return fmt.Sprintf("text:\n%s\ndescription: %s", n.Text, description)
+}
+
+func (n *SecureNote) Type() enum.ResourceType {
+	return enum.SecureNote
+}
@@ -0,0 +1,64 @@
+package resources
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+
+	"ydx-goadv-gophkeeper/pkg/model/enum"
+)
+
+type SshKey struct {
+	PrivateKey string `json:"privateKey,omitempty"`
+	PublicKey  string `json:"publicKey,omitempty"`
+	Comment    string `json:"comment,omitempty"`
+	Passphrase string `json:"passphrase,omitempty"`
+	CustomFields
+}
+
+// NewSshKey parses private key to fill its public key in authorized_keys format
+func NewSshKey(privateKey string, passphrase string, comment string) (*SshKey, error) {
+	key := &SshKey{PrivateKey: privateKey, Comment: comment, Passphrase: passphrase}
+	if err := key.fillPublicKey(); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func (k *SshKey) fillPublicKey() error {
+	signer, err := k.Signer()
+	if err != nil {
+		return err
+	}
+	k.PublicKey = strings.TrimSpace(string(ssh.MarshalAuthorizedKey(signer.PublicKey())))
+	return nil
+}
+
+// Signer decrypts private key with passphrase if it's set
+func (k *SshKey) Signer() (ssh.Signer, error) {
+	var rawKey any
+	var err error
+	if k.Passphrase != "" {
+		rawKey, err = ssh.ParseRawPrivateKeyWithPassphrase([]byte(k.PrivateKey), []byte(k.Passphrase))
+	} else {
+		rawKey, err = ssh.ParseRawPrivateKey([]byte(k.PrivateKey))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ssh private key: %v", err)
+	}
+	return ssh.NewSignerFromKey(rawKey)
+}
+
+func (k *SshKey) Format(description string) string {
+	return fmt.Sprintf("publicKey: %s\ncomment: %s\nprivateKey:\n%s\ndescription: %s",
+		k.PublicKey,
+		k.Comment,
+		strings.TrimSpace(k.PrivateKey),
+		description,
+	)
+}
+
+func (k *SshKey) Type() enum.ResourceType {
+	return enum.SshKey
+}
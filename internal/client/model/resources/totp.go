@@ -0,0 +1,178 @@
+package resources
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/qrcode"
+
+	"ydx-goadv-gophkeeper/pkg/model/enum"
+)
+
+const (
+	totpDefaultDigits    = 6
+	totpDefaultPeriod    = 30
+	totpDefaultAlgorithm = "SHA1"
+	// codes longer than 10 digits overflow the truncated 31-bit hmac value
+	totpMinDigits = 6
+	totpMaxDigits = 10
+)
+
+type Totp struct {
+	Uri       string `json:"uri,omitempty"`
+	Issuer    string `json:"issuer,omitempty"`
+	Account   string `json:"account,omitempty"`
+	Secret    string `json:"secret,omitempty"`
+	Digits    int    `json:"digits,omitempty"`
+	Period    int    `json:"period,omitempty"`
+	Algorithm string `json:"algorithm,omitempty"`
+	CustomFields
+}
+
+// NewTotpFromUri parses otpauth://totp/Issuer:account?secret=...&issuer=...&digits=6&period=30&algorithm=SHA1
+func NewTotpFromUri(uri string) (*Totp, error) {
+	u, err := url.Parse(strings.TrimSpace(uri))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse otpauth uri: %v", err)
+	}
+	if u.Scheme != "otpauth" || u.Host != "totp" {
+		return nil, fmt.Errorf("uri '%s' is not an otpauth://totp uri", uri)
+	}
+	query := u.Query()
+	totp := &Totp{
+		Uri:       u.String(),
+		Secret:    strings.ToUpper(strings.ReplaceAll(query.Get("secret"), " ", "")),
+		Issuer:    query.Get("issuer"),
+		Digits:    totpDefaultDigits,
+		Period:    totpDefaultPeriod,
+		Algorithm: totpDefaultAlgorithm,
+	}
+	label := strings.TrimPrefix(u.Path, "/")
+	if issuer, account, ok := strings.Cut(label, ":"); ok {
+		totp.Account = strings.TrimSpace(account)
+		if totp.Issuer == "" {
+			totp.Issuer = issuer
+		}
+	} else {
+		totp.Account = label
+	}
+	if digits := query.Get("digits"); digits != "" {
+		if totp.Digits, err = strconv.Atoi(digits); err != nil {
+			return nil, fmt.Errorf("invalid digits '%s': %v", digits, err)
+		}
+	}
+	if period := query.Get("period"); period != "" {
+		if totp.Period, err = strconv.Atoi(period); err != nil {
+			return nil, fmt.Errorf("invalid period '%s': %v", period, err)
+		}
+	}
+	if algorithm := query.Get("algorithm"); algorithm != "" {
+		totp.Algorithm = strings.ToUpper(algorithm)
+	}
+	if totp.Digits < totpMinDigits || totp.Digits > totpMaxDigits {
+		return nil, fmt.Errorf("digits %d must be from %d to %d", totp.Digits, totpMinDigits, totpMaxDigits)
+	}
+	if totp.Period <= 0 {
+		return nil, fmt.Errorf("period %d must be positive", totp.Period)
+	}
+	if _, _, err := totp.Code(time.Now()); err != nil {
+		return nil, err
+	}
+	return totp, nil
+}
+
+// NewTotpFromQrImage decodes otpauth uri from png or jpeg QR code image
+func NewTotpFromQrImage(path string) (*Totp, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %v", err)
+	}
+	bitmap, err := gozxing.NewBinaryBitmapFromImage(img)
+	if err != nil {
+		return nil, err
+	}
+	result, err := qrcode.NewQRCodeReader().Decode(bitmap, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read QR code: %v", err)
+	}
+	return NewTotpFromUri(result.GetText())
+}
+
+// Code generates RFC 6238 code for the moment and returns seconds left until it expires
+func (t *Totp) Code(moment time.Time) (string, int, error) {
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(strings.TrimRight(t.Secret, "="))
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid totp secret: %v", err)
+	}
+	var hashFunc func() hash.Hash
+	switch t.Algorithm {
+	case "", "SHA1":
+		hashFunc = sha1.New
+	case "SHA256":
+		hashFunc = sha256.New
+	case "SHA512":
+		hashFunc = sha512.New
+	default:
+		return "", 0, fmt.Errorf("totp algorithm '%s' is not supported", t.Algorithm)
+	}
+	digits := t.Digits
+	if digits == 0 {
+		digits = totpDefaultDigits
+	}
+	if digits < totpMinDigits || digits > totpMaxDigits {
+		return "", 0, fmt.Errorf("totp digits %d must be from %d to %d", digits, totpMinDigits, totpMaxDigits)
+	}
+	period := int64(t.Period)
+	if period == 0 {
+		period = totpDefaultPeriod
+	}
+	if period < 0 {
+		return "", 0, fmt.Errorf("totp period %d must be positive", period)
+	}
+
+	counter := make([]byte, 8)
+	binary.BigEndian.PutUint64(counter, uint64(moment.Unix()/period))
+	mac := hmac.New(hashFunc, key)
+	mac.Write(counter)
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	value := int64(binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff)
+	mod := int64(1)
+	for i := 0; i < digits; i++ {
+		mod *= 10
+	}
+	code := fmt.Sprintf("%0*d", digits, value%mod)
+	return code, int(period - moment.Unix()%period), nil
+}
+
+func (t *Totp) Format(description string) string {
+	code, left, err := t.Code(time.Now())
+	if err != nil {
+		code = err.Error()
+	}
+	return fmt.Sprintf("issuer: %s\naccount: %s\ncode: %s (%ds left)\ndescription: %s", t.Issuer, t.Account, code, left, description)
+}
+
+func (t *Totp) Type() enum.ResourceType {
+	return enum.Totp
+}
@@ -0,0 +1,102 @@
+package resources
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"ydx-goadv-gophkeeper/pkg/model/enum"
+)
+
+func TestTotp_Code(t *testing.T) {
+	tests := []struct {
+		name         string
+		totp         Totp
+		moment       int64
+		expectedCode string
+		expectedLeft int
+	}{
+		{
+			name:         "RFC 6238 SHA1",
+			totp:         Totp{Secret: "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", Digits: 8, Period: 30, Algorithm: "SHA1"},
+			moment:       59,
+			expectedCode: "94287082",
+			expectedLeft: 1,
+		},
+		{
+			name:         "RFC 6238 SHA1 later",
+			totp:         Totp{Secret: "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", Digits: 8, Period: 30, Algorithm: "SHA1"},
+			moment:       1111111109,
+			expectedCode: "07081804",
+			expectedLeft: 1,
+		},
+		{
+			name:         "RFC 6238 SHA256",
+			totp:         Totp{Secret: "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQGEZA", Digits: 8, Period: 30, Algorithm: "SHA256"},
+			moment:       59,
+			expectedCode: "46119246",
+			expectedLeft: 1,
+		},
+		{
+			name:         "Defaults",
+			totp:         Totp{Secret: "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"},
+			moment:       40,
+			expectedCode: "287082",
+			expectedLeft: 20,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			code, left, err := test.totp.Code(time.Unix(test.moment, 0))
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedCode, code)
+			assert.Equal(t, test.expectedLeft, left)
+		})
+	}
+}
+
+func TestNewTotpFromUri(t *testing.T) {
+	totp, err := NewTotpFromUri("otpauth://totp/ACME:john@example.com?secret=gezdgnbvgy3tqojq&digits=8&period=60&algorithm=sha256")
+	assert.NoError(t, err)
+	assert.Equal(t, "ACME", totp.Issuer)
+	assert.Equal(t, "john@example.com", totp.Account)
+	assert.Equal(t, "GEZDGNBVGY3TQOJQ", totp.Secret)
+	assert.Equal(t, 8, totp.Digits)
+	assert.Equal(t, 60, totp.Period)
+	assert.Equal(t, "SHA256", totp.Algorithm)
+
+	_, err = NewTotpFromUri("otpauth://hotp/ACME?secret=GEZDGNBVGY3TQOJQ")
+	assert.Error(t, err)
+}
+
+func TestNewTotpFromUri_InvalidParams(t *testing.T) {
+	tests := []struct {
+		name string
+		uri  string
+		err  string
+	}{
+		{name: "digits overflow int64", uri: "otpauth://totp/ACME?secret=GEZDGNBVGY3TQOJQ&digits=19", err: "digits 19 must be from 6 to 10"},
+		{name: "digits zero modulus", uri: "otpauth://totp/ACME?secret=GEZDGNBVGY3TQOJQ&digits=64", err: "digits 64 must be from 6 to 10"},
+		{name: "too few digits", uri: "otpauth://totp/ACME?secret=GEZDGNBVGY3TQOJQ&digits=4", err: "digits 4 must be from 6 to 10"},
+		{name: "zero period", uri: "otpauth://totp/ACME?secret=GEZDGNBVGY3TQOJQ&period=0", err: "period 0 must be positive"},
+		{name: "negative period", uri: "otpauth://totp/ACME?secret=GEZDGNBVGY3TQOJQ&period=-30", err: "period -30 must be positive"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := NewTotpFromUri(test.uri)
+			assert.EqualError(t, err, test.err)
+
+			kind, _ := KindOf(enum.Totp)
+			assert.Error(t, kind.Validate(&Totp{Uri: test.uri}).Err())
+		})
+	}
+}
+
+func TestTotp_CodeInvalidDigits(t *testing.T) {
+	// stored resources are not trusted either
+	_, _, err := (&Totp{Secret: "GEZDGNBVGY3TQOJQ", Digits: 64}).Code(time.Now())
+	assert.EqualError(t, err, "totp digits 64 must be from 6 to 10")
+}
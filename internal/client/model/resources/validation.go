@@ -0,0 +1,220 @@
+package resources
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// MaxDataSize limits json data of the text resource
+	MaxDataSize = 64 * 1024
+	// MaxFieldLength limits single line fields
+	MaxFieldLength = 1024
+)
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Validation collects field errors and warnings, warnings don't prevent saving
+type Validation struct {
+	Errors   []FieldError `json:"errors,omitempty"`
+	Warnings []FieldError `json:"warnings,omitempty"`
+}
+
+func (v *Validation) Fail(field string, format string, args ...any) {
+	v.Errors = append(v.Errors, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *Validation) Warn(field string, format string, args ...any) {
+	v.Warnings = append(v.Warnings, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// Err returns ValidationError if there are errors
+func (v *Validation) Err() error {
+	if len(v.Errors) == 0 {
+		return nil
+	}
+	return &ValidationError{Errors: v.Errors}
+}
+
+type ValidationError struct {
+	Errors []FieldError `json:"errors"`
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, fieldErr := range e.Errors {
+		messages = append(messages, fmt.Sprintf("%s: %s", fieldErr.Field, fieldErr.Message))
+	}
+	return "validation failed: " + strings.Join(messages, "; ")
+}
+
+// Fields returns names of invalid fields
+func (e *ValidationError) Fields() []string {
+	fields := make([]string, 0, len(e.Errors))
+	for _, fieldErr := range e.Errors {
+		fields = append(fields, fieldErr.Field)
+	}
+	return fields
+}
+
+// Validate checks resource by its kind rules, custom fields and size limits,
+// resource may be normalized, i.e. card number without spaces
+func (k Kind) Validate(resource ResourceClIFormatter) *Validation {
+	v := &Validation{}
+	if k.validate != nil {
+		k.validate(resource, v)
+	}
+	for _, field := range k.Schema {
+		if field.Input != TextInput && field.Input != SecretInput {
+			continue
+		}
+		value, err := (&Info{Resource: resource}).Field(field.Name)
+		if err == nil && utf8.RuneCountInString(value) > MaxFieldLength {
+			v.Fail(field.Name, "is longer than %d characters", MaxFieldLength)
+		}
+	}
+	if holder, ok := resource.(CustomFieldsHolder); ok {
+		for _, field := range holder.Custom().Fields {
+			if _, err := NewCustomField(field.Name, field.Type, field.Value); err != nil {
+				v.Fail(field.Name, "%v", err)
+			}
+		}
+	}
+	if data, err := json.Marshal(resource); err != nil {
+		v.Fail("data", "%v", err)
+	} else if len(data) > MaxDataSize {
+		v.Fail("data", "size %d bytes exceeds limit of %d bytes", len(data), MaxDataSize)
+	}
+	return v
+}
+
+func validateLoginPassword(resource ResourceClIFormatter, v *Validation) {
+	lp := resource.(*LoginPassword)
+	lp.Login = strings.TrimSpace(lp.Login)
+	if lp.Login == "" {
+		v.Fail("login", "is empty")
+	}
+	if lp.Password == "" {
+		v.Fail("password", "is empty")
+	}
+	if lp.Url != "" {
+		normalized, err := NormalizeUrl(lp.Url)
+		if err != nil {
+			v.Fail("url", "%v", err)
+		} else {
+			lp.Url = normalized
+		}
+	}
+	if lp.TotpId < 0 {
+		v.Fail("totpId", "is negative")
+	}
+}
+
+// NormalizeUrl adds https scheme if it's missing and lower cases scheme and host
+func NormalizeUrl(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("is not a valid url: %v", err)
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("url '%s' has no host", raw)
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	return u.String(), nil
+}
+
+func validateBankCard(resource ResourceClIFormatter, v *Validation) {
+	card := resource.(*BankCard)
+	card.Number = strings.NewReplacer(" ", "", "-", "").Replace(card.Number)
+	if err := checkCardNumber(card.Number); err != nil {
+		v.Fail("number", "%v", err)
+	} else {
+		card.Brand = CardBrand(card.Number)
+	}
+
+	expireAt, err := ParseCardExpiry(card.ExpireAt)
+	if err != nil {
+		v.Fail("expireAt", "%v", err)
+	} else {
+		card.ExpireAt = expireAt.Format("01/06")
+		if time.Now().After(expireAt) {
+			v.Warn("expireAt", "card expired at %s", card.ExpireAt)
+		}
+	}
+}
+
+func checkCardNumber(number string) error {
+	if len(number) < 12 || len(number) > 19 {
+		return fmt.Errorf("should contain from 12 to 19 digits")
+	}
+	sum := 0
+	for i := 0; i < len(number); i++ {
+		digit := int(number[len(number)-1-i] - '0')
+		if digit < 0 || digit > 9 {
+			return fmt.Errorf("should contain only digits")
+		}
+		if i%2 == 1 {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+	}
+	if sum%10 != 0 {
+		return fmt.Errorf("failed Luhn check")
+	}
+	return nil
+}
+
+// CardBrand detects card brand by its number prefix
+func CardBrand(number string) string {
+	prefix := func(n int) int {
+		if len(number) < n {
+			return -1
+		}
+		var value int
+		fmt.Sscanf(number[:n], "%d", &value)
+		return value
+	}
+	switch {
+	case strings.HasPrefix(number, "4"):
+		return "Visa"
+	case prefix(2) >= 51 && prefix(2) <= 55, prefix(4) >= 2221 && prefix(4) <= 2720:
+		return "Mastercard"
+	case prefix(4) >= 2200 && prefix(4) <= 2204:
+		return "Mir"
+	case prefix(2) == 34, prefix(2) == 37:
+		return "American Express"
+	case prefix(4) == 6011, prefix(2) == 65, prefix(3) >= 644 && prefix(3) <= 649:
+		return "Discover"
+	case prefix(4) >= 3528 && prefix(4) <= 3589:
+		return "JCB"
+	case prefix(2) == 62:
+		return "UnionPay"
+	case prefix(2) == 50, prefix(2) >= 56 && prefix(2) <= 69:
+		return "Maestro"
+	}
+	return ""
+}
+
+// ParseCardExpiry parses MM/YY and returns the end of the month
+func ParseCardExpiry(expireAt string) (time.Time, error) {
+	month, err := time.Parse("01/06", strings.TrimSpace(expireAt))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("'%s' is not in format MM/YY", expireAt)
+	}
+	return month.AddDate(0, 1, 0).Add(-time.Nanosecond), nil
+}
@@ -0,0 +1,63 @@
+package resources
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"ydx-goadv-gophkeeper/pkg/model/enum"
+)
+
+func TestCardBrand(t *testing.T) {
+	tests := []struct {
+		number string
+		brand  string
+	}{
+		{number: "4111111111111111", brand: "Visa"},
+		{number: "5555555555554444", brand: "Mastercard"},
+		{number: "2221000000000009", brand: "Mastercard"},
+		{number: "2200000000000004", brand: "Mir"},
+		{number: "378282246310005", brand: "American Express"},
+		{number: "6011111111111117", brand: "Discover"},
+		{number: "3530111333300000", brand: "JCB"},
+		{number: "9999999999999995", brand: ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.number, func(t *testing.T) {
+			assert.NoError(t, checkCardNumber(test.number))
+			assert.Equal(t, test.brand, CardBrand(test.number))
+		})
+	}
+	assert.Error(t, checkCardNumber("4111111111111112"))
+	assert.Error(t, checkCardNumber("4111a11111111111"))
+}
+
+func TestKind_ValidateBankCard(t *testing.T) {
+	kind, _ := KindOf(enum.BankCard)
+
+	card := NewBankCard("4111 1111-1111 1111", "1/30", "John", "Doe")
+	validation := kind.Validate(card)
+	assert.Equal(t, []FieldError{{Field: "expireAt", Message: "'1/30' is not in format MM/YY"}}, validation.Errors)
+	assert.Equal(t, "4111111111111111", card.Number)
+	assert.Equal(t, "Visa", card.Brand)
+
+	lastMonth := time.Now().AddDate(0, -1, 0).Format("01/06")
+	validation = kind.Validate(NewBankCard("4111111111111111", lastMonth, "John", "Doe"))
+	assert.NoError(t, validation.Err())
+	assert.Equal(t, []FieldError{{Field: "expireAt", Message: fmt.Sprintf("card expired at %s", lastMonth)}}, validation.Warnings)
+}
+
+func TestKind_ValidateLoginPassword(t *testing.T) {
+	kind, _ := KindOf(enum.LoginPassword)
+
+	lp := NewLoginPassword(" ", "secret", "GitHub.com/login")
+	err := kind.Validate(lp).Err()
+	assert.EqualError(t, err, "validation failed: login: is empty")
+	assert.Equal(t, "https://github.com/login", lp.Url)
+
+	lp = NewLoginPassword("john", string(make([]byte, MaxFieldLength+1)), "")
+	assert.Equal(t, []string{"password"}, kind.Validate(lp).Err().(*ValidationError).Fields())
+}
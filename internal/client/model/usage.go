@@ -0,0 +1,69 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+)
+
+type TypeUsage struct {
+	Type  string
+	Items int64
+	Bytes int64
+}
+
+// QuotaUsage is the consumption of user or organization quota, zero max values are unlimited
+type QuotaUsage struct {
+	Items    int64
+	Bytes    int64
+	MaxItems int64
+	MaxBytes int64
+}
+
+func (q QuotaUsage) String() string {
+	maxItems, maxBytes := "unlimited", "unlimited"
+	if q.MaxItems > 0 {
+		maxItems = fmt.Sprint(q.MaxItems)
+	}
+	if q.MaxBytes > 0 {
+		maxBytes = FormatBytes(q.MaxBytes)
+	}
+	return fmt.Sprintf("%d of %s resources, %s of %s", q.Items, maxItems, FormatBytes(q.Bytes), maxBytes)
+}
+
+type Usage struct {
+	Types []TypeUsage
+	User  QuotaUsage
+	// Organization is empty if user is not its member
+	Organization      string
+	OrganizationUsage QuotaUsage
+}
+
+func (u *Usage) String() string {
+	var builder strings.Builder
+	writer := tabwriter.NewWriter(&builder, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "TYPE\tITEMS\tSIZE")
+	for _, usage := range u.Types {
+		fmt.Fprintf(writer, "%s\t%d\t%s\n", usage.Type, usage.Items, FormatBytes(usage.Bytes))
+	}
+	writer.Flush()
+	builder.WriteString("user quota: " + u.User.String())
+	if u.Organization != "" {
+		builder.WriteString(fmt.Sprintf("\norganization '%s' quota: %s", u.Organization, u.OrganizationUsage.String()))
+	}
+	return builder.String()
+}
+
+// FormatBytes returns human readable size
+func FormatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
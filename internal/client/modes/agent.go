@@ -0,0 +1,75 @@
+package modes
+
+import (
+	"context"
+	"fmt"
+
+	"ydx-goadv-gophkeeper/internal/client/agent"
+	"ydx-goadv-gophkeeper/internal/client/configs"
+)
+
+// RunAgent unlocks the vault and serves it over the agent socket until ctx is done
+func RunAgent(ctx context.Context, cfg *configs.AppConfig, server agent.Server) error {
+	passphrase, username, password, err := readUnlockData(cfg)
+	if err != nil {
+		return err
+	}
+	if err = server.Unlock(ctx, passphrase, username, password); err != nil {
+		return fmt.Errorf("failed to unlock agent: %v", err)
+	}
+	fmt.Printf("agent is started, idle timeout: %v\nexport GOPHKEEPER_AGENT_SOCK=%s\n", cfg.AgentIdleTimeout, cfg.AgentSocket)
+	return server.Serve(ctx)
+}
+
+func RunLock(client agent.Client) error {
+	if err := client.Lock(); err != nil {
+		return err
+	}
+	fmt.Println("locked")
+	return nil
+}
+
+func RunUnlock(cfg *configs.AppConfig, client agent.Client) error {
+	passphrase, username, password, err := readUnlockData(cfg)
+	if err != nil {
+		return err
+	}
+	if err = client.Unlock(passphrase, username, password); err != nil {
+		return err
+	}
+	fmt.Println("unlocked")
+	return nil
+}
+
+func RunAgentStatus(client agent.Client) error {
+	status, err := client.Status()
+	if err != nil {
+		return err
+	}
+	if status.Locked {
+		fmt.Println("locked")
+		return nil
+	}
+	fmt.Printf("unlocked, auto-lock in %s\n", status.IdleLeft)
+	return nil
+}
+
+func readUnlockData(cfg *configs.AppConfig) ([]byte, string, string, error) {
+	var passphrase []byte
+	var err error
+	if cfg.PrivateKeyEncrypted {
+		passphrase, err = readSecret("private key passphrase:")
+		if err != nil {
+			return nil, "", "", err
+		}
+	}
+	username, err := readString("input username")
+	if err != nil {
+		return nil, "", "", err
+	}
+	password, err := readSecret("password:")
+	if err != nil {
+		return nil, "", "", err
+	}
+	return passphrase, username, string(password), nil
+}
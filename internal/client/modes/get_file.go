@@ -0,0 +1,50 @@
+package modes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/spf13/pflag"
+
+	"ydx-goadv-gophkeeper/internal/client/services"
+)
+
+// GetFile saves the file resource to the destination: gophkeeper get-file 42 [dest] [--force],
+// or streams its content to stdout for pipes: gophkeeper get-file 42 --stdout | tar x,
+// '--version n' gets the previous content of the file
+func GetFile(ctx context.Context, resourceService services.ResourceService, args []string, out io.Writer) error {
+	flags := pflag.NewFlagSet("get-file", pflag.ContinueOnError)
+	toStdout := flags.Bool("stdout", false, "write the file content to stdout")
+	force := flags.BoolP("force", "f", false, "overwrite existing file without confirmation")
+	version := flags.Int32("version", 0, "previous version of the file")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 || flags.NArg() > 2 {
+		return errors.New("usage: gophkeeper get-file [id] [dest] [--force] [--version n] or gophkeeper get-file [id] --stdout [--version n]")
+	}
+	resId, err := strconv.ParseInt(flags.Arg(0), 10, 32)
+	if err != nil {
+		return err
+	}
+	if *toStdout {
+		if *version != 0 {
+			return resourceService.WriteFileVersion(ctx, int32(resId), *version, out)
+		}
+		return resourceService.WriteFile(ctx, int32(resId), out)
+	}
+	download, err := services.NewFileDownload(ctx, resourceService, int32(resId), flags.Arg(1), *force, readString)
+	if err != nil {
+		return err
+	}
+	download.Version = *version
+	if err := download.Run(ctx); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "file saved to: %s\n", download.Path)
+	return nil
+}
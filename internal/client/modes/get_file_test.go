@@ -0,0 +1,40 @@
+package modes
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"ydx-goadv-gophkeeper/internal/client/mocks/services"
+	"ydx-goadv-gophkeeper/internal/client/model/resources"
+)
+
+func TestGetFile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	resourceService := services.NewMockResourceService(ctrl)
+
+	var out bytes.Buffer
+	resourceService.EXPECT().WriteFile(gomock.Any(), int32(42), &out).DoAndReturn(func(_ context.Context, _ int32, w io.Writer) error {
+		_, err := w.Write([]byte("content"))
+		return err
+	})
+	assert.NoError(t, GetFile(context.Background(), resourceService, []string{"42", "--stdout"}, &out))
+	assert.Equal(t, "content", out.String())
+
+	// stored name can not escape the destination directory
+	dir := t.TempDir()
+	resourceService.EXPECT().Get(gomock.Any(), int32(42)).Return(&resources.Info{Resource: &resources.File{Name: "../cert.pem"}}, nil)
+	resourceService.EXPECT().GetFileTo(gomock.Any(), int32(42), filepath.Join(dir, "cert.pem")).Return(nil)
+	assert.NoError(t, GetFile(context.Background(), resourceService, []string{"42", dir}, &out))
+
+	// previous version is saved with the current name
+	resourceService.EXPECT().Get(gomock.Any(), int32(42)).Return(&resources.Info{Resource: &resources.File{Name: "cert.pem"}}, nil)
+	resourceService.EXPECT().GetFileVersionTo(gomock.Any(), int32(42), int32(3), filepath.Join(dir, "cert.pem")).Return(nil)
+	assert.NoError(t, GetFile(context.Background(), resourceService, []string{"42", dir, "--version", "3"}, &out))
+}
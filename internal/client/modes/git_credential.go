@@ -0,0 +1,203 @@
+package modes
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"ydx-goadv-gophkeeper/internal/client/model/resources"
+	"ydx-goadv-gophkeeper/internal/client/services"
+	"ydx-goadv-gophkeeper/pkg/model/enum"
+)
+
+const (
+	gitGet   = "get"
+	gitStore = "store"
+	gitErase = "erase"
+)
+
+// gitCredential is the git credential helper protocol message,
+// see https://git-scm.com/docs/git-credential#IOFMT
+type gitCredential struct {
+	Protocol string
+	Host     string
+	Path     string
+	Username string
+	Password string
+}
+
+// GitCredential implements 'get', 'store' and 'erase' actions of the git credential helper.
+// LoginPassword resources are matched by their url, so git can fetch credentials from the vault:
+// git config --global credential.helper '!gophkeeper git-credential'
+func GitCredential(ctx context.Context, resourceService services.ResourceService, args []string, in io.Reader, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New("action is empty, usage: gophkeeper git-credential [get|store|erase]")
+	}
+	credential, err := readGitCredential(in)
+	if err != nil {
+		return err
+	}
+	if credential.Host == "" {
+		return errors.New("host is required")
+	}
+	switch args[0] {
+	case gitGet:
+		matches, err := findGitCredentials(ctx, resourceService, credential)
+		if err != nil || len(matches) == 0 {
+			// git tries the next helper if nothing is returned
+			return err
+		}
+		lp := matches[0].loginPassword
+		_, err = fmt.Fprintf(out, "username=%s\npassword=%s\n", lp.Login, lp.Password)
+		return err
+	case gitStore:
+		return storeGitCredential(ctx, resourceService, credential)
+	case gitErase:
+		matches, err := findGitCredentials(ctx, resourceService, credential)
+		if err != nil {
+			return err
+		}
+		for _, match := range matches {
+			// only the rejected credential is erased, other entries of the host are kept
+			if credential.Password == "" || match.loginPassword.Password != credential.Password {
+				continue
+			}
+			if err = resourceService.Delete(ctx, match.id); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		// unknown actions must be ignored by helpers
+		return nil
+	}
+}
+
+func readGitCredential(in io.Reader) (*gitCredential, error) {
+	credential := &gitCredential{}
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			break
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid credential line '%s'", line)
+		}
+		switch key {
+		case "protocol":
+			credential.Protocol = value
+		case "host":
+			credential.Host = value
+		case "path":
+			credential.Path = value
+		case "username":
+			credential.Username = value
+		case "password":
+			credential.Password = value
+		case "url":
+			u, err := url.Parse(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid credential url '%s': %v", value, err)
+			}
+			credential.Protocol, credential.Host, credential.Path = u.Scheme, u.Host, strings.TrimPrefix(u.Path, "/")
+			if u.User != nil {
+				credential.Username = u.User.Username()
+			}
+		}
+	}
+	return credential, scanner.Err()
+}
+
+type gitCredentialMatch struct {
+	id            int32
+	loginPassword *resources.LoginPassword
+}
+
+func findGitCredentials(ctx context.Context, resourceService services.ResourceService, credential *gitCredential) ([]*gitCredentialMatch, error) {
+	descriptions, err := resourceService.GetDescriptions(ctx, enum.LoginPassword)
+	if err != nil {
+		return nil, err
+	}
+	var matches []*gitCredentialMatch
+	for _, description := range descriptions {
+		info, err := resourceService.Get(ctx, description.Id)
+		if err != nil {
+			return nil, err
+		}
+		lp, ok := info.Resource.(*resources.LoginPassword)
+		if !ok || !credential.matches(lp) {
+			continue
+		}
+		matches = append(matches, &gitCredentialMatch{id: description.Id, loginPassword: lp})
+	}
+	return matches, nil
+}
+
+func storeGitCredential(ctx context.Context, resourceService services.ResourceService, credential *gitCredential) error {
+	if credential.Username == "" || credential.Password == "" {
+		return errors.New("username and password are required to store credential")
+	}
+	lp := resources.NewLoginPassword(credential.Username, credential.Password, credential.url())
+	if kind, ok := resources.KindOf(enum.LoginPassword); ok {
+		if err := kind.Validate(lp).Err(); err != nil {
+			return err
+		}
+	}
+	matches, err := findGitCredentials(ctx, resourceService, credential)
+	if err != nil {
+		return err
+	}
+	meta := []byte(fmt.Sprintf("git: %s", credential.Host))
+	if len(matches) != 0 {
+		lp.TotpId = matches[0].loginPassword.TotpId
+		lp.CustomFields = matches[0].loginPassword.CustomFields
+		data, err := json.Marshal(lp)
+		if err != nil {
+			return err
+		}
+		return resourceService.Update(ctx, matches[0].id, enum.LoginPassword, data, meta)
+	}
+	data, err := json.Marshal(lp)
+	if err != nil {
+		return err
+	}
+	_, err = resourceService.Save(ctx, enum.LoginPassword, data, meta)
+	return err
+}
+
+func (c *gitCredential) url() string {
+	if c.Protocol == "" {
+		return c.Host
+	}
+	return fmt.Sprintf("%s://%s", c.Protocol, c.Host)
+}
+
+// matches compares host, protocol and username of the credential with LoginPassword,
+// protocol is not checked if the url of LoginPassword has no scheme
+func (c *gitCredential) matches(lp *resources.LoginPassword) bool {
+	if lp.Url == "" {
+		return false
+	}
+	rawUrl := lp.Url
+	if !strings.Contains(rawUrl, "://") {
+		rawUrl = "//" + rawUrl
+	}
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return false
+	}
+	if !strings.EqualFold(u.Host, c.Host) {
+		return false
+	}
+	if u.Scheme != "" && c.Protocol != "" && !strings.EqualFold(u.Scheme, c.Protocol) {
+		return false
+	}
+	return c.Username == "" || c.Username == lp.Login
+}
@@ -0,0 +1,80 @@
+package modes
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"ydx-goadv-gophkeeper/internal/client/mocks/services"
+	"ydx-goadv-gophkeeper/internal/client/model/resources"
+	srvmodel "ydx-goadv-gophkeeper/internal/server/model"
+	"ydx-goadv-gophkeeper/pkg/model/enum"
+)
+
+func TestGitCredential_Matches(t *testing.T) {
+	tests := []struct {
+		name       string
+		credential string
+		lp         resources.LoginPassword
+		expected   bool
+	}{
+		{
+			name:       "same host and protocol",
+			credential: "protocol=https\nhost=github.com\n\n",
+			lp:         resources.LoginPassword{Login: "git", Url: "https://github.com"},
+			expected:   true,
+		},
+		{
+			name:       "url without scheme",
+			credential: "protocol=https\nhost=GitHub.com\nusername=git\n",
+			lp:         resources.LoginPassword{Login: "git", Url: "github.com"},
+			expected:   true,
+		},
+		{
+			name:       "another protocol",
+			credential: "protocol=http\nhost=github.com\n",
+			lp:         resources.LoginPassword{Login: "git", Url: "https://github.com"},
+			expected:   false,
+		},
+		{
+			name:       "another username",
+			credential: "url=https://bob@gitlab.com/repo.git\n",
+			lp:         resources.LoginPassword{Login: "alice", Url: "https://gitlab.com"},
+			expected:   false,
+		},
+		{
+			name:       "empty url",
+			credential: "protocol=https\nhost=github.com\n",
+			lp:         resources.LoginPassword{Login: "git"},
+			expected:   false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			credential, err := readGitCredential(strings.NewReader(test.credential))
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, credential.matches(&test.lp))
+		})
+	}
+}
+
+func TestGitCredential_Erase(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	resourceService := services.NewMockResourceService(ctrl)
+
+	resourceService.EXPECT().GetDescriptions(gomock.Any(), enum.LoginPassword).Return([]*srvmodel.ResourceDescription{{Id: 1}, {Id: 2}}, nil)
+	resourceService.EXPECT().Get(gomock.Any(), int32(1)).
+		Return(&resources.Info{Resource: resources.NewLoginPassword("git", "rejected", "https://github.com")}, nil)
+	resourceService.EXPECT().Get(gomock.Any(), int32(2)).
+		Return(&resources.Info{Resource: resources.NewLoginPassword("git", "valid", "https://github.com")}, nil)
+	// the entry of the same host with another password is kept
+	resourceService.EXPECT().Delete(gomock.Any(), int32(1)).Return(nil)
+
+	in := strings.NewReader("protocol=https\nhost=github.com\nusername=git\npassword=rejected\n\n")
+	assert.NoError(t, GitCredential(context.Background(), resourceService, []string{gitErase}, in, nil))
+}
@@ -0,0 +1,200 @@
+package modes
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"text/template"
+
+	"github.com/spf13/pflag"
+
+	"ydx-goadv-gophkeeper/internal/client/services"
+	"ydx-goadv-gophkeeper/pkg/model/enum"
+)
+
+const (
+	fileContentField = "content"
+	filePathField    = "path"
+)
+
+// Inject renders template with secret references:
+// gophkeeper inject -i app.tpl -o app.conf
+// where template contains {{ gophkeeper "42" "password" }}. File resources can be inlined
+// by {{ gophkeeper "7" "content" }} or written to the temp file by {{ gophkeeper "7" "path" }}.
+// The temp dir is removed if rendering fails or no file is written to it.
+func Inject(ctx context.Context, resolver services.SecretResolver, resourceService services.ResourceService, args []string) (err error) {
+	flags := pflag.NewFlagSet("inject", pflag.ContinueOnError)
+	flags.ParseErrorsWhitelist.UnknownFlags = true
+	input := flags.StringP("input", "i", "", "template file path")
+	output := flags.StringP("output", "o", "", "output file path, stdout if empty")
+	dryRun := flags.Bool("dry-run", false, "report which references would resolve without rendering")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *input == "" {
+		return errors.New("template is not specified, usage: gophkeeper inject -i app.tpl -o app.conf [--dry-run]")
+	}
+	tplBytes, err := os.ReadFile(*input)
+	if err != nil {
+		return fmt.Errorf("failed to read template '%s': %v", *input, err)
+	}
+
+	injector := &injector{ctx: ctx, resolver: resolver, resourceService: resourceService, dryRun: *dryRun}
+	defer func() {
+		if err != nil || len(injector.tempFiles) == 0 {
+			injector.removeTempDir()
+		}
+	}()
+	tpl, err := template.New(filepath.Base(*input)).
+		Funcs(template.FuncMap{"gophkeeper": injector.resolve}).
+		Parse(string(tplBytes))
+	if err != nil {
+		return fmt.Errorf("failed to parse template '%s': %v", *input, err)
+	}
+	var rendered bytes.Buffer
+	if err = tpl.Execute(&rendered, nil); err != nil {
+		return fmt.Errorf("failed to render template '%s': %v", *input, err)
+	}
+
+	if *dryRun {
+		return injector.report(os.Stdout)
+	}
+	for _, path := range injector.tempFiles {
+		fmt.Fprintf(os.Stderr, "file resource is written to: %s\n", path)
+	}
+	if *output == "" {
+		_, err = rendered.WriteTo(os.Stdout)
+		return err
+	}
+	return writePrivateFile(*output, rendered.Bytes())
+}
+
+type injector struct {
+	ctx             context.Context
+	resolver        services.SecretResolver
+	resourceService services.ResourceService
+	dryRun          bool
+	tempDir         string
+	// tempFiles are the written files by resource id, so every file is downloaded once
+	tempFiles map[int32]string
+	refs      []string
+	failures  map[string]error
+}
+
+func (in *injector) resolve(id any, field string) (string, error) {
+	resId, err := strconv.ParseInt(fmt.Sprint(id), 10, 32)
+	if err != nil {
+		return "", fmt.Errorf("resource id '%v' is invalid: %v", id, err)
+	}
+	value, err := in.resolveField(int32(resId), field)
+	if !in.dryRun {
+		return value, err
+	}
+	ref := fmt.Sprintf("%d/%s", resId, field)
+	in.refs = append(in.refs, ref)
+	if err != nil {
+		if in.failures == nil {
+			in.failures = make(map[string]error)
+		}
+		in.failures[ref] = err
+	}
+	return "", nil
+}
+
+func (in *injector) resolveField(resId int32, field string) (string, error) {
+	switch field {
+	case fileContentField, filePathField:
+		if in.dryRun {
+			info, err := in.resourceService.Get(in.ctx, resId)
+			if err == nil && info.Resource.Type() != enum.File {
+				err = fmt.Errorf("resource '%d' is not a file", resId)
+			}
+			return "", err
+		}
+		if field == filePathField {
+			return in.downloadFile(resId)
+		}
+		var content bytes.Buffer
+		if err := in.resourceService.WriteFile(in.ctx, resId, &content); err != nil {
+			return "", fmt.Errorf("failed to get file resource '%d': %v", resId, err)
+		}
+		return content.String(), nil
+	default:
+		return in.resolver.ResolveField(in.ctx, resId, field)
+	}
+}
+
+func (in *injector) downloadFile(resId int32) (string, error) {
+	if path, ok := in.tempFiles[resId]; ok {
+		return path, nil
+	}
+	if in.tempDir == "" {
+		dir, err := os.MkdirTemp("", "gophkeeper-inject-")
+		if err != nil {
+			return "", fmt.Errorf("failed to create temp dir: %v", err)
+		}
+		in.tempDir = dir
+	}
+	path := filepath.Join(in.tempDir, strconv.Itoa(int(resId)))
+	if err := in.resourceService.GetFileTo(in.ctx, resId, path); err != nil {
+		return "", fmt.Errorf("failed to get file resource '%d': %v", resId, err)
+	}
+	if err := os.Chmod(path, 0600); err != nil {
+		return "", err
+	}
+	if in.tempFiles == nil {
+		in.tempFiles = make(map[int32]string)
+	}
+	in.tempFiles[resId] = path
+	return path, nil
+}
+
+func (in *injector) removeTempDir() {
+	if in.tempDir == "" {
+		return
+	}
+	if err := os.RemoveAll(in.tempDir); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to remove temp dir '%s': %v\n", in.tempDir, err)
+	}
+	in.tempFiles = nil
+}
+
+func (in *injector) report(out io.Writer) error {
+	if len(in.refs) == 0 {
+		fmt.Fprintln(out, "there are no references")
+		return nil
+	}
+	for _, ref := range in.refs {
+		if err, ok := in.failures[ref]; ok {
+			fmt.Fprintf(out, "%s: failed: %v\n", ref, err)
+			continue
+		}
+		fmt.Fprintf(out, "%s: ok\n", ref)
+	}
+	if len(in.failures) > 0 {
+		return fmt.Errorf("%d of %d references can't be resolved", len(in.failures), len(in.refs))
+	}
+	return nil
+}
+
+// writePrivateFile writes data to the file readable only by the owner
+func writePrivateFile(path string, data []byte) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open '%s': %v", path, err)
+	}
+	defer file.Close()
+	// permissions of the existing file are not changed by OpenFile
+	if err = file.Chmod(0600); err != nil {
+		return fmt.Errorf("failed to set permissions of '%s': %v", path, err)
+	}
+	if _, err = file.Write(data); err != nil {
+		return fmt.Errorf("failed to write '%s': %v", path, err)
+	}
+	return nil
+}
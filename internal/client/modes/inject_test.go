@@ -0,0 +1,135 @@
+package modes
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"ydx-goadv-gophkeeper/internal/client/mocks/services"
+)
+
+func TestInject(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		prepare  func(resolver *services.MockSecretResolver, resourceService *services.MockResourceService)
+		expected string
+		err      string
+	}{
+		{
+			name:     "fields are substituted",
+			template: `user={{ gophkeeper "42" "login" }} password={{ gophkeeper 42 "password" }}`,
+			prepare: func(resolver *services.MockSecretResolver, _ *services.MockResourceService) {
+				resolver.EXPECT().ResolveField(gomock.Any(), int32(42), "login").Return("alice", nil)
+				resolver.EXPECT().ResolveField(gomock.Any(), int32(42), "password").Return("qwerty", nil)
+			},
+			expected: "user=alice password=qwerty",
+		},
+		{
+			name:     "file content is inlined",
+			template: `cert: {{ gophkeeper "7" "content" }}`,
+			prepare: func(_ *services.MockSecretResolver, resourceService *services.MockResourceService) {
+				resourceService.EXPECT().WriteFile(gomock.Any(), int32(7), gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ int32, w io.Writer) error {
+						_, err := w.Write([]byte("PEM"))
+						return err
+					})
+			},
+			expected: "cert: PEM",
+		},
+		{
+			name:     "unknown reference",
+			template: `password={{ gophkeeper "43" "password" }}`,
+			prepare: func(resolver *services.MockSecretResolver, _ *services.MockResourceService) {
+				resolver.EXPECT().ResolveField(gomock.Any(), int32(43), "password").
+					Return("", errors.New("failed to resolve resource '43': resource not found"))
+			},
+			err: "resource '43': resource not found",
+		},
+		{
+			name:     "invalid id",
+			template: `password={{ gophkeeper "abc" "password" }}`,
+			err:      "resource id 'abc' is invalid",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			resolver := services.NewMockSecretResolver(ctrl)
+			resourceService := services.NewMockResourceService(ctrl)
+			if test.prepare != nil {
+				test.prepare(resolver, resourceService)
+			}
+			dir := t.TempDir()
+			input, output := filepath.Join(dir, "app.tpl"), filepath.Join(dir, "app.conf")
+			assert.NoError(t, os.WriteFile(input, []byte(test.template), 0600))
+
+			err := Inject(context.Background(), resolver, resourceService, []string{"-i", input, "-o", output})
+			if test.err != "" {
+				assert.ErrorContains(t, err, test.err)
+				_, err = os.Stat(output)
+				assert.True(t, os.IsNotExist(err))
+				return
+			}
+			assert.NoError(t, err)
+			content, err := os.ReadFile(output)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, string(content))
+			stat, err := os.Stat(output)
+			assert.NoError(t, err)
+			assert.Equal(t, os.FileMode(0600), stat.Mode().Perm())
+		})
+	}
+}
+
+func TestInject_FilePath(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	resolver := services.NewMockSecretResolver(ctrl)
+	resourceService := services.NewMockResourceService(ctrl)
+	var written string
+	// file is downloaded once and is kept when its content is inlined too
+	resourceService.EXPECT().GetFileTo(gomock.Any(), int32(7), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ int32, path string) error {
+			written = path
+			return os.WriteFile(path, []byte("PEM"), 0644)
+		})
+	resourceService.EXPECT().WriteFile(gomock.Any(), int32(7), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ int32, w io.Writer) error {
+			_, err := w.Write([]byte("PEM"))
+			return err
+		})
+	dir := t.TempDir()
+	input, output := filepath.Join(dir, "app.tpl"), filepath.Join(dir, "app.conf")
+	template := `{{ gophkeeper "7" "path" }} {{ gophkeeper "7" "content" }} {{ gophkeeper "7" "path" }}`
+	assert.NoError(t, os.WriteFile(input, []byte(template), 0600))
+	assert.NoError(t, Inject(context.Background(), resolver, resourceService, []string{"-i", input, "-o", output}))
+	defer os.RemoveAll(filepath.Dir(written))
+	content, err := os.ReadFile(output)
+	assert.NoError(t, err)
+	assert.Equal(t, written+" PEM "+written, string(content))
+	stat, err := os.Stat(written)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), stat.Mode().Perm())
+
+	// temp dir is removed if rendering fails
+	resourceService.EXPECT().GetFileTo(gomock.Any(), int32(7), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ int32, path string) error {
+			written = path
+			return os.WriteFile(path, []byte("PEM"), 0600)
+		})
+	resolver.EXPECT().ResolveField(gomock.Any(), int32(43), "password").Return("", errors.New("resource not found"))
+	template = `{{ gophkeeper "7" "path" }} {{ gophkeeper "43" "password" }}`
+	assert.NoError(t, os.WriteFile(input, []byte(template), 0600))
+	assert.Error(t, Inject(context.Background(), resolver, resourceService, []string{"-i", input, "-o", output}))
+	_, err = os.Stat(filepath.Dir(written))
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,88 @@
+package modes
+
+import (
+	"bytes"
+	"io"
+	"sort"
+	"sync"
+)
+
+const mask = "*****"
+
+// maskWriter replaces secrets in the written output, only the tail which may start a secret is held back,
+// so secrets split between several writes and multi-line secrets are masked too
+type maskWriter struct {
+	mu      sync.Mutex
+	out     io.Writer
+	secrets [][]byte
+	buf     bytes.Buffer
+}
+
+func newMaskWriter(out io.Writer, secrets []string) *maskWriter {
+	w := &maskWriter{out: out}
+	for _, secret := range secrets {
+		if secret != "" {
+			w.secrets = append(w.secrets, []byte(secret))
+		}
+	}
+	// longer secrets first, so the secret which contains another one is masked entirely
+	sort.Slice(w.secrets, func(i, j int) bool {
+		return len(w.secrets[i]) > len(w.secrets[j])
+	})
+	return w
+}
+
+func (w *maskWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.buf.Write(p)
+	if err := w.flush(false); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Close writes the rest of the buffered output
+func (w *maskWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.flush(true)
+}
+
+// flush writes masked output up to the tail which is the beginning of a secret, or everything if final
+func (w *maskWriter) flush(final bool) error {
+	data := w.buf.Bytes()
+	out := make([]byte, 0, len(data))
+	i := 0
+Loop:
+	for i < len(data) {
+		if !final && w.startsSecret(data[i:]) {
+			break
+		}
+		for _, secret := range w.secrets {
+			if bytes.HasPrefix(data[i:], secret) {
+				out = append(out, mask...)
+				i += len(secret)
+				continue Loop
+			}
+		}
+		out = append(out, data[i])
+		i++
+	}
+	w.buf.Next(i)
+	if len(out) == 0 {
+		return nil
+	}
+	_, err := w.out.Write(out)
+	return err
+}
+
+// startsSecret reports whether the tail is the incomplete beginning of a secret
+func (w *maskWriter) startsSecret(tail []byte) bool {
+	for _, secret := range w.secrets {
+		if len(secret) > len(tail) && bytes.HasPrefix(secret, tail) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,68 @@
+package modes
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaskWriter(t *testing.T) {
+	tests := []struct {
+		name     string
+		secrets  []string
+		writes   []string
+		expected string
+	}{
+		{
+			name:     "secret in one write",
+			secrets:  []string{"qwerty"},
+			writes:   []string{"password is qwerty\n"},
+			expected: "password is *****\n",
+		},
+		{
+			name:     "secret split between writes",
+			secrets:  []string{"qwerty"},
+			writes:   []string{"password is qw", "erty\nnext line"},
+			expected: "password is *****\nnext line",
+		},
+		{
+			name:     "nested secrets",
+			secrets:  []string{"abc", "abcdef", ""},
+			writes:   []string{"abcdef abc\n"},
+			expected: "***** *****\n",
+		},
+		{
+			name:     "multi-line secret split between writes",
+			secrets:  []string{"-----BEGIN KEY-----\nabc\n-----END KEY-----"},
+			writes:   []string{"key: -----BEGIN KEY-----\n", "abc\n-----END", " KEY-----\n"},
+			expected: "key: *****\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var out bytes.Buffer
+			w := newMaskWriter(&out, test.secrets)
+			for _, write := range test.writes {
+				n, err := w.Write([]byte(write))
+				assert.NoError(t, err)
+				assert.Equal(t, len(write), n)
+			}
+			assert.NoError(t, w.Close())
+			assert.Equal(t, test.expected, out.String())
+		})
+	}
+}
+
+func TestMaskWriter_FlushWithoutNewline(t *testing.T) {
+	var out bytes.Buffer
+	w := newMaskWriter(&out, []string{"qwerty"})
+	_, err := w.Write([]byte("progress 50% qw"))
+	assert.NoError(t, err)
+	// only the possible beginning of the secret is held back
+	assert.Equal(t, "progress 50% ", out.String())
+	_, err = w.Write([]byte("ertz"))
+	assert.NoError(t, err)
+	assert.Equal(t, "progress 50% qwertz", out.String())
+}
@@ -0,0 +1,33 @@
+package modes
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"syscall"
+
+	"golang.org/x/term"
+)
+
+var stdinReader = bufio.NewReader(os.Stdin)
+
+// prompts are written to stderr, so stdout of the modes stays clean for pipes
+func readString(label string) (string, error) {
+	fmt.Fprintf(os.Stderr, "%s\n-> ", label)
+	line, err := stdinReader.ReadString('\n')
+	if err != nil && line == "" {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func readSecret(label string) ([]byte, error) {
+	fmt.Fprintf(os.Stderr, "%s\n-> ", label)
+	secret, err := term.ReadPassword(int(syscall.Stdin))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", label, err)
+	}
+	return secret, nil
+}
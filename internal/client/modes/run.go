@@ -0,0 +1,66 @@
+package modes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/pflag"
+
+	"ydx-goadv-gophkeeper/internal/client/services"
+)
+
+// RunWithSecrets starts the command with secrets injected into its environment:
+// gophkeeper run --env DB_PASS=ref://42/password -- ./migrate
+// It returns exit code of the command.
+func RunWithSecrets(ctx context.Context, resolver services.SecretResolver, args []string) (int, error) {
+	flags := pflag.NewFlagSet("run", pflag.ContinueOnError)
+	// global flags like server port are parsed by the app config
+	flags.ParseErrorsWhitelist.UnknownFlags = true
+	envRefs := flags.StringArrayP("env", "e", nil, "environment variable in format NAME=ref://[id]/[field]")
+	if err := flags.Parse(args); err != nil {
+		return 0, err
+	}
+	command := flags.Args()
+	if len(command) == 0 {
+		return 0, errors.New("command is empty, usage: gophkeeper run --env NAME=ref://[id]/[field] -- [command] [args]")
+	}
+
+	env := os.Environ()
+	secrets := make([]string, 0, len(*envRefs))
+	for _, envRef := range *envRefs {
+		name, ref, ok := strings.Cut(envRef, "=")
+		if !ok || name == "" {
+			return 0, fmt.Errorf("env '%s' must be in format NAME=ref://[id]/[field]", envRef)
+		}
+		secret, err := resolver.Resolve(ctx, ref)
+		if err != nil {
+			return 0, err
+		}
+		env = append(env, fmt.Sprintf("%s=%s", name, secret))
+		secrets = append(secrets, secret)
+	}
+
+	stdout := newMaskWriter(os.Stdout, secrets)
+	defer stdout.Close()
+	stderr := newMaskWriter(os.Stderr, secrets)
+	defer stderr.Close()
+
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	cmd.Env = env
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode(), nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to run '%s': %v", command[0], err)
+	}
+	return 0, nil
+}
@@ -0,0 +1,77 @@
+package modes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/spf13/pflag"
+
+	"ydx-goadv-gophkeeper/internal/client/model/resources"
+	"ydx-goadv-gophkeeper/internal/client/services"
+	"ydx-goadv-gophkeeper/pkg/model/enum"
+)
+
+var ErrValidation = errors.New("resource validation failed")
+
+// saveResult is written to stdout as json, errors are set if validation failed
+type saveResult struct {
+	Id       int32                  `json:"id,omitempty"`
+	Errors   []resources.FieldError `json:"errors,omitempty"`
+	Warnings []resources.FieldError `json:"warnings,omitempty"`
+}
+
+// Save reads resource json data from input, validates it by type and saves or updates it:
+// echo '{"number":"4111 1111 1111 1111","expireAt":"01/30"}' | gophkeeper save -t bc -d "main card"
+func Save(ctx context.Context, resourceService services.ResourceService, args []string, in io.Reader, out io.Writer) error {
+	flags := pflag.NewFlagSet("save", pflag.ContinueOnError)
+	alias := flags.StringP("type", "t", "", "resource type alias")
+	description := flags.StringP("description", "d", "", "resource description")
+	id := flags.Int32("id", 0, "id of the resource to update")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	kind, ok := resources.KindByAlias(*alias)
+	if !ok || kind.Type == enum.File {
+		return fmt.Errorf("resource type '%s' is not supported, usage: gophkeeper save -t lp -d description [--id 42] < data.json", *alias)
+	}
+
+	resource := kind.New()
+	decoder := json.NewDecoder(in)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(resource); err != nil {
+		return writeSaveResult(out, saveResult{Errors: []resources.FieldError{{Field: "data", Message: err.Error()}}})
+	}
+	validation := kind.Validate(resource)
+	result := saveResult{Errors: validation.Errors, Warnings: validation.Warnings}
+	if len(result.Errors) != 0 {
+		return writeSaveResult(out, result)
+	}
+
+	data, err := json.Marshal(resource)
+	if err != nil {
+		return err
+	}
+	if *id != 0 {
+		err = resourceService.Update(ctx, *id, kind.Type, data, []byte(*description))
+		result.Id = *id
+	} else {
+		result.Id, err = resourceService.Save(ctx, kind.Type, data, []byte(*description))
+	}
+	if err != nil {
+		return err
+	}
+	return writeSaveResult(out, result)
+}
+
+func writeSaveResult(out io.Writer, result saveResult) error {
+	if err := json.NewEncoder(out).Encode(result); err != nil {
+		return err
+	}
+	if len(result.Errors) != 0 {
+		return ErrValidation
+	}
+	return nil
+}
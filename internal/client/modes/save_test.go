@@ -0,0 +1,35 @@
+package modes
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"ydx-goadv-gophkeeper/internal/client/mocks/services"
+	"ydx-goadv-gophkeeper/pkg/model/enum"
+)
+
+func TestSave(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	resourceService := services.NewMockResourceService(ctrl)
+
+	var out bytes.Buffer
+	err := Save(context.Background(), resourceService, []string{"-t", "bc"},
+		strings.NewReader(`{"number":"4111111111111112","expireAt":"01/30"}`), &out)
+	assert.ErrorIs(t, err, ErrValidation)
+	assert.JSONEq(t, `{"errors":[{"field":"number","message":"failed Luhn check"}]}`, out.String())
+
+	out.Reset()
+	resourceService.EXPECT().
+		Save(gomock.Any(), enum.BankCard, []byte(`{"number":"4111111111111111","expireAt":"01/30","brand":"Visa"}`), []byte("main")).
+		Return(int32(42), nil)
+	err = Save(context.Background(), resourceService, []string{"-t", "bc", "-d", "main"},
+		strings.NewReader(`{"number":"4111 1111 1111 1111","expireAt":"01/30"}`), &out)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"id":42}`, out.String())
+}
@@ -0,0 +1,43 @@
+package modes
+
+import (
+	"fmt"
+
+	"ydx-goadv-gophkeeper/internal/client/agent"
+	"ydx-goadv-gophkeeper/internal/client/configs"
+	"ydx-goadv-gophkeeper/internal/client/model"
+	"ydx-goadv-gophkeeper/internal/client/services"
+)
+
+// Session prefers the unlocked agent: its session token is reused and data is encrypted by the agent.
+// Without the agent the private key is read locally, passphrase is asked if the key is encrypted.
+func Session(cfg *configs.AppConfig, agentClient agent.Client, tokenHolder *model.TokenHolder) (services.CryptService, error) {
+	if status, err := agentClient.Status(); err == nil && !status.Locked {
+		token, err := agentClient.Token()
+		if err != nil {
+			return nil, err
+		}
+		tokenHolder.Set(token)
+		return agentClient, nil
+	}
+	if cfg.PrivateKeyEncrypted {
+		passphrase, err := readSecret("private key passphrase:")
+		if err != nil {
+			return nil, err
+		}
+		if err = cfg.UnlockPrivateKey(passphrase); err != nil {
+			return nil, err
+		}
+	}
+	return services.NewCryptService(cfg.PrivateKey), nil
+}
+
+// AgentSession is used by the non-interactive modes, they can't ask for credentials
+func AgentSession(agentClient agent.Client, tokenHolder *model.TokenHolder) (services.CryptService, error) {
+	token, err := agentClient.Token()
+	if err != nil {
+		return nil, fmt.Errorf("unlocked agent is required, start it by 'gophkeeper agent': %v", err)
+	}
+	tokenHolder.Set(token)
+	return agentClient, nil
+}
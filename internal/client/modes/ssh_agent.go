@@ -0,0 +1,55 @@
+package modes
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/spf13/pflag"
+	"golang.org/x/crypto/ssh"
+	sshagent "golang.org/x/crypto/ssh/agent"
+
+	"ydx-goadv-gophkeeper/internal/client/agent"
+	"ydx-goadv-gophkeeper/internal/client/configs"
+	"ydx-goadv-gophkeeper/internal/client/services"
+)
+
+// RunSshAgent starts ssh-agent compatible listener signing with SshKey resources of the vault:
+// gophkeeper ssh-agent [--socket path] [--confirm]
+func RunSshAgent(ctx context.Context, cfg *configs.AppConfig, resourceService services.ResourceService, args []string) error {
+	flags := pflag.NewFlagSet("ssh-agent", pflag.ContinueOnError)
+	flags.ParseErrorsWhitelist.UnknownFlags = true
+	socketPath := flags.String("socket", filepath.Join(filepath.Dir(cfg.AgentSocket), "ssh-agent.sock"), "ssh agent socket path")
+	confirm := flags.Bool("confirm", false, "ask for confirmation on every key usage")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	var confirmFunc agent.ConfirmFunc
+	if *confirm {
+		confirmFunc = confirmKeyUsage()
+	}
+	fmt.Printf("export SSH_AUTH_SOCK=%s\n", *socketPath)
+	return agent.NewSshAgent(resourceService, confirmFunc).Serve(ctx, *socketPath)
+}
+
+// confirmKeyUsage asks in the terminal of the running ssh agent, one question at a time
+func confirmKeyUsage() agent.ConfirmFunc {
+	mu := &sync.Mutex{}
+	return func(key *sshagent.Key) bool {
+		mu.Lock()
+		defer mu.Unlock()
+		publicKey, err := ssh.ParsePublicKey(key.Blob)
+		if err != nil {
+			return false
+		}
+		answer, err := readString(fmt.Sprintf("allow signing with '%s' %s? [y/N]", key.Comment, ssh.FingerprintSHA256(publicKey)))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to read confirmation: %v\n", err)
+			return false
+		}
+		return strings.EqualFold(strings.TrimSpace(answer), "y")
+	}
+}
@@ -0,0 +1,147 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	scoreExact      = 100
+	scoreWord       = 80
+	scorePrefix     = 60
+	scoreSubstring  = 40
+	scoreTypo       = 30
+	scoreSubsequent = 20
+)
+
+// matchScore scores the term match of the value, both are lower case, 0 is no match
+func matchScore(term string, value string) int {
+	if term == "" || value == "" {
+		return 0
+	}
+	if value == term {
+		return scoreExact
+	}
+	words := splitWords(value)
+	best := 0
+	for _, word := range words {
+		switch {
+		case word == term:
+			return scoreWord
+		case strings.HasPrefix(word, term):
+			best = max(best, scorePrefix)
+		}
+	}
+	if best != 0 {
+		return best
+	}
+	if strings.Contains(value, term) {
+		return scoreSubstring
+	}
+	if typos := maxTypos(term); typos != 0 {
+		for _, word := range words {
+			if distance := editDistance(term, word, typos); distance <= typos {
+				best = max(best, scoreTypo-10*(distance-1))
+			}
+		}
+		if best != 0 {
+			return best
+		}
+	}
+	return subsequenceScore(term, value)
+}
+
+// maxTypos allows one typo for 4+ runes and two typos for 8+ runes terms
+func maxTypos(term string) int {
+	switch length := len([]rune(term)); {
+	case length >= 8:
+		return 2
+	case length >= 4:
+		return 1
+	}
+	return 0
+}
+
+// subsequenceScore matches term runes in order, compact matches score higher
+func subsequenceScore(term string, value string) int {
+	termRunes := []rune(term)
+	if len(termRunes) < 2 {
+		return 0
+	}
+	start, matched := -1, 0
+	for i, r := range []rune(value) {
+		if r != termRunes[matched] {
+			continue
+		}
+		if start < 0 {
+			start = i
+		}
+		matched++
+		if matched == len(termRunes) {
+			span := i - start + 1
+			return max(1, scoreSubsequent*len(termRunes)/span)
+		}
+	}
+	return 0
+}
+
+// editDistance is the optimal string alignment distance, it stops at limit+1
+func editDistance(a string, b string, limit int) int {
+	ar, br := []rune(a), []rune(b)
+	if abs(len(ar)-len(br)) > limit {
+		return limit + 1
+	}
+	prev2 := make([]int, len(br)+1)
+	prev := make([]int, len(br)+1)
+	current := make([]int, len(br)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ar); i++ {
+		current[0] = i
+		rowMin := current[0]
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+			current[j] = min(min(prev[j]+1, current[j-1]+1), prev[j-1]+cost)
+			if i > 1 && j > 1 && ar[i-1] == br[j-2] && ar[i-2] == br[j-1] {
+				current[j] = min(current[j], prev2[j-2]+1)
+			}
+			rowMin = min(rowMin, current[j])
+		}
+		if rowMin > limit {
+			return limit + 1
+		}
+		prev2, prev, current = prev, current, prev2
+	}
+	return prev[len(br)]
+}
+
+func splitWords(value string) []string {
+	return strings.FieldsFunc(value, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func max(a int, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func min(a int, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func abs(a int) int {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
@@ -0,0 +1,262 @@
+package search
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"ydx-goadv-gophkeeper/internal/client/model/resources"
+	"ydx-goadv-gophkeeper/pkg/model/enum"
+	"ydx-goadv-gophkeeper/pkg/model/registry"
+)
+
+const (
+	descriptionField = "description"
+	typeFilter       = "type"
+	idFilter         = "id"
+	tagFilter        = "tag"
+	folderFilter     = "folder"
+)
+
+// fieldWeights ranks matches of the main fields higher than custom fields
+var fieldWeights = map[string]int{
+	descriptionField: 3,
+	"login":          2,
+	"url":            2,
+	"name":           2,
+	"issuer":         2,
+}
+
+type Field struct {
+	Name  string
+	Value string
+}
+
+// Document holds searchable fields of decrypted resource, secrets and hidden custom fields are not indexed
+type Document struct {
+	Id          int32
+	Type        enum.ResourceType
+	Description string
+	Fields      []Field
+	Labels      resources.Labels
+}
+
+func NewDocument(id int32, resType enum.ResourceType, description string, labels resources.Labels, resource resources.ResourceClIFormatter) Document {
+	doc := Document{Id: id, Type: resType, Description: description, Labels: labels}
+	add := func(name string, values ...string) {
+		for _, value := range values {
+			if strings.TrimSpace(value) != "" {
+				doc.Fields = append(doc.Fields, Field{Name: name, Value: value})
+			}
+		}
+	}
+	switch res := resource.(type) {
+	case *resources.LoginPassword:
+		add("login", res.Login)
+		add("url", res.Url)
+	case *resources.BankCard:
+		add("name", res.Name+" "+res.Surname)
+		add("brand", res.Brand)
+	case *resources.File:
+		add("name", res.Name)
+	case *resources.SshKey:
+		add("comment", res.Comment)
+	case *resources.SecureNote:
+		add("text", res.Text)
+	case *resources.Totp:
+		add("issuer", res.Issuer)
+		add("account", res.Account)
+	case *resources.Identity:
+		add("name", res.FirstName+" "+res.LastName)
+		add("email", res.Email)
+		add("phone", res.Phone)
+		add("address", res.Addresses...)
+	}
+	if holder, ok := resource.(resources.CustomFieldsHolder); ok {
+		for _, field := range holder.Custom().Fields {
+			if field.Type != resources.HiddenField {
+				add(strings.ToLower(field.Name), field.Value)
+			}
+		}
+	}
+	return doc
+}
+
+func (d *Document) fields() []Field {
+	return append([]Field{{Name: descriptionField, Value: d.Description}}, d.Fields...)
+}
+
+type Result struct {
+	Document
+	Score int
+	// Match is the best matched field
+	Match Field
+}
+
+// Index is in-memory index of decrypted resources
+type Index struct {
+	docs map[int32]Document
+}
+
+func NewIndex() *Index {
+	return &Index{docs: make(map[int32]Document)}
+}
+
+func (i *Index) Put(doc Document) {
+	i.docs[doc.Id] = doc
+}
+
+// SetLabels updates labels of the indexed document, labels are updated without resource data
+func (i *Index) SetLabels(id int32, labels resources.Labels) {
+	if doc, ok := i.docs[id]; ok {
+		doc.Labels = labels
+		i.docs[id] = doc
+	}
+}
+
+func (i *Index) Remove(id int32) {
+	delete(i.docs, id)
+}
+
+func (i *Index) Len() int {
+	return len(i.docs)
+}
+
+type query struct {
+	terms   []string
+	types   []enum.ResourceType
+	ids     []int32
+	tags    []string
+	folders []string
+	filters []Field
+}
+
+// parseQuery splits query into fuzzy terms and 'key:value' filters, 'type' filter takes resource alias,
+// 'tag' and 'folder' filter labels, folder filter includes subfolders, other keys filter the field by name
+func parseQuery(raw string) (*query, error) {
+	q := &query{}
+	for _, token := range strings.Fields(strings.ToLower(raw)) {
+		key, value, ok := strings.Cut(token, ":")
+		if !ok || key == "" || value == "" || strings.HasPrefix(value, "//") {
+			q.terms = append(q.terms, token)
+			continue
+		}
+		switch key {
+		case typeFilter:
+			kind, ok := registry.ByAlias(value)
+			if !ok {
+				return nil, fmt.Errorf("type '%s' is not supported", value)
+			}
+			q.types = append(q.types, kind.Type)
+		case idFilter:
+			id, err := strconv.ParseInt(value, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("id '%s' is not a number", value)
+			}
+			q.ids = append(q.ids, int32(id))
+		case tagFilter:
+			q.tags = append(q.tags, value)
+		case folderFilter:
+			q.folders = append(q.folders, resources.NormalizeFolder(value))
+		default:
+			q.filters = append(q.filters, Field{Name: key, Value: value})
+		}
+	}
+	return q, nil
+}
+
+// Search returns documents matching all terms and filters ordered by score, limit <= 0 returns all
+func (i *Index) Search(rawQuery string, limit int) ([]Result, error) {
+	q, err := parseQuery(rawQuery)
+	if err != nil {
+		return nil, err
+	}
+	var results []Result
+	for _, doc := range i.docs {
+		if result, ok := q.match(doc); ok {
+			results = append(results, result)
+		}
+	}
+	sort.Slice(results, func(a, b int) bool {
+		if results[a].Score != results[b].Score {
+			return results[a].Score > results[b].Score
+		}
+		return results[a].Id < results[b].Id
+	})
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+func (q *query) match(doc Document) (Result, bool) {
+	result := Result{Document: doc}
+	if len(q.types) != 0 && !containsType(q.types, doc.Type) {
+		return result, false
+	}
+	if len(q.ids) != 0 && !containsId(q.ids, doc.Id) {
+		return result, false
+	}
+	for _, tag := range q.tags {
+		if !doc.Labels.HasTag(tag) {
+			return result, false
+		}
+	}
+	for _, folder := range q.folders {
+		if !doc.Labels.InFolder(folder, true) {
+			return result, false
+		}
+	}
+	fields := doc.fields()
+	bestScore := 0
+	score := func(term string, name string) bool {
+		termBest := 0
+		for _, field := range fields {
+			if name != "" && field.Name != name {
+				continue
+			}
+			weight, ok := fieldWeights[field.Name]
+			if !ok {
+				weight = 1
+			}
+			fieldScore := matchScore(term, strings.ToLower(field.Value)) * weight
+			termBest = max(termBest, fieldScore)
+			if fieldScore > bestScore {
+				bestScore = fieldScore
+				result.Match = field
+			}
+		}
+		result.Score += termBest
+		return termBest != 0
+	}
+	for _, filter := range q.filters {
+		if !score(filter.Value, filter.Name) {
+			return result, false
+		}
+	}
+	for _, term := range q.terms {
+		if !score(term, "") {
+			return result, false
+		}
+	}
+	return result, true
+}
+
+func containsType(types []enum.ResourceType, resType enum.ResourceType) bool {
+	for _, t := range types {
+		if t == resType {
+			return true
+		}
+	}
+	return false
+}
+
+func containsId(ids []int32, id int32) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"ydx-goadv-gophkeeper/internal/client/model/resources"
+	"ydx-goadv-gophkeeper/pkg/model/enum"
+)
+
+func TestMatchScore(t *testing.T) {
+	tests := []struct {
+		term     string
+		value    string
+		expected int
+	}{
+		{term: "github", value: "github", expected: scoreExact},
+		{term: "github", value: "work github account", expected: scoreWord},
+		{term: "git", value: "github account", expected: scorePrefix},
+		{term: "hub", value: "github", expected: scoreSubstring},
+		{term: "gihtub", value: "github", expected: scoreTypo},
+		{term: "gthb", value: "github", expected: 13},
+		{term: "gitlab", value: "github", expected: 0},
+		{term: "ab", value: "github", expected: 0},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, matchScore(test.term, test.value), test.term)
+	}
+}
+
+func TestIndex_Search(t *testing.T) {
+	index := NewIndex()
+	lp := resources.NewLoginPassword("octocat", "github", "https://github.com")
+	lp.Fields = []resources.CustomField{
+		{Name: "env", Type: resources.TextField, Value: "prod"},
+		{Name: "pin", Type: resources.HiddenField, Value: "1234"},
+	}
+	index.Put(NewDocument(1, enum.LoginPassword, "work account", resources.Labels{Folder: "work/dev", Tags: []string{"prod"}}, lp))
+	index.Put(NewDocument(2, enum.SecureNote, "github recovery codes", resources.Labels{}, resources.NewSecureNote("1234-5678")))
+	index.Put(NewDocument(3, enum.LoginPassword, "gitlab", resources.Labels{Folder: "work"}, resources.NewLoginPassword("octocat", "secret", "https://gitlab.com")))
+
+	ids := func(results []Result) []int32 {
+		var ids []int32
+		for _, result := range results {
+			ids = append(ids, result.Id)
+		}
+		return ids
+	}
+	results, err := index.Search("github", 0)
+	assert.NoError(t, err)
+	// description match is ranked higher, password is not indexed
+	assert.Equal(t, []int32{2, 1}, ids(results))
+	assert.Equal(t, Field{Name: "url", Value: "https://github.com"}, results[1].Match)
+
+	results, err = index.Search("octocat type:lp", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []int32{1, 3}, ids(results))
+
+	results, err = index.Search("env:prod", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []int32{1}, ids(results))
+
+	results, err = index.Search("1234", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []int32{2}, ids(results))
+
+	results, err = index.Search("octocat id:3", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []int32{3}, ids(results))
+
+	results, err = index.Search("octocat folder:work", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []int32{1, 3}, ids(results))
+
+	results, err = index.Search("tag:prod", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []int32{1}, ids(results))
+
+	index.SetLabels(1, resources.Labels{})
+	results, err = index.Search("octocat folder:work/dev", 0)
+	assert.NoError(t, err)
+	assert.Empty(t, results)
+
+	results, err = index.Search("octocat", 1)
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+
+	_, err = index.Search("type:unknown", 0)
+	assert.Error(t, err)
+}
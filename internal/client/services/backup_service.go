@@ -0,0 +1,456 @@
+package services
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"ydx-goadv-gophkeeper/internal/client/backup"
+	"ydx-goadv-gophkeeper/internal/client/model"
+	"ydx-goadv-gophkeeper/internal/client/model/resources"
+	"ydx-goadv-gophkeeper/pkg/logger"
+	restype "ydx-goadv-gophkeeper/pkg/model"
+	"ydx-goadv-gophkeeper/pkg/model/enum"
+)
+
+const (
+	manifestName = "manifest.json"
+	resourcesDir = "resources/"
+	filesDir     = "files/"
+)
+
+// ConflictPolicy defines how restored resource is saved if the vault has resource of the same type and description
+type ConflictPolicy string
+
+const (
+	ConflictSkip      ConflictPolicy = "skip"
+	ConflictOverwrite ConflictPolicy = "overwrite"
+	ConflictKeep      ConflictPolicy = "keep"
+)
+
+func ParseConflictPolicy(policy string) (ConflictPolicy, error) {
+	switch ConflictPolicy(policy) {
+	case ConflictSkip, ConflictOverwrite, ConflictKeep:
+		return ConflictPolicy(policy), nil
+	}
+	return "", fmt.Errorf("conflict policy '%s' is not supported, available: skip, overwrite, keep", policy)
+}
+
+//go:generate mockgen -source=backup_service.go -destination=../mocks/services/backup_service.go -package=services
+
+type BackupService interface {
+	// Export writes all resources and files as tar archive encrypted with the passphrase
+	Export(ctx context.Context, out io.Writer, passphrase []byte) (*model.BackupManifest, error)
+	// Restore checks archive checksums and saves its resources, resources equal to the vault ones are not saved
+	Restore(ctx context.Context, in io.Reader, passphrase []byte, onConflict ConflictPolicy) (*model.RestoreSummary, error)
+}
+
+type backupService struct {
+	log             *zap.SugaredLogger
+	resourceService ResourceService
+}
+
+type vaultResource struct {
+	id       int32
+	resource resources.ResourceClIFormatter
+}
+
+func NewBackupService(resourceService ResourceService) BackupService {
+	return &backupService{log: logger.NewLogger("backup-service"), resourceService: resourceService}
+}
+
+func (s *backupService) Export(ctx context.Context, out io.Writer, passphrase []byte) (*model.BackupManifest, error) {
+	descriptions, err := s.resourceService.GetDescriptions(ctx, enum.Nan)
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := backup.NewWriter(out, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	archive := tar.NewWriter(encrypted)
+	manifest := &model.BackupManifest{
+		Version:   model.BackupVersion,
+		CreatedAt: time.Now().UTC(),
+		Resources: make([]model.BackupResource, 0, len(descriptions)),
+		Checksums: make(map[string]string),
+	}
+	tmpDir, err := os.MkdirTemp("", "gophkeeper-export")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	for _, description := range descriptions {
+		info, err := s.resourceService.Get(ctx, description.Id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get resource %d: %w", description.Id, err)
+		}
+		data, err := json.Marshal(info.Resource)
+		if err != nil {
+			return nil, err
+		}
+		labels, err := resources.ParseLabels(description.Labels)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse labels of resource %d: %w", description.Id, err)
+		}
+		kind, _ := resources.KindOf(description.Type)
+		res := model.BackupResource{
+			Id:          description.Id,
+			Type:        kind.Alias,
+			Description: string(description.Meta),
+			CreatedAt:   description.CreatedAt,
+			UpdatedAt:   description.UpdatedAt,
+			DataPath:    fmt.Sprintf("%s%d.json", resourcesDir, description.Id),
+			Labels:      labels,
+		}
+		if err := writeEntry(archive, manifest, res.DataPath, bytes.NewReader(data), int64(len(data))); err != nil {
+			return nil, err
+		}
+		if description.Type == enum.File {
+			res.FilePath = fmt.Sprintf("%s%d", filesDir, description.Id)
+			if err := s.exportFile(ctx, archive, manifest, description.Id, res.FilePath, tmpDir); err != nil {
+				return nil, err
+			}
+		}
+		manifest.Resources = append(manifest.Resources, res)
+	}
+
+	manifestJson, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := writeEntry(archive, nil, manifestName, bytes.NewReader(manifestJson), int64(len(manifestJson))); err != nil {
+		return nil, err
+	}
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+	return manifest, encrypted.Close()
+}
+
+func (s *backupService) exportFile(ctx context.Context, archive *tar.Writer, manifest *model.BackupManifest, resId int32, name string, tmpDir string) error {
+	path := filepath.Join(tmpDir, fmt.Sprint(resId))
+	if err := s.resourceService.GetFileTo(ctx, resId, path); err != nil {
+		return fmt.Errorf("failed to get file %d: %w", resId, err)
+	}
+	defer os.Remove(path)
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	stat, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	return writeEntry(archive, manifest, name, file, stat.Size())
+}
+
+// writeEntry writes archive entry and adds its checksum to the manifest
+func writeEntry(archive *tar.Writer, manifest *model.BackupManifest, name string, content io.Reader, size int64) error {
+	modTime := time.Now()
+	if manifest != nil {
+		modTime = manifest.CreatedAt
+	}
+	if err := archive.WriteHeader(&tar.Header{Name: name, Mode: 0600, Size: size, ModTime: modTime}); err != nil {
+		return err
+	}
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(archive, hash), content); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	if manifest != nil {
+		manifest.Checksums[name] = hex.EncodeToString(hash.Sum(nil))
+	}
+	return nil
+}
+
+func (s *backupService) Restore(ctx context.Context, in io.Reader, passphrase []byte, onConflict ConflictPolicy) (*model.RestoreSummary, error) {
+	decrypted, err := backup.NewReader(in, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	tmpDir, err := os.MkdirTemp("", "gophkeeper-restore")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+	manifest, data, err := readArchive(tar.NewReader(decrypted), tmpDir)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := s.vaultResources(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// linked totp must be restored before login passwords to remap their ids
+	lpAlias := restype.TypeToArg[enum.LoginPassword]
+	sort.SliceStable(manifest.Resources, func(i, j int) bool {
+		return manifest.Resources[i].Type != lpAlias && manifest.Resources[j].Type == lpAlias
+	})
+	summary := &model.RestoreSummary{}
+	ids := make(map[int32]int32, len(manifest.Resources))
+	// labels of saved resources are updated at once, labels of skipped ones are kept
+	labels := make(map[int32]resources.Labels)
+	// resources except files are saved in atomic batches of at most MaxBatchSize operations,
+	// totp batches are saved before login passwords
+	batch := &restoreBatch{}
+	lpBatch := false
+	flush := func() error {
+		if len(batch.operations) == 0 {
+			return nil
+		}
+		results, err := s.resourceService.Batch(ctx, batch.operations, true)
+		if err != nil {
+			return fmt.Errorf("failed to restore resources: %w", err)
+		}
+		var failed error
+		for i, result := range results {
+			if result.Err != nil && (failed == nil || errors.Is(failed, ErrBatchAborted)) {
+				failed = fmt.Errorf("failed to restore resource %d: %w", batch.resources[i].Id, result.Err)
+			}
+		}
+		if failed != nil {
+			return failed
+		}
+		for i, result := range results {
+			res := batch.resources[i]
+			ids[res.Id] = result.Id
+			if batch.operations[i].Kind == model.BatchUpdate {
+				summary.Overwritten++
+				labels[result.Id] = res.Labels
+			} else {
+				summary.Restored++
+				if !res.Labels.IsEmpty() {
+					labels[result.Id] = res.Labels
+				}
+			}
+		}
+		batch = &restoreBatch{}
+		return nil
+	}
+	add := func(kind model.BatchKind, resId int32, resource resources.ResourceClIFormatter, res model.BackupResource) error {
+		if err := batch.add(kind, resId, resource, res); err != nil {
+			return err
+		}
+		if len(batch.operations) < MaxBatchSize {
+			return nil
+		}
+		return flush()
+	}
+	for _, res := range manifest.Resources {
+		kind, ok := resources.KindByAlias(res.Type)
+		if !ok {
+			summary.Warnings = append(summary.Warnings, fmt.Sprintf("resource %d of type '%s' is not supported", res.Id, res.Type))
+			continue
+		}
+		resource := kind.New()
+		if err := json.Unmarshal(data[res.DataPath], resource); err != nil {
+			return summary, fmt.Errorf("failed to parse resource %d: %w", res.Id, err)
+		}
+		if lp, ok := resource.(*resources.LoginPassword); ok {
+			if !lpBatch {
+				if err := flush(); err != nil {
+					return summary, err
+				}
+				lpBatch = true
+			}
+			if lp.TotpId != 0 {
+				totpId, ok := ids[lp.TotpId]
+				if !ok {
+					summary.Warnings = append(summary.Warnings, fmt.Sprintf("linked totp %d of resource %d is not restored", lp.TotpId, res.Id))
+				}
+				lp.TotpId = totpId
+			}
+		}
+
+		conflict, ok := existing[conflictKey(kind.Type, res.Description)]
+		if ok && dedupeKey(conflict.resource, res.Description) == dedupeKey(resource, res.Description) {
+			summary.Unchanged++
+			ids[res.Id] = conflict.id
+			continue
+		}
+		if ok && onConflict == ConflictSkip {
+			summary.Skipped++
+			ids[res.Id] = conflict.id
+			continue
+		}
+		if ok && onConflict == ConflictOverwrite {
+			if resource.Type() != enum.File {
+				if err := add(model.BatchUpdate, conflict.id, resource, res); err != nil {
+					return summary, err
+				}
+				continue
+			}
+			id, err := s.overwriteFile(ctx, conflict.id, resource, res, tmpDir)
+			if err != nil {
+				return summary, fmt.Errorf("failed to overwrite resource %d: %w", conflict.id, err)
+			}
+			summary.Overwritten++
+			ids[res.Id] = id
+			labels[id] = res.Labels
+			continue
+		}
+		if resource.Type() != enum.File {
+			if err := add(model.BatchCreate, 0, resource, res); err != nil {
+				return summary, err
+			}
+			continue
+		}
+		id, err := s.saveFile(ctx, resource.(*resources.File), res, tmpDir)
+		if err != nil {
+			return summary, fmt.Errorf("failed to restore resource %d: %w", res.Id, err)
+		}
+		summary.Restored++
+		ids[res.Id] = id
+		if !res.Labels.IsEmpty() {
+			labels[id] = res.Labels
+		}
+	}
+	if err := flush(); err != nil {
+		return summary, err
+	}
+	if len(labels) != 0 {
+		if err := s.resourceService.UpdateLabels(ctx, labels); err != nil {
+			return summary, fmt.Errorf("failed to restore labels: %w", err)
+		}
+	}
+	return summary, nil
+}
+
+// readArchive verifies checksums, resource jsons are returned by path and files are extracted to the dir
+func readArchive(archive *tar.Reader, dir string) (*model.BackupManifest, map[string][]byte, error) {
+	var manifest *model.BackupManifest
+	data := make(map[string][]byte)
+	checksums := make(map[string]string)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		hash := sha256.New()
+		switch {
+		case header.Name == manifestName:
+			manifest = &model.BackupManifest{}
+			if err := json.NewDecoder(archive).Decode(manifest); err != nil {
+				return nil, nil, fmt.Errorf("failed to parse manifest: %w", err)
+			}
+			continue
+		case strings.HasPrefix(header.Name, filesDir):
+			file, err := os.Create(filepath.Join(dir, filepath.Base(header.Name)))
+			if err != nil {
+				return nil, nil, err
+			}
+			_, err = io.Copy(io.MultiWriter(file, hash), archive)
+			file.Close()
+			if err != nil {
+				return nil, nil, err
+			}
+		default:
+			content, err := io.ReadAll(io.TeeReader(archive, hash))
+			if err != nil {
+				return nil, nil, err
+			}
+			data[header.Name] = content
+		}
+		checksums[header.Name] = hex.EncodeToString(hash.Sum(nil))
+	}
+	if manifest == nil {
+		return nil, nil, fmt.Errorf("archive has no %s", manifestName)
+	}
+	if manifest.Version != model.BackupVersion {
+		return nil, nil, fmt.Errorf("archive version %d is not supported", manifest.Version)
+	}
+	for path, checksum := range manifest.Checksums {
+		if checksums[path] != checksum {
+			return nil, nil, fmt.Errorf("checksum of %s does not match the manifest", path)
+		}
+	}
+	return manifest, data, nil
+}
+
+// vaultResources returns decrypted vault resources by conflictKey
+func (s *backupService) vaultResources(ctx context.Context) (map[string]vaultResource, error) {
+	descriptions, err := s.resourceService.GetDescriptions(ctx, enum.Nan)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]vaultResource, len(descriptions))
+	for _, description := range descriptions {
+		key := conflictKey(description.Type, string(description.Meta))
+		if _, ok := result[key]; ok {
+			continue
+		}
+		info, err := s.resourceService.Get(ctx, description.Id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get resource %d: %w", description.Id, err)
+		}
+		result[key] = vaultResource{id: description.Id, resource: info.Resource}
+	}
+	return result, nil
+}
+
+// saveFile uploads file with its original name
+func (s *backupService) saveFile(ctx context.Context, file *resources.File, res model.BackupResource, tmpDir string) (int32, error) {
+	dir := filepath.Join(tmpDir, fmt.Sprintf("%d-upload", res.Id))
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return 0, err
+	}
+	path := filepath.Join(dir, filepath.Base(file.Name))
+	if err := os.Rename(filepath.Join(tmpDir, filepath.Base(res.FilePath)), path); err != nil {
+		return 0, err
+	}
+	return s.resourceService.SaveFile(ctx, path, []byte(res.Description), file.Fields)
+}
+
+// overwriteFile deletes vault file and saves it again
+func (s *backupService) overwriteFile(ctx context.Context, resId int32, resource resources.ResourceClIFormatter, res model.BackupResource, tmpDir string) (int32, error) {
+	if err := s.resourceService.Delete(ctx, resId); err != nil {
+		return 0, err
+	}
+	return s.saveFile(ctx, resource.(*resources.File), res, tmpDir)
+}
+
+// restoreBatch keeps archive resources of the batch operations
+type restoreBatch struct {
+	operations []model.BatchOperation
+	resources  []model.BackupResource
+}
+
+func (b *restoreBatch) add(kind model.BatchKind, resId int32, resource resources.ResourceClIFormatter, res model.BackupResource) error {
+	data, err := json.Marshal(resource)
+	if err != nil {
+		return err
+	}
+	b.operations = append(b.operations, model.BatchOperation{
+		Kind: kind,
+		Id:   resId,
+		Type: resource.Type(),
+		Data: data,
+		Meta: []byte(res.Description),
+	})
+	b.resources = append(b.resources, res)
+	return nil
+}
+
+func conflictKey(resType enum.ResourceType, description string) string {
+	return fmt.Sprintf("%d:%s", resType, description)
+}
@@ -0,0 +1,141 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"ydx-goadv-gophkeeper/internal/client/mocks/services"
+	"ydx-goadv-gophkeeper/internal/client/model"
+	"ydx-goadv-gophkeeper/internal/client/model/resources"
+	clservices "ydx-goadv-gophkeeper/internal/client/services"
+	srvmodel "ydx-goadv-gophkeeper/internal/server/model"
+	"ydx-goadv-gophkeeper/pkg/model/enum"
+)
+
+func TestBackupService_ExportRestore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	resourceService := services.NewMockResourceService(ctrl)
+	passphrase := []byte("correct horse battery staple")
+
+	lp := resources.NewLoginPassword("octocat", "secret", "https://github.com")
+	lp.TotpId = 2
+	totp, err := resources.NewTotpFromUri("otpauth://totp/github:octocat?secret=JBSWY3DPEHPK3PXP&issuer=github")
+	assert.NoError(t, err)
+	file := &resources.File{Name: "notes.txt", Extension: ".txt", Size: 5}
+	labels := resources.Labels{Folder: "work", Tags: []string{"prod"}}
+	resourceService.EXPECT().GetDescriptions(gomock.Any(), enum.Nan).Return([]*srvmodel.ResourceDescription{
+		{Id: 1, Type: enum.LoginPassword, Meta: []byte("github"), Labels: []byte(`{"folder":"work","tags":["prod"]}`)},
+		{Id: 2, Type: enum.Totp, Meta: []byte("github otp")},
+		{Id: 3, Type: enum.File, Meta: []byte("notes")},
+	}, nil)
+	resourceService.EXPECT().Get(gomock.Any(), int32(1)).Return(&resources.Info{Resource: lp}, nil)
+	resourceService.EXPECT().Get(gomock.Any(), int32(2)).Return(&resources.Info{Resource: totp}, nil)
+	resourceService.EXPECT().Get(gomock.Any(), int32(3)).Return(&resources.Info{Resource: file}, nil)
+	resourceService.EXPECT().GetFileTo(gomock.Any(), int32(3), gomock.Any()).DoAndReturn(func(_ context.Context, _ int32, path string) error {
+		return os.WriteFile(path, []byte("hello"), 0600)
+	})
+
+	backupService := clservices.NewBackupService(resourceService)
+	var archive bytes.Buffer
+	manifest, err := backupService.Export(context.Background(), &archive, passphrase)
+	assert.NoError(t, err)
+	assert.Len(t, manifest.Resources, 3)
+	assert.Len(t, manifest.Checksums, 4)
+
+	_, err = backupService.Restore(context.Background(), bytes.NewReader(archive.Bytes()), []byte("wrong"), clservices.ConflictSkip)
+	assert.Error(t, err)
+
+	// restore to another account, linked totp id is remapped
+	resourceService.EXPECT().GetDescriptions(gomock.Any(), enum.Nan).Return(nil, nil)
+	totpJson, err := json.Marshal(totp)
+	assert.NoError(t, err)
+	resourceService.EXPECT().Batch(gomock.Any(), []model.BatchOperation{
+		{Kind: model.BatchCreate, Type: enum.Totp, Data: totpJson, Meta: []byte("github otp")},
+	}, true).Return([]model.BatchResult{{Id: 20}}, nil)
+	resourceService.EXPECT().SaveFile(gomock.Any(), gomock.Any(), []byte("notes"), gomock.Any()).DoAndReturn(func(_ context.Context, path string, _ []byte, _ []resources.CustomField) (int32, error) {
+		content, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		assert.Equal(t, "hello", string(content))
+		assert.Equal(t, "notes.txt", filepath.Base(path))
+		return 30, nil
+	})
+	restoredLp := *lp
+	restoredLp.TotpId = 20
+	lpJson, err := json.Marshal(&restoredLp)
+	assert.NoError(t, err)
+	resourceService.EXPECT().Batch(gomock.Any(), []model.BatchOperation{
+		{Kind: model.BatchCreate, Type: enum.LoginPassword, Data: lpJson, Meta: []byte("github")},
+	}, true).Return([]model.BatchResult{{Id: 10}}, nil)
+	resourceService.EXPECT().UpdateLabels(gomock.Any(), map[int32]resources.Labels{10: labels}).Return(nil)
+
+	summary, err := backupService.Restore(context.Background(), bytes.NewReader(archive.Bytes()), passphrase, clservices.ConflictSkip)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, summary.Restored)
+	assert.Empty(t, summary.Warnings)
+
+	// restore to the same account, changed login password is overwritten
+	changedLp := *lp
+	changedLp.Password = "changed"
+	resourceService.EXPECT().GetDescriptions(gomock.Any(), enum.Nan).Return([]*srvmodel.ResourceDescription{
+		{Id: 1, Type: enum.LoginPassword, Meta: []byte("github")},
+		{Id: 2, Type: enum.Totp, Meta: []byte("github otp")},
+		{Id: 3, Type: enum.File, Meta: []byte("notes")},
+	}, nil)
+	resourceService.EXPECT().Get(gomock.Any(), int32(1)).Return(&resources.Info{Resource: &changedLp}, nil)
+	resourceService.EXPECT().Get(gomock.Any(), int32(2)).Return(&resources.Info{Resource: totp}, nil)
+	resourceService.EXPECT().Get(gomock.Any(), int32(3)).Return(&resources.Info{Resource: file}, nil)
+	lpJson, err = json.Marshal(lp)
+	assert.NoError(t, err)
+	resourceService.EXPECT().Batch(gomock.Any(), []model.BatchOperation{
+		{Kind: model.BatchUpdate, Id: 1, Type: enum.LoginPassword, Data: lpJson, Meta: []byte("github")},
+	}, true).Return([]model.BatchResult{{Id: 1}}, nil)
+	resourceService.EXPECT().UpdateLabels(gomock.Any(), map[int32]resources.Labels{1: labels}).Return(nil)
+
+	summary, err = backupService.Restore(context.Background(), bytes.NewReader(archive.Bytes()), passphrase, clservices.ConflictOverwrite)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, summary.Overwritten)
+	assert.Equal(t, 2, summary.Unchanged)
+}
+
+func TestBackupService_RestoreSplitsBatches(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	resourceService := services.NewMockResourceService(ctrl)
+	passphrase := []byte("correct horse battery staple")
+
+	count := clservices.MaxBatchSize + 1
+	descriptions := make([]*srvmodel.ResourceDescription, 0, count)
+	for i := 1; i <= count; i++ {
+		descriptions = append(descriptions, &srvmodel.ResourceDescription{Id: int32(i), Type: enum.SecureNote, Meta: []byte(fmt.Sprintf("note %d", i))})
+	}
+	resourceService.EXPECT().GetDescriptions(gomock.Any(), enum.Nan).Return(descriptions, nil)
+	resourceService.EXPECT().Get(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, id int32) (*resources.Info, error) {
+		return &resources.Info{Resource: resources.NewSecureNote(fmt.Sprintf("text %d", id))}, nil
+	}).Times(count)
+
+	backupService := clservices.NewBackupService(resourceService)
+	var archive bytes.Buffer
+	_, err := backupService.Export(context.Background(), &archive, passphrase)
+	assert.NoError(t, err)
+
+	resourceService.EXPECT().GetDescriptions(gomock.Any(), enum.Nan).Return(nil, nil)
+	saved := func(_ context.Context, operations []model.BatchOperation, _ bool) ([]model.BatchResult, error) {
+		return make([]model.BatchResult, len(operations)), nil
+	}
+	gomock.InOrder(
+		resourceService.EXPECT().Batch(gomock.Any(), gomock.Len(clservices.MaxBatchSize), true).DoAndReturn(saved),
+		resourceService.EXPECT().Batch(gomock.Any(), gomock.Len(1), true).DoAndReturn(saved),
+	)
+	summary, err := backupService.Restore(context.Background(), bytes.NewReader(archive.Bytes()), passphrase, clservices.ConflictSkip)
+	assert.NoError(t, err)
+	assert.Equal(t, count, summary.Restored)
+}
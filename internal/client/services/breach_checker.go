@@ -0,0 +1,117 @@
+package services
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	hibpHashLength = sha1.Size * 2
+	// hibpMaxLineLength is enough for 'HASH:COUNT\r\n' line
+	hibpMaxLineLength = 128
+)
+
+//go:generate mockgen -source=breach_checker.go -destination=../mocks/services/breach_checker.go -package=services
+
+// BreachChecker looks up passwords in the local Have-I-Been-Pwned dataset, passwords are never sent anywhere
+type BreachChecker interface {
+	// Count returns how many times the password was seen in breaches, 0 if it's not found
+	Count(password string) (int, error)
+	Close() error
+}
+
+// hibpChecker searches 'SHA1:COUNT' lines of the file ordered by hash by binary search
+type hibpChecker struct {
+	file *os.File
+	size int64
+}
+
+func NewHibpChecker(path string) (BreachChecker, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open HIBP dataset: %w", err)
+	}
+	stat, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &hibpChecker{file: file, size: stat.Size()}, nil
+}
+
+func (c *hibpChecker) Count(password string) (int, error) {
+	sum := sha1.Sum([]byte(password))
+	target := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	// find the first line with hash >= target, lines before lo have lower hashes
+	lo, hi := int64(0), c.size
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		start, line, err := c.lineAt(mid)
+		if err != nil {
+			return 0, err
+		}
+		if start >= c.size || lineHash(line) >= target {
+			hi = mid
+			continue
+		}
+		lo = start + int64(len(line)) + 1
+	}
+	start, line, err := c.lineAt(lo)
+	if err != nil || start >= c.size || lineHash(line) != target {
+		return 0, err
+	}
+	_, count, found := strings.Cut(strings.TrimSpace(line), ":")
+	if !found {
+		return 0, fmt.Errorf("invalid HIBP line at %d: '%s'", start, line)
+	}
+	return strconv.Atoi(count)
+}
+
+// lineAt returns the first line which starts at the offset or after it
+func (c *hibpChecker) lineAt(offset int64) (int64, string, error) {
+	start := offset
+	if offset > 0 {
+		buf := make([]byte, hibpMaxLineLength)
+		n, err := c.file.ReadAt(buf, offset-1)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return 0, "", err
+		}
+		i := bytes.IndexByte(buf[:n], '\n')
+		if i < 0 {
+			return c.size, "", nil
+		}
+		start = offset + int64(i)
+	}
+	if start >= c.size {
+		return c.size, "", nil
+	}
+	buf := make([]byte, hibpMaxLineLength)
+	n, err := c.file.ReadAt(buf, start)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return 0, "", err
+	}
+	line := buf[:n]
+	if i := bytes.IndexByte(line, '\n'); i >= 0 {
+		line = line[:i]
+	}
+	return start, string(line), nil
+}
+
+func lineHash(line string) string {
+	if len(line) < hibpHashLength {
+		return strings.ToUpper(line)
+	}
+	return strings.ToUpper(line[:hibpHashLength])
+}
+
+func (c *hibpChecker) Close() error {
+	return c.file.Close()
+}
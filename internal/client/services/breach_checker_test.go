@@ -0,0 +1,43 @@
+package services
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHibpChecker_Count(t *testing.T) {
+	var lines []string
+	counts := make(map[string]int)
+	for i := 0; i < 500; i++ {
+		password := fmt.Sprintf("password%d", i)
+		sum := sha1.Sum([]byte(password))
+		counts[password] = i + 1
+		lines = append(lines, fmt.Sprintf("%s:%d\r\n", strings.ToUpper(hex.EncodeToString(sum[:])), i+1))
+	}
+	sort.Strings(lines)
+	path := filepath.Join(t.TempDir(), "pwned.txt")
+	assert.NoError(t, os.WriteFile(path, []byte(strings.TrimSpace(strings.Join(lines, ""))), 0600))
+
+	checker, err := NewHibpChecker(path)
+	assert.NoError(t, err)
+	defer checker.Close()
+
+	for password, expected := range counts {
+		count, err := checker.Count(password)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, count, password)
+	}
+	for _, password := range []string{"", "not breached", "password500"} {
+		count, err := checker.Count(password)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, count, password)
+	}
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+	"go.uber.org/zap"
+
+	clmodel "ydx-goadv-gophkeeper/internal/client/model"
+	"ydx-goadv-gophkeeper/internal/client/model/resources"
+	"ydx-goadv-gophkeeper/pkg/model/enum"
+)
+
+const (
+	// zstdCompression marks compressed file content in its description
+	zstdCompression = "zstd"
+	// maxDecompressedSize protects from payloads crafted to exhaust memory
+	maxDecompressedSize = 64 << 20
+)
+
+// compressedHeader marks compressed payload, json of resources never starts with it
+var compressedHeader = []byte{0, 'z', 's', 't'}
+
+// compressedExtensions are formats which do not get smaller
+var compressedExtensions = []string{
+	".zip", ".gz", ".tgz", ".bz2", ".xz", ".zst", ".7z", ".rar",
+	".jpg", ".jpeg", ".png", ".gif", ".webp", ".mp3", ".mp4", ".mkv", ".pdf", ".docx", ".xlsx",
+}
+
+var (
+	payloadEncoder, _ = zstd.NewWriter(nil)
+	payloadDecoder, _ = zstd.NewReader(nil, zstd.WithDecoderMaxMemory(maxDecompressedSize))
+)
+
+type compressor struct {
+	minSize        int
+	types          map[enum.ResourceType]bool
+	skipExtensions map[string]bool
+}
+
+func newCompressor(policy clmodel.CompressionPolicy, log *zap.SugaredLogger) *compressor {
+	c := &compressor{
+		minSize:        policy.MinSize,
+		types:          make(map[enum.ResourceType]bool, len(policy.Types)),
+		skipExtensions: make(map[string]bool),
+	}
+	for _, alias := range policy.Types {
+		kind, ok := resources.KindByAlias(alias)
+		if !ok {
+			log.Warnf("compression of unknown type '%s' is skipped", alias)
+			continue
+		}
+		c.types[kind.Type] = true
+	}
+	for _, ext := range append(compressedExtensions, policy.SkipExtensions...) {
+		c.skipExtensions[strings.ToLower(ext)] = true
+	}
+	return c
+}
+
+// compress returns compressed data with header if the type is compressed and the data gets smaller
+func (c *compressor) compress(resType enum.ResourceType, data []byte) []byte {
+	if !c.types[resType] || len(data) < c.minSize {
+		return data
+	}
+	compressed := payloadEncoder.EncodeAll(data, append([]byte(nil), compressedHeader...))
+	if len(compressed) >= len(data) {
+		return data
+	}
+	return compressed
+}
+
+// compressesFile tells if the file content is compressed by the policy
+func (c *compressor) compressesFile(path string, size int64) bool {
+	return c.types[enum.File] && size >= int64(c.minSize) && !c.skipExtensions[strings.ToLower(filepath.Ext(path))]
+}
+
+// decompress returns data as is if it has no compressed header
+func decompress(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, compressedHeader) {
+		return data, nil
+	}
+	decompressed, err := payloadDecoder.DecodeAll(data[len(compressedHeader):], nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress data: %w", err)
+	}
+	return decompressed, nil
+}
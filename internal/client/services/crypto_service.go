@@ -1,6 +1,7 @@
 package services
 
 import (
+	"crypto/hmac"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/sha256"
@@ -18,18 +19,32 @@ const blockLength = 128
 type CryptService interface {
 	Decrypt(data []byte) ([]byte, error)
 	Encrypt(data []byte) ([]byte, error)
+	// BlindIndex returns deterministic keyed hash of the data, server can match it without knowing the data
+	BlindIndex(data []byte) ([]byte, error)
 }
 
 type cryptService struct {
 	log        *zap.SugaredLogger
 	privateKey *rsa.PrivateKey
+	indexKey   []byte
 }
 
 func NewCryptService(privateKey *rsa.PrivateKey) CryptService {
-	return &cryptService{
+	service := &cryptService{
 		log:        logger.NewLogger("crypt"),
 		privateKey: privateKey,
 	}
+	if privateKey != nil {
+		keyHash := sha256.Sum256(append([]byte("labels"), privateKey.D.Bytes()...))
+		service.indexKey = keyHash[:]
+	}
+	return service
+}
+
+func (e *cryptService) BlindIndex(data []byte) ([]byte, error) {
+	mac := hmac.New(sha256.New, e.indexKey)
+	mac.Write(data)
+	return mac.Sum(nil), nil
 }
 
 func (e *cryptService) Decrypt(data []byte) ([]byte, error) {
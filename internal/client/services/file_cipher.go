@@ -0,0 +1,369 @@
+package services
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/klauspost/compress/zstd"
+
+	"ydx-goadv-gophkeeper/internal/client/model/resources"
+)
+
+// fileFrameSize is the size of file plaintext sealed at once, frames do not depend on stream chunks
+const fileFrameSize = 64 * 1024
+
+var errFileTruncated = errors.New("encrypted file is truncated")
+
+// fileKey derives the key of the file from its content hash, so the same file of the same vault key is always
+// encrypted the same way and the server keeps it once, while other keys produce unrelated ciphertexts,
+// compression is a part of the key as frame nonces must not seal both the raw and the compressed content
+func fileKey(cryptoService CryptService, contentHash []byte, compressed bool) ([]byte, error) {
+	prefix := "file:raw:"
+	if compressed {
+		prefix = "file:" + zstdCompression + ":"
+	}
+	return cryptoService.BlindIndex(append([]byte(prefix), contentHash...))
+}
+
+// snapshotFile copies the file to a private temp file and returns its path and SHA-256 of the content,
+// the key, the digest and the upload are taken from the copy, so they are of the same content
+func snapshotFile(path string) (string, []byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", nil, err
+	}
+	defer file.Close()
+	snapshot, err := os.CreateTemp("", "gophkeeper-upload-*")
+	if err != nil {
+		return "", nil, err
+	}
+	hash := sha256.New()
+	_, err = io.Copy(io.MultiWriter(snapshot, hash), file)
+	if closeErr := snapshot.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(snapshot.Name())
+		return "", nil, err
+	}
+	return snapshot.Name(), hash.Sum(nil), nil
+}
+
+// encryptedDigest returns SHA-256 of the encrypted file, it is known before upload as encryption is deterministic
+func encryptedDigest(key []byte, compressed bool, path string) ([]byte, error) {
+	sealer, err := newFileSealer(key, compressed)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	hash := sha256.New()
+	buffer := make([]byte, fileFrameSize)
+	for {
+		n, err := file.Read(buffer)
+		sealed, sealErr := sealer.Seal(buffer[:n])
+		if sealErr != nil {
+			return nil, sealErr
+		}
+		hash.Write(sealed)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	sealed, err := sealer.Close()
+	if err != nil {
+		return nil, err
+	}
+	hash.Write(sealed)
+	return hash.Sum(nil), nil
+}
+
+// fileSealer compresses the file content if needed and seals it by frames
+type fileSealer struct {
+	frames     *fileFrames
+	encoder    *zstd.Encoder
+	compressed bytes.Buffer
+}
+
+func newFileSealer(key []byte, compressed bool) (*fileSealer, error) {
+	frames, err := newFileFrames(key)
+	if err != nil {
+		return nil, err
+	}
+	sealer := &fileSealer{frames: frames}
+	if compressed {
+		// single goroutine keeps the output the same for the same content
+		sealer.encoder, err = zstd.NewWriter(&sealer.compressed, zstd.WithEncoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return sealer, nil
+}
+
+// Seal returns sealed frames of the content written so far
+func (s *fileSealer) Seal(data []byte) ([]byte, error) {
+	if s.encoder == nil {
+		return s.frames.Seal(data), nil
+	}
+	if _, err := s.encoder.Write(data); err != nil {
+		return nil, err
+	}
+	sealed := s.frames.Seal(s.compressed.Bytes())
+	s.compressed.Reset()
+	return sealed, nil
+}
+
+// Close returns the rest of sealed frames
+func (s *fileSealer) Close() ([]byte, error) {
+	if s.encoder == nil {
+		return s.frames.SealFinal(), nil
+	}
+	if err := s.encoder.Close(); err != nil {
+		return nil, err
+	}
+	sealed := s.frames.Seal(s.compressed.Bytes())
+	s.compressed.Reset()
+	return append(sealed, s.frames.SealFinal()...), nil
+}
+
+// fileDecrypter writes decrypted content of the file stream
+type fileDecrypter interface {
+	// Open writes the content of the chunk as far as it is known
+	Open(data []byte) error
+	// Close writes the rest of the content once the stream is complete
+	Close() error
+	// Abort stops writing of the incomplete stream
+	Abort()
+}
+
+// chunkDecrypter decrypts files uploaded without content key chunk by chunk with the vault key
+type chunkDecrypter struct {
+	decrypt func([]byte) ([]byte, error)
+	w       io.Writer
+}
+
+func (d *chunkDecrypter) Open(data []byte) error {
+	decrypted, err := d.decrypt(data)
+	if err != nil {
+		return err
+	}
+	_, err = d.w.Write(decrypted)
+	return err
+}
+
+func (d *chunkDecrypter) Close() error {
+	return nil
+}
+
+func (d *chunkDecrypter) Abort() {}
+
+// fileOpener opens frames and writes the file content, compressed content is streamed through the decoder
+type fileOpener struct {
+	frames *fileFrames
+	// w is either the destination or the pipe to the decoder
+	w    io.Writer
+	pipe *io.PipeWriter
+	// done returns the result of the decoder
+	done chan error
+}
+
+func newFileOpener(key []byte, compressed bool, w io.Writer) (*fileOpener, error) {
+	frames, err := newFileFrames(key)
+	if err != nil {
+		return nil, err
+	}
+	opener := &fileOpener{frames: frames, w: w}
+	if !compressed {
+		return opener, nil
+	}
+	decoder, err := zstd.NewReader(nil, zstd.WithDecoderConcurrency(1))
+	if err != nil {
+		return nil, err
+	}
+	reader, pipe := io.Pipe()
+	opener.w, opener.pipe, opener.done = pipe, pipe, make(chan error, 1)
+	go func() {
+		defer decoder.Close()
+		err := decoder.Reset(reader)
+		if err == nil {
+			_, err = io.Copy(w, decoder)
+		}
+		if err != nil {
+			err = fmt.Errorf("failed to decompress file: %w", err)
+		}
+		// failed decoder stops writes to the pipe
+		reader.CloseWithError(err)
+		opener.done <- err
+	}()
+	return opener, nil
+}
+
+func (o *fileOpener) Open(data []byte) error {
+	opened, err := o.frames.Open(data)
+	if err != nil {
+		return err
+	}
+	_, err = o.w.Write(opened)
+	return err
+}
+
+func (o *fileOpener) Close() error {
+	opened, err := o.frames.OpenFinal()
+	if err != nil {
+		return err
+	}
+	if _, err := o.w.Write(opened); err != nil {
+		return err
+	}
+	if o.pipe == nil {
+		return nil
+	}
+	o.pipe.Close()
+	err = <-o.done
+	o.pipe = nil
+	return err
+}
+
+// Abort stops the decoder and waits until it does not write anymore
+func (o *fileOpener) Abort() {
+	if o.pipe == nil {
+		return
+	}
+	o.pipe.CloseWithError(errFileTruncated)
+	<-o.done
+	o.pipe = nil
+}
+
+// fileFrames seals or opens the file by frames, the nonce is the frame number as the key is unique per content
+type fileFrames struct {
+	aead    cipher.AEAD
+	counter uint64
+	buf     []byte
+}
+
+func newFileFrames(key []byte) (*fileFrames, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &fileFrames{aead: aead}, nil
+}
+
+// nonce marks the final frame, so the truncated file is not opened
+func (f *fileFrames) nonce(final bool) []byte {
+	nonce := make([]byte, f.aead.NonceSize())
+	if final {
+		nonce[0] = 1
+	}
+	binary.BigEndian.PutUint64(nonce[len(nonce)-8:], f.counter)
+	f.counter++
+	return nonce
+}
+
+// Seal buffers the plaintext and returns sealed full frames
+func (f *fileFrames) Seal(data []byte) []byte {
+	f.buf = append(f.buf, data...)
+	var sealed []byte
+	// the last full frame is kept, it is final if no more data comes
+	for len(f.buf) > fileFrameSize {
+		sealed = f.aead.Seal(sealed, f.nonce(false), f.buf[:fileFrameSize], nil)
+		f.buf = f.buf[fileFrameSize:]
+	}
+	return sealed
+}
+
+// SealFinal seals the rest of the plaintext as the final frame
+func (f *fileFrames) SealFinal() []byte {
+	sealed := f.aead.Seal(nil, f.nonce(true), f.buf, nil)
+	f.buf = nil
+	return sealed
+}
+
+// Open buffers the ciphertext and returns plaintext of full frames
+func (f *fileFrames) Open(data []byte) ([]byte, error) {
+	f.buf = append(f.buf, data...)
+	frameSize := fileFrameSize + f.aead.Overhead()
+	var opened []byte
+	for len(f.buf) > frameSize {
+		var err error
+		opened, err = f.aead.Open(opened, f.nonce(false), f.buf[:frameSize], nil)
+		if err != nil {
+			return nil, err
+		}
+		f.buf = f.buf[frameSize:]
+	}
+	return opened, nil
+}
+
+// OpenFinal opens the rest of the ciphertext as the final frame
+func (f *fileFrames) OpenFinal() ([]byte, error) {
+	if len(f.buf) < f.aead.Overhead() {
+		return nil, errFileTruncated
+	}
+	opened, err := f.aead.Open(nil, f.nonce(true), f.buf, nil)
+	if err != nil {
+		return nil, err
+	}
+	f.buf = nil
+	return opened, nil
+}
+
+// sealedFile is the file description stored by the server as is, so its custom fields are encrypted
+type sealedFile struct {
+	resources.File
+	SealedFields []byte `json:"sealedFields,omitempty"`
+}
+
+// sealFileDescription returns the file description with encrypted custom fields
+func (s *resourceService) sealFileDescription(file *resources.File) ([]byte, error) {
+	sealed := sealedFile{File: *file}
+	sealed.Fields = nil
+	if len(file.Fields) > 0 {
+		fields, err := json.Marshal(file.Fields)
+		if err != nil {
+			return nil, err
+		}
+		if sealed.SealedFields, err = s.cryptoService.Encrypt(fields); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(&sealed)
+}
+
+// openFileDescription returns the file description with decrypted custom fields
+func (s *resourceService) openFileDescription(data []byte) ([]byte, error) {
+	var sealed sealedFile
+	if err := json.Unmarshal(data, &sealed); err != nil {
+		return nil, err
+	}
+	if sealed.SealedFields == nil {
+		return data, nil
+	}
+	fields, err := s.cryptoService.Decrypt(sealed.SealedFields)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(fields, &sealed.Fields); err != nil {
+		return nil, err
+	}
+	return json.Marshal(&sealed.File)
+}
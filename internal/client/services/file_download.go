@@ -0,0 +1,54 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"ydx-goadv-gophkeeper/internal/client/model/resources"
+)
+
+var ErrNotOverwritten = errors.New("existing file is not overwritten")
+
+// FileDownload saves the file resource to the resolved destination
+type FileDownload struct {
+	resourceService ResourceService
+	ResId           int32
+	// Version is the previous version of the file, zero is the current content
+	Version int32
+	Path    string
+}
+
+// NewFileDownload resolves the destination of the file resource, dest is a directory or a file path,
+// the existing file is overwritten if force is set or the answer to the prompt read by ask is yes
+func NewFileDownload(ctx context.Context, resourceService ResourceService, resId int32, dest string, force bool, ask func(prompt string) (string, error)) (*FileDownload, error) {
+	info, err := resourceService.Get(ctx, resId)
+	if err != nil {
+		return nil, err
+	}
+	file, ok := info.Resource.(*resources.File)
+	if !ok {
+		return nil, fmt.Errorf("resource %d is not a file", resId)
+	}
+	path := file.Destination(dest)
+	if _, err := os.Stat(path); err == nil && !force {
+		answer, err := ask(fmt.Sprintf("file '%s' exists, overwrite? [y/N]", path))
+		if err != nil {
+			return nil, err
+		}
+		if answer = strings.ToLower(strings.TrimSpace(answer)); answer != "y" && answer != "yes" {
+			return nil, ErrNotOverwritten
+		}
+	}
+	return &FileDownload{resourceService: resourceService, ResId: resId, Path: path}, nil
+}
+
+// Run downloads the file content to the destination
+func (d *FileDownload) Run(ctx context.Context) error {
+	if d.Version != 0 {
+		return d.resourceService.GetFileVersionTo(ctx, d.ResId, d.Version, d.Path)
+	}
+	return d.resourceService.GetFileTo(ctx, d.ResId, d.Path)
+}
@@ -0,0 +1,55 @@
+package services_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"ydx-goadv-gophkeeper/internal/client/mocks/services"
+	"ydx-goadv-gophkeeper/internal/client/model/resources"
+	clservices "ydx-goadv-gophkeeper/internal/client/services"
+)
+
+func TestNewFileDownload(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	resourceService := services.NewMockResourceService(ctrl)
+	resourceService.EXPECT().Get(gomock.Any(), int32(42)).Return(&resources.Info{Resource: &resources.File{Name: "cert.pem"}}, nil).AnyTimes()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "cert.pem")
+	assert.NoError(t, os.WriteFile(path, []byte("old"), 0600))
+
+	tests := []struct {
+		name    string
+		force   bool
+		answer  string
+		wantErr error
+	}{
+		{name: "refused", answer: "n", wantErr: clservices.ErrNotOverwritten},
+		{name: "empty answer", answer: "", wantErr: clservices.ErrNotOverwritten},
+		{name: "confirmed", answer: " Yes "},
+		{name: "forced", force: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			asked := false
+			ask := func(string) (string, error) {
+				asked = true
+				return tt.answer, nil
+			}
+			download, err := clservices.NewFileDownload(context.Background(), resourceService, 42, dir, tt.force, ask)
+			assert.Equal(t, !tt.force, asked)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			resourceService.EXPECT().GetFileTo(gomock.Any(), int32(42), path).Return(nil)
+			assert.NoError(t, download.Run(context.Background()))
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"ydx-goadv-gophkeeper/internal/client/importers"
+	"ydx-goadv-gophkeeper/internal/client/model"
+	"ydx-goadv-gophkeeper/internal/client/model/resources"
+	"ydx-goadv-gophkeeper/pkg/logger"
+	"ydx-goadv-gophkeeper/pkg/model/enum"
+)
+
+const DefaultImportBatchSize = 20
+
+type ImportOptions struct {
+	// DryRun only validates and dedupes entries
+	DryRun bool
+	// BatchSize is the number of resources saved in one request, at most MaxBatchSize
+	BatchSize int
+}
+
+//go:generate mockgen -source=import_service.go -destination=../mocks/services/import_service.go -package=services
+
+type ImportService interface {
+	// Import validates entries, skips duplicates of the vault resources and uploads the rest in batches
+	Import(ctx context.Context, entries []importers.Entry, opts ImportOptions) (*model.ImportSummary, error)
+}
+
+type importService struct {
+	log             *zap.SugaredLogger
+	resourceService ResourceService
+}
+
+func NewImportService(resourceService ResourceService) ImportService {
+	return &importService{log: logger.NewLogger("import-service"), resourceService: resourceService}
+}
+
+func (s *importService) Import(ctx context.Context, entries []importers.Entry, opts ImportOptions) (*model.ImportSummary, error) {
+	summary := model.NewImportSummary(opts.DryRun)
+	existing, err := s.existingKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []importers.Entry
+	for _, entry := range entries {
+		kind, ok := resources.KindOf(entry.Resource.Type())
+		if !ok {
+			return nil, fmt.Errorf("undefined type %v", entry.Resource.Type())
+		}
+		if len(kind.Schema) != 0 {
+			if err := kind.Validate(entry.Resource).Err(); err != nil {
+				summary.Fail(kind.Alias, entry.Description, err.Error())
+				continue
+			}
+		}
+		key := dedupeKey(entry.Resource, entry.Description)
+		if existing[key] {
+			summary.Stat(kind.Alias).Duplicates++
+			continue
+		}
+		existing[key] = true
+		pending = append(pending, entry)
+	}
+
+	if opts.DryRun {
+		for _, entry := range pending {
+			summary.Stat(aliasOf(entry)).Added++
+		}
+		return summary, nil
+	}
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultImportBatchSize
+	}
+	if batchSize > MaxBatchSize {
+		batchSize = MaxBatchSize
+	}
+	labels := make(map[int32]resources.Labels)
+	var uploadErr error
+	for start := 0; start < len(pending) && uploadErr == nil; start += batchSize {
+		end := start + batchSize
+		if end > len(pending) {
+			end = len(pending)
+		}
+		uploadErr = s.upload(ctx, pending[start:end], summary, labels)
+	}
+	if len(labels) != 0 {
+		if err := s.resourceService.UpdateLabels(ctx, labels); err != nil && uploadErr == nil {
+			uploadErr = fmt.Errorf("failed to set folders of imported resources: %w", err)
+		}
+	}
+	return summary, uploadErr
+}
+
+// upload saves the batch in one request and files one by one, failed entries are added to the summary,
+// folders of saved entries are added to labels
+func (s *importService) upload(ctx context.Context, batch []importers.Entry, summary *model.ImportSummary, labels map[int32]resources.Labels) error {
+	ids := make([]int32, len(batch))
+	errs := make([]error, len(batch))
+	operations := make([]model.BatchOperation, 0, len(batch))
+	batched := make([]int, 0, len(batch))
+	for i, entry := range batch {
+		if entry.Resource.Type() == enum.File {
+			ids[i], errs[i] = s.saveFile(ctx, entry)
+			continue
+		}
+		data, err := json.Marshal(entry.Resource)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		operations = append(operations, model.BatchOperation{
+			Kind: model.BatchCreate,
+			Type: entry.Resource.Type(),
+			Data: data,
+			Meta: []byte(entry.Description),
+		})
+		batched = append(batched, i)
+	}
+	if len(operations) != 0 {
+		results, err := s.resourceService.Batch(ctx, operations, false)
+		if err != nil {
+			return fmt.Errorf("failed to import batch: %w", err)
+		}
+		for j, result := range results {
+			ids[batched[j]], errs[batched[j]] = result.Id, result.Err
+		}
+	}
+	for i, err := range errs {
+		if err != nil {
+			s.log.Errorf("failed to import '%s': %v", batch[i].Description, err)
+			summary.SaveFailed(aliasOf(batch[i]), batch[i].Description, err.Error())
+			continue
+		}
+		summary.Stat(aliasOf(batch[i])).Added++
+		if folder := resources.NormalizeFolder(batch[i].Folder); folder != "" {
+			labels[ids[i]] = resources.Labels{Folder: folder}
+		}
+	}
+	return nil
+}
+
+// saveFile uploads file from temp dir to keep its name
+func (s *importService) saveFile(ctx context.Context, entry importers.Entry) (int32, error) {
+	dir, err := os.MkdirTemp("", "gophkeeper-import")
+	if err != nil {
+		return 0, err
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, entry.FileName)
+	if err := os.WriteFile(path, entry.Content, 0600); err != nil {
+		return 0, err
+	}
+	var fields []resources.CustomField
+	if file, ok := entry.Resource.(*resources.File); ok {
+		fields = file.Fields
+	}
+	return s.resourceService.SaveFile(ctx, path, []byte(entry.Description), fields)
+}
+
+// existingKeys decrypts vault resources to dedupe imported entries
+func (s *importService) existingKeys(ctx context.Context) (map[string]bool, error) {
+	descriptions, err := s.resourceService.GetDescriptions(ctx, enum.Nan)
+	if err != nil {
+		return nil, err
+	}
+	keys := make(map[string]bool, len(descriptions))
+	for _, description := range descriptions {
+		info, err := s.resourceService.Get(ctx, description.Id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get resource %d: %w", description.Id, err)
+		}
+		keys[dedupeKey(info.Resource, string(description.Meta))] = true
+	}
+	return keys, nil
+}
+
+// dedupeKey identifies resource by its type and key fields, custom fields are ignored
+func dedupeKey(resource resources.ResourceClIFormatter, description string) string {
+	var fields []string
+	switch res := resource.(type) {
+	case *resources.LoginPassword:
+		fields = []string{strings.ToLower(res.Login), strings.ToLower(res.Url), res.Password}
+	case *resources.BankCard:
+		fields = []string{res.Number}
+	case *resources.SecureNote:
+		fields = []string{description, res.Text}
+	case *resources.File:
+		fields = []string{description, res.Name, fmt.Sprint(res.Size)}
+	case *resources.Identity:
+		fields = []string{res.FirstName, res.LastName, res.BirthDate, res.PassportNumber, res.IdNumber}
+	default:
+		data, _ := json.Marshal(resource)
+		fields = []string{string(data)}
+	}
+	hash := sha256.Sum256([]byte(strings.Join(fields, "\x00")))
+	return fmt.Sprintf("%d:%s", resource.Type(), hex.EncodeToString(hash[:]))
+}
+
+func aliasOf(entry importers.Entry) string {
+	kind, _ := resources.KindOf(entry.Resource.Type())
+	return kind.Alias
+}
@@ -0,0 +1,67 @@
+package services_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"ydx-goadv-gophkeeper/internal/client/importers"
+	"ydx-goadv-gophkeeper/internal/client/mocks/services"
+	"ydx-goadv-gophkeeper/internal/client/model"
+	"ydx-goadv-gophkeeper/internal/client/model/resources"
+	clservices "ydx-goadv-gophkeeper/internal/client/services"
+	srvmodel "ydx-goadv-gophkeeper/internal/server/model"
+	"ydx-goadv-gophkeeper/pkg/model/enum"
+)
+
+func TestImportService_Import(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	resourceService := services.NewMockResourceService(ctrl)
+
+	existing := resources.NewLoginPassword("octocat", "secret", "https://github.com")
+	resourceService.EXPECT().GetDescriptions(gomock.Any(), enum.Nan).Return([]*srvmodel.ResourceDescription{
+		{Id: 1, Type: enum.LoginPassword, Meta: []byte("github")},
+	}, nil).Times(2)
+	resourceService.EXPECT().Get(gomock.Any(), int32(1)).Return(&resources.Info{Resource: existing}, nil).Times(2)
+
+	note := resources.NewSecureNote("qwerty")
+	entries := []importers.Entry{
+		// url is normalized by validation before dedupe
+		{Resource: resources.NewLoginPassword("OctoCat", "secret", "github.com"), Description: "github"},
+		{Resource: resources.NewLoginPassword("", "secret", ""), Description: "no login"},
+		{Resource: note, Description: "wifi"},
+		{Resource: resources.NewSecureNote("qwerty"), Description: "wifi"},
+	}
+
+	summary, err := clservices.NewImportService(resourceService).Import(context.Background(), entries, clservices.ImportOptions{DryRun: true})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]*model.ImportStats{
+		"lp": {Duplicates: 1, Invalid: 1},
+		"nt": {Added: 1, Duplicates: 1},
+	}, summary.Stats)
+	assert.Len(t, summary.Failures, 1)
+
+	noteJson, err := json.Marshal(note)
+	assert.NoError(t, err)
+	resourceService.EXPECT().Batch(gomock.Any(), []model.BatchOperation{
+		{Kind: model.BatchCreate, Type: enum.SecureNote, Data: noteJson, Meta: []byte("wifi")},
+	}, false).Return([]model.BatchResult{{Id: 2}}, nil)
+	summary, err = clservices.NewImportService(resourceService).Import(context.Background(), entries, clservices.ImportOptions{BatchSize: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, summary.Stats["nt"].Added)
+
+	// failed resource is reported, the rest of the batch is saved
+	resourceService.EXPECT().GetDescriptions(gomock.Any(), enum.Nan).Return(nil, nil)
+	entries = []importers.Entry{{Resource: note, Description: "wifi"}, {Resource: resources.NewSecureNote("pin"), Description: "pin"}}
+	resourceService.EXPECT().Batch(gomock.Any(), gomock.Len(2), false).
+		Return([]model.BatchResult{{Err: errors.New("db error")}, {Id: 3}}, nil)
+	summary, err = clservices.NewImportService(resourceService).Import(context.Background(), entries, clservices.ImportOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, &model.ImportStats{Added: 1, Failed: 1}, summary.Stats["nt"])
+	assert.Len(t, summary.Failures, 1)
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"ydx-goadv-gophkeeper/internal/client/model"
+	"ydx-goadv-gophkeeper/internal/client/model/resources"
+	"ydx-goadv-gophkeeper/pkg/logger"
+)
+
+//go:generate mockgen -source=label_service.go -destination=../mocks/services/label_service.go -package=services
+
+type LabelService interface {
+	// Move puts resources to the folder, empty folder is the root
+	Move(ctx context.Context, folder string, ids []int32) error
+	Tag(ctx context.Context, tag string, ids []int32) error
+	Untag(ctx context.Context, tag string, ids []int32) error
+}
+
+type labelService struct {
+	log             *zap.SugaredLogger
+	resourceService ResourceService
+}
+
+func NewLabelService(resourceService ResourceService) LabelService {
+	return &labelService{log: logger.NewLogger("label-service"), resourceService: resourceService}
+}
+
+func (s *labelService) Move(ctx context.Context, folder string, ids []int32) error {
+	folder = resources.NormalizeFolder(folder)
+	return s.modify(ctx, ids, func(labels *resources.Labels) {
+		labels.Folder = folder
+	})
+}
+
+func (s *labelService) Tag(ctx context.Context, tag string, ids []int32) error {
+	tag, err := resources.NormalizeTag(tag)
+	if err != nil {
+		return err
+	}
+	return s.modify(ctx, ids, func(labels *resources.Labels) {
+		labels.AddTag(tag)
+	})
+}
+
+func (s *labelService) Untag(ctx context.Context, tag string, ids []int32) error {
+	tag, err := resources.NormalizeTag(tag)
+	if err != nil {
+		return err
+	}
+	return s.modify(ctx, ids, func(labels *resources.Labels) {
+		labels.RemoveTag(tag)
+	})
+}
+
+// modify applies change to the current labels of the resources and saves them at once
+func (s *labelService) modify(ctx context.Context, ids []int32, change func(labels *resources.Labels)) error {
+	if len(ids) == 0 {
+		return fmt.Errorf("resource ids are empty")
+	}
+	page, err := s.resourceService.ListDescriptions(ctx, model.ListQuery{Ids: ids})
+	if err != nil {
+		return err
+	}
+	current := make(map[int32][]byte, len(page.Descriptions))
+	for _, description := range page.Descriptions {
+		current[description.Id] = description.Labels
+	}
+	updated := make(map[int32]resources.Labels, len(ids))
+	for _, id := range ids {
+		data, ok := current[id]
+		if !ok {
+			return fmt.Errorf("resource %d is not found", id)
+		}
+		labels, err := resources.ParseLabels(data)
+		if err != nil {
+			return fmt.Errorf("failed to parse labels of resource %d: %w", id, err)
+		}
+		change(&labels)
+		updated[id] = labels
+	}
+	s.log.Debugf("updating labels of %d resources", len(updated))
+	return s.resourceService.UpdateLabels(ctx, updated)
+}
@@ -0,0 +1,225 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"ydx-goadv-gophkeeper/internal/client/generator"
+	"ydx-goadv-gophkeeper/internal/client/model"
+	"ydx-goadv-gophkeeper/internal/client/model/resources"
+	srvmodel "ydx-goadv-gophkeeper/internal/server/model"
+	"ydx-goadv-gophkeeper/pkg/logger"
+	restype "ydx-goadv-gophkeeper/pkg/model"
+	"ydx-goadv-gophkeeper/pkg/model/enum"
+)
+
+var ErrNoHibpFile = errors.New("HIBP dataset file is not configured")
+
+type ReportOptions struct {
+	// MaxAge is the period after which password should be rotated
+	MaxAge time.Duration
+	// ExpiresIn is the period to report bank cards expiring soon
+	ExpiresIn time.Duration
+	// MinScore is the lowest acceptable password score from 0 to 4
+	MinScore int
+	// HibpFile overrides configured HIBP dataset, breaches are not checked if both are empty
+	HibpFile string
+}
+
+func DefaultReportOptions() ReportOptions {
+	return ReportOptions{MaxAge: 180 * 24 * time.Hour, ExpiresIn: 30 * 24 * time.Hour, MinScore: 3}
+}
+
+//go:generate mockgen -source=report_service.go -destination=../mocks/services/report_service.go -package=services
+
+type ReportService interface {
+	// Build decrypts LoginPassword and BankCard resources locally and reports their issues
+	Build(ctx context.Context, opts ReportOptions) (*model.Report, error)
+	// Breaches reports LoginPassword resources found in the local HIBP dataset
+	Breaches(ctx context.Context, hibpFile string) (*model.Report, error)
+}
+
+type reportService struct {
+	log             *zap.SugaredLogger
+	resourceService ResourceService
+	hibpFile        string
+}
+
+type reportResource struct {
+	description *srvmodel.ResourceDescription
+	resource    resources.ResourceClIFormatter
+}
+
+func NewReportService(resourceService ResourceService, hibpFile string) ReportService {
+	return &reportService{log: logger.NewLogger("report-service"), resourceService: resourceService, hibpFile: hibpFile}
+}
+
+func (s *reportService) Build(ctx context.Context, opts ReportOptions) (*model.Report, error) {
+	checked, err := s.load(ctx, enum.LoginPassword, enum.BankCard)
+	if err != nil {
+		return nil, err
+	}
+	report := &model.Report{Checked: len(checked)}
+	passwords := make(map[string][]int32)
+
+	for _, res := range checked {
+		description := res.description
+		switch resource := res.resource.(type) {
+		case *resources.LoginPassword:
+			if resource.Password != "" {
+				passwords[resource.Password] = append(passwords[resource.Password], description.Id)
+			}
+			if analysis := generator.Analyze(resource.Password); analysis.Score < opts.MinScore {
+				details := fmt.Sprintf("score %d/4, %.0f bits", analysis.Score, analysis.Entropy)
+				if len(analysis.Patterns) != 0 {
+					details += ": " + strings.Join(analysis.Patterns, ", ")
+				}
+				report.Entries = append(report.Entries, reportEntry(description, model.IssueWeak, details))
+			}
+			if description.UpdatedAt.IsZero() {
+				report.Entries = append(report.Entries, reportEntry(description, model.IssueAgeUnknown, "saved before update time was tracked"))
+			} else if age := time.Since(description.UpdatedAt); age > opts.MaxAge {
+				details := fmt.Sprintf("not rotated for %d days", int(age.Hours()/24))
+				report.Entries = append(report.Entries, reportEntry(description, model.IssueOld, details))
+			}
+		case *resources.BankCard:
+			expireAt, err := resources.ParseCardExpiry(resource.ExpireAt)
+			if err != nil {
+				s.log.Warnf("failed to parse expiry of bank card %d: %v", description.Id, err)
+				continue
+			}
+			if time.Now().After(expireAt) {
+				report.Entries = append(report.Entries, reportEntry(description, model.IssueExpired, "expired at "+resource.ExpireAt))
+			} else if time.Until(expireAt) < opts.ExpiresIn {
+				report.Entries = append(report.Entries, reportEntry(description, model.IssueExpiring, "expires at "+resource.ExpireAt))
+			}
+		}
+	}
+
+	for _, res := range checked {
+		for _, ids := range passwords {
+			if len(ids) < 2 || !containsId(ids, res.description.Id) {
+				continue
+			}
+			var others []string
+			for _, id := range ids {
+				if id != res.description.Id {
+					others = append(others, fmt.Sprint(id))
+				}
+			}
+			report.Entries = append(report.Entries, reportEntry(res.description, model.IssueReused, "same password as "+strings.Join(others, ", ")))
+		}
+	}
+
+	hibpFile := opts.HibpFile
+	if hibpFile == "" {
+		hibpFile = s.hibpFile
+	}
+	if hibpFile != "" {
+		breaches, err := s.breaches(checked, hibpFile)
+		if err != nil {
+			return nil, err
+		}
+		report.Entries = append(report.Entries, breaches...)
+	}
+
+	sort.SliceStable(report.Entries, func(i, j int) bool {
+		return report.Entries[i].Id < report.Entries[j].Id
+	})
+	return report, nil
+}
+
+func (s *reportService) Breaches(ctx context.Context, hibpFile string) (*model.Report, error) {
+	if hibpFile == "" {
+		hibpFile = s.hibpFile
+	}
+	if hibpFile == "" {
+		return nil, ErrNoHibpFile
+	}
+	checked, err := s.load(ctx, enum.LoginPassword)
+	if err != nil {
+		return nil, err
+	}
+	breaches, err := s.breaches(checked, hibpFile)
+	if err != nil {
+		return nil, err
+	}
+	return &model.Report{Checked: len(checked), Entries: breaches}, nil
+}
+
+func (s *reportService) breaches(checked []reportResource, hibpFile string) ([]model.ReportEntry, error) {
+	checker, err := NewHibpChecker(hibpFile)
+	if err != nil {
+		return nil, err
+	}
+	defer checker.Close()
+	var entries []model.ReportEntry
+	for _, res := range checked {
+		lp, ok := res.resource.(*resources.LoginPassword)
+		if !ok || lp.Password == "" {
+			continue
+		}
+		count, err := checker.Count(lp.Password)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check resource %d: %w", res.description.Id, err)
+		}
+		if count > 0 {
+			entries = append(entries, reportEntry(res.description, model.IssueBreached, fmt.Sprintf("seen %d times in breaches", count)))
+		}
+	}
+	return entries, nil
+}
+
+// load gets and decrypts resources of the types
+func (s *reportService) load(ctx context.Context, types ...enum.ResourceType) ([]reportResource, error) {
+	descriptions, err := s.resourceService.GetDescriptions(ctx, enum.Nan)
+	if err != nil {
+		return nil, err
+	}
+	var result []reportResource
+	for _, description := range descriptions {
+		if !containsType(types, description.Type) {
+			continue
+		}
+		info, err := s.resourceService.Get(ctx, description.Id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get resource %d: %w", description.Id, err)
+		}
+		result = append(result, reportResource{description: description, resource: info.Resource})
+	}
+	return result, nil
+}
+
+func reportEntry(description *srvmodel.ResourceDescription, issue model.ReportIssue, details string) model.ReportEntry {
+	return model.ReportEntry{
+		Id:          description.Id,
+		Type:        restype.TypeToArg[description.Type],
+		Description: string(description.Meta),
+		Issue:       issue,
+		Details:     details,
+	}
+}
+
+func containsId(ids []int32, id int32) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+func containsType(types []enum.ResourceType, resType enum.ResourceType) bool {
+	for _, t := range types {
+		if t == resType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package services_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"ydx-goadv-gophkeeper/internal/client/mocks/services"
+	"ydx-goadv-gophkeeper/internal/client/model"
+	"ydx-goadv-gophkeeper/internal/client/model/resources"
+	clservices "ydx-goadv-gophkeeper/internal/client/services"
+	srvmodel "ydx-goadv-gophkeeper/internal/server/model"
+	"ydx-goadv-gophkeeper/pkg/model/enum"
+)
+
+func TestReportService_Build(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	resourceService := services.NewMockResourceService(ctrl)
+
+	now := time.Now()
+	description := func(id int32, resType enum.ResourceType, updatedAt time.Time) *srvmodel.ResourceDescription {
+		return &srvmodel.ResourceDescription{Id: id, Type: resType, Meta: []byte("res"), UpdatedAt: updatedAt}
+	}
+	resourceService.EXPECT().GetDescriptions(gomock.Any(), enum.Nan).Return([]*srvmodel.ResourceDescription{
+		description(1, enum.LoginPassword, now),
+		description(2, enum.LoginPassword, now.Add(-400*24*time.Hour)),
+		description(3, enum.LoginPassword, now),
+		description(4, enum.BankCard, now),
+		description(5, enum.SecureNote, now),
+		// saved before update time was tracked
+		description(6, enum.LoginPassword, time.Time{}),
+	}, nil)
+	strong := "x7#Kp9!vQ2$mZ4wL"
+	resourceService.EXPECT().Get(gomock.Any(), int32(1)).Return(&resources.Info{Resource: resources.NewLoginPassword("a", strong, "")}, nil)
+	resourceService.EXPECT().Get(gomock.Any(), int32(2)).Return(&resources.Info{Resource: resources.NewLoginPassword("b", strong, "")}, nil)
+	resourceService.EXPECT().Get(gomock.Any(), int32(3)).Return(&resources.Info{Resource: resources.NewLoginPassword("c", "password1", "")}, nil)
+	resourceService.EXPECT().Get(gomock.Any(), int32(6)).Return(&resources.Info{Resource: resources.NewLoginPassword("d", "k3@Wz8^rT5&nY1qP", "")}, nil)
+	resourceService.EXPECT().Get(gomock.Any(), int32(4)).Return(&resources.Info{Resource: resources.NewBankCard("4111111111111111", now.Format("01/06"), "", "")}, nil)
+
+	opts := clservices.DefaultReportOptions()
+	opts.ExpiresIn = 45 * 24 * time.Hour
+	opts.HibpFile = filepath.Join(t.TempDir(), "pwned.txt")
+	// sha1 of 'password1'
+	assert.NoError(t, os.WriteFile(opts.HibpFile, []byte("E38AD214943DAAD1D64C102FAEC29DE4AFE9DA3D:42\n"), 0600))
+	report, err := clservices.NewReportService(resourceService, "").Build(context.Background(), opts)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, report.Checked)
+	assert.Equal(t, []model.ReportEntry{
+		{Id: 1, Type: "lp", Description: "res", Issue: model.IssueReused, Details: "same password as 2"},
+		{Id: 2, Type: "lp", Description: "res", Issue: model.IssueOld, Details: "not rotated for 400 days"},
+		{Id: 2, Type: "lp", Description: "res", Issue: model.IssueReused, Details: "same password as 1"},
+		{Id: 3, Type: "lp", Description: "res", Issue: model.IssueWeak, Details: "score 0/4, 6 bits: common password"},
+		{Id: 3, Type: "lp", Description: "res", Issue: model.IssueBreached, Details: "seen 42 times in breaches"},
+		{Id: 4, Type: "bc", Description: "res", Issue: model.IssueExpiring, Details: "expires at " + now.Format("01/06")},
+		{Id: 6, Type: "lp", Description: "res", Issue: model.IssueAgeUnknown, Details: "saved before update time was tracked"},
+	}, report.Entries)
+}
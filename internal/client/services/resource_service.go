@@ -1,22 +1,38 @@
 package services
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"os"
 	"path/filepath"
+	"time"
 
 	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
+	clmodel "ydx-goadv-gophkeeper/internal/client/model"
 	"ydx-goadv-gophkeeper/internal/client/model/resources"
 	"ydx-goadv-gophkeeper/internal/server/model"
 	"ydx-goadv-gophkeeper/pkg/logger"
+	restype "ydx-goadv-gophkeeper/pkg/model"
 	"ydx-goadv-gophkeeper/pkg/model/enum"
+	"ydx-goadv-gophkeeper/pkg/model/registry"
 	"ydx-goadv-gophkeeper/pkg/pb"
 	intsrv "ydx-goadv-gophkeeper/pkg/services"
 )
 
+// ErrBatchAborted is the result of operation rolled back with the failed atomic batch
+var ErrBatchAborted = errors.New("batch is rolled back")
+
+// MaxBatchSize is the number of operations the server accepts in one batch
+const MaxBatchSize = 1000
+
 //go:generate mockgen -source=resource_service.go -destination=../mocks/services/resource_service.go -package=services
 
 type ResourceService interface {
@@ -24,9 +40,46 @@ type ResourceService interface {
 	Update(ctx context.Context, resId int32, resType enum.ResourceType, data []byte, meta []byte) error
 	Delete(ctx context.Context, resId int32) error
 	GetDescriptions(ctx context.Context, resType enum.ResourceType) ([]*model.ResourceDescription, error)
+	// ListDescriptions gets a page of descriptions filtered and ordered by server
+	ListDescriptions(ctx context.Context, query clmodel.ListQuery) (*clmodel.DescriptionsPage, error)
 	Get(ctx context.Context, resId int32) (*resources.Info, error)
-	SaveFile(ctx context.Context, path string, meta []byte) (int32, error)
-	GetFile(ctx context.Context, resId int32) (string, error)
+	// SaveFile uploads the file with its custom fields
+	SaveFile(ctx context.Context, path string, meta []byte, fields []resources.CustomField) (int32, error)
+	// ReplaceFile uploads new content of the file keeping its id, labels, custom fields and creation time,
+	// the previous content is kept as the file version
+	ReplaceFile(ctx context.Context, resId int32, path string, meta []byte) error
+	// RenameFile updates name, description and custom fields of the file keeping its content,
+	// empty name, nil meta and nil fields are kept
+	RenameFile(ctx context.Context, resId int32, name string, meta []byte, fields []resources.CustomField) error
+	// GetFileTo replaces the path with the file once its content is verified
+	GetFileTo(ctx context.Context, resId int32, path string) error
+	// WriteFile streams the file content to the writer, the error at the end means the content is not verified
+	WriteFile(ctx context.Context, resId int32, w io.Writer) error
+	// FileVersions returns previous contents of the file from the latest one
+	FileVersions(ctx context.Context, resId int32) (clmodel.FileVersions, error)
+	// GetFileVersionTo replaces the path with the previous content of the file once it is verified
+	GetFileVersionTo(ctx context.Context, resId int32, version int32, path string) error
+	// WriteFileVersion streams the previous content of the file to the writer
+	WriteFileVersion(ctx context.Context, resId int32, version int32, w io.Writer) error
+	// UpdateLabels encrypts and saves labels of the resources at once
+	UpdateLabels(ctx context.Context, labels map[int32]resources.Labels) error
+	// Batch applies operations in one request, results are in the order of operations
+	Batch(ctx context.Context, operations []clmodel.BatchOperation, atomic bool) ([]clmodel.BatchResult, error)
+	// Usage returns resources consumption by type and quotas of the user and organization
+	Usage(ctx context.Context) (*clmodel.Usage, error)
+}
+
+var listOrders = map[clmodel.ListOrder]pb.ORDER{
+	"":                     pb.ORDER_BY_ID,
+	clmodel.OrderById:      pb.ORDER_BY_ID,
+	clmodel.OrderByCreated: pb.ORDER_BY_CREATED,
+	clmodel.OrderByUpdated: pb.ORDER_BY_UPDATED,
+}
+
+var batchKinds = map[clmodel.BatchKind]pb.BATCH_KIND{
+	clmodel.BatchCreate: pb.BATCH_KIND_CREATE,
+	clmodel.BatchUpdate: pb.BATCH_KIND_UPDATE,
+	clmodel.BatchDelete: pb.BATCH_KIND_DELETE,
 }
 
 type resourceService struct {
@@ -34,18 +87,22 @@ type resourceService struct {
 	resourceClient pb.ResourcesClient
 	fileService    intsrv.FileService
 	cryptoService  CryptService
+	compressor     *compressor
 }
 
 func NewResourceService(
 	client pb.ResourcesClient,
 	fileService intsrv.FileService,
 	cryptoService CryptService,
+	compression clmodel.CompressionPolicy,
 ) ResourceService {
+	log := logger.NewLogger("res-service")
 	return &resourceService{
-		log:            logger.NewLogger("res-service"),
+		log:            log,
 		resourceClient: client,
 		fileService:    fileService,
 		cryptoService:  cryptoService,
+		compressor:     newCompressor(compression, log),
 	}
 }
 
@@ -55,12 +112,12 @@ func (s *resourceService) Save(
 	data []byte,
 	meta []byte,
 ) (int32, error) {
-	encryptedData, err := s.cryptoService.Encrypt(data)
+	encryptedData, err := s.cryptoService.Encrypt(s.compressor.compress(resType, data))
 	if err != nil {
 		return 0, err
 	}
 	resId, err := s.resourceClient.Save(ctx, &pb.Resource{
-		Type: pb.TYPE(resType),
+		Type: registry.ToWire(resType),
 		Data: encryptedData,
 		Meta: meta,
 	})
@@ -77,13 +134,13 @@ func (s *resourceService) Update(
 	data []byte,
 	meta []byte,
 ) error {
-	encryptedData, err := s.cryptoService.Encrypt(data)
+	encryptedData, err := s.cryptoService.Encrypt(s.compressor.compress(resType, data))
 	if err != nil {
 		return err
 	}
 	_, err = s.resourceClient.Update(ctx, &pb.Resource{
 		Id:   resId,
-		Type: pb.TYPE(resType),
+		Type: registry.ToWire(resType),
 		Data: encryptedData,
 		Meta: meta,
 	})
@@ -96,11 +153,28 @@ func (s *resourceService) Delete(ctx context.Context, resId int32) error {
 }
 
 func (s *resourceService) GetDescriptions(ctx context.Context, resType enum.ResourceType) ([]*model.ResourceDescription, error) {
-	stream, err := s.resourceClient.GetDescriptions(ctx, &pb.Query{ResourceType: pb.TYPE(resType)})
+	query := clmodel.ListQuery{}
+	if resType != enum.Nan {
+		query.Types = []enum.ResourceType{resType}
+	}
+	page, err := s.ListDescriptions(ctx, query)
 	if err != nil {
 		return nil, err
 	}
-	results := make([]*model.ResourceDescription, 0)
+	return page.Descriptions, nil
+}
+
+func (s *resourceService) ListDescriptions(ctx context.Context, query clmodel.ListQuery) (*clmodel.DescriptionsPage, error) {
+	pbQuery, err := s.toPbQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	stream, err := s.resourceClient.GetDescriptions(ctx, pbQuery)
+	if err != nil {
+		return nil, err
+	}
+	page := &clmodel.DescriptionsPage{Descriptions: make([]*model.ResourceDescription, 0)}
+	var last string
 	for {
 		descr, err := stream.Recv()
 		if err == io.EOF {
@@ -109,13 +183,73 @@ func (s *resourceService) GetDescriptions(ctx context.Context, resType enum.Reso
 		if err != nil {
 			return nil, err
 		}
-		results = append(results, &model.ResourceDescription{
-			Id:   descr.Id,
-			Meta: descr.Meta,
-			Type: enum.ResourceType(descr.Type),
+		resType, err := registry.FromWire(descr.Type)
+		if err != nil {
+			return nil, err
+		}
+		// labels are returned decrypted, they are parsed by resources.ParseLabels
+		labels, err := s.decryptLabels(descr.Labels)
+		if err != nil {
+			return nil, err
+		}
+		page.Descriptions = append(page.Descriptions, &model.ResourceDescription{
+			Id:        descr.Id,
+			Meta:      descr.Meta,
+			Type:      resType,
+			CreatedAt: descr.CreatedAt.AsTime(),
+			UpdatedAt: optionalTime(descr.UpdatedAt),
+			Labels:    labels,
 		})
+		last = descr.Cursor
 	}
-	return results, nil
+	if query.Limit > 0 && len(page.Descriptions) == query.Limit {
+		page.Next = last
+	}
+	return page, nil
+}
+
+func (s *resourceService) toPbQuery(query clmodel.ListQuery) (*pb.Query, error) {
+	order, ok := listOrders[query.Order]
+	if !ok {
+		return nil, fmt.Errorf("unknown order '%s', it should be one of: id, created, updated", query.Order)
+	}
+	pbQuery := &pb.Query{
+		Ids:        query.Ids,
+		Order:      order,
+		Descending: query.Descending,
+		PageSize:   int32(query.Limit),
+		Cursor:     query.Cursor,
+	}
+	for _, resType := range query.Types {
+		pbQuery.Types = append(pbQuery.Types, registry.ToWire(resType))
+	}
+	if !query.UpdatedSince.IsZero() {
+		pbQuery.UpdatedSince = timestamppb.New(query.UpdatedSince)
+	}
+	var terms []string
+	for _, tag := range query.Tags {
+		terms = append(terms, resources.TagTerm(tag))
+	}
+	if query.Folder != "" {
+		terms = append(terms, resources.FolderTerm(query.Folder))
+	}
+	var err error
+	if pbQuery.LabelTokens, err = s.blindIndexes(terms); err != nil {
+		return nil, err
+	}
+	return pbQuery, nil
+}
+
+func (s *resourceService) blindIndexes(terms []string) ([][]byte, error) {
+	indexes := make([][]byte, 0, len(terms))
+	for _, term := range terms {
+		index, err := s.cryptoService.BlindIndex([]byte(term))
+		if err != nil {
+			return nil, err
+		}
+		indexes = append(indexes, index)
+	}
+	return indexes, nil
 }
 
 func (s *resourceService) Get(ctx context.Context, resId int32) (*resources.Info, error) {
@@ -123,52 +257,217 @@ func (s *resourceService) Get(ctx context.Context, resId int32) (*resources.Info
 	if err != nil {
 		return nil, err
 	}
+	if resource.Labels, err = s.decryptLabels(resource.Labels); err != nil {
+		return nil, err
+	}
+	if resource.Type == pb.TYPE_FILE {
+		// file description is stored as is, only file content and custom fields are encrypted
+		if resource.Data, err = s.openFileDescription(resource.Data); err != nil {
+			return nil, err
+		}
+		return s.parseResource(resource)
+	}
 	decryptedData, err := s.cryptoService.Decrypt(resource.Data)
 	if err != nil {
 		return nil, err
 	}
-	resource.Data = decryptedData
+	if resource.Data, err = decompress(decryptedData); err != nil {
+		return nil, err
+	}
 	return s.parseResource(resource)
 }
 
-func (s *resourceService) parseResource(resource *pb.Resource) (*resources.Info, error) {
-	switch enum.ResourceType(resource.Type) {
-	case enum.LoginPassword:
-		var loginPassword resources.LoginPassword
-		if err := json.Unmarshal(resource.Data, &loginPassword); err != nil {
-			return nil, err
+func (s *resourceService) decryptLabels(labels []byte) ([]byte, error) {
+	if len(labels) == 0 {
+		return nil, nil
+	}
+	return s.cryptoService.Decrypt(labels)
+}
+
+func (s *resourceService) UpdateLabels(ctx context.Context, labels map[int32]resources.Labels) error {
+	update := &pb.LabelsUpdate{Resources: make([]*pb.ResourceLabels, 0, len(labels))}
+	for resId, resLabels := range labels {
+		var encrypted []byte
+		if !resLabels.IsEmpty() {
+			labelsJson, err := json.Marshal(resLabels)
+			if err != nil {
+				return err
+			}
+			if encrypted, err = s.cryptoService.Encrypt(labelsJson); err != nil {
+				return err
+			}
+		}
+		tokens, err := s.blindIndexes(resLabels.IndexTerms())
+		if err != nil {
+			return err
 		}
+		update.Resources = append(update.Resources, &pb.ResourceLabels{Id: resId, Labels: encrypted, Tokens: tokens})
+	}
+	_, err := s.resourceClient.UpdateLabels(ctx, update)
+	return err
+}
 
-		return &resources.Info{Resource: &loginPassword, Meta: resource.Meta}, nil
+func (s *resourceService) Batch(ctx context.Context, operations []clmodel.BatchOperation, atomic bool) ([]clmodel.BatchResult, error) {
+	request := &pb.BatchRequest{Operations: make([]*pb.BatchOperation, 0, len(operations)), Atomic: atomic}
+	for _, operation := range operations {
+		resource := &pb.Resource{Id: operation.Id, Type: registry.ToWire(operation.Type), Meta: operation.Meta}
+		if operation.Kind != clmodel.BatchDelete {
+			encryptedData, err := s.cryptoService.Encrypt(s.compressor.compress(operation.Type, operation.Data))
+			if err != nil {
+				return nil, err
+			}
+			resource.Data = encryptedData
+		}
+		request.Operations = append(request.Operations, &pb.BatchOperation{Kind: batchKinds[operation.Kind], Resource: resource})
+	}
+	response, err := s.resourceClient.Batch(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	if len(response.Results) != len(operations) {
+		return nil, fmt.Errorf("batch has %d results for %d operations", len(response.Results), len(operations))
+	}
+	results := make([]clmodel.BatchResult, 0, len(response.Results))
+	for _, result := range response.Results {
+		batchResult := clmodel.BatchResult{Id: result.Id}
+		if result.Aborted {
+			batchResult.Err = ErrBatchAborted
+		} else if result.Error != "" {
+			batchResult.Err = errors.New(result.Error)
+		}
+		results = append(results, batchResult)
+	}
+	return results, nil
+}
 
-	case enum.BankCard:
-		var bankCard resources.BankCard
-		if err := json.Unmarshal(resource.Data, &bankCard); err != nil {
+func (s *resourceService) Usage(ctx context.Context) (*clmodel.Usage, error) {
+	report, err := s.resourceClient.Usage(ctx, &emptypb.Empty{})
+	if err != nil {
+		return nil, err
+	}
+	usage := &clmodel.Usage{
+		Types:             make([]clmodel.TypeUsage, 0, len(report.Types)),
+		User:              toQuotaUsage(report.User),
+		Organization:      report.Organization,
+		OrganizationUsage: toQuotaUsage(report.OrganizationUsage),
+	}
+	for _, typeUsage := range report.Types {
+		resType, err := registry.FromWire(typeUsage.Type)
+		if err != nil {
 			return nil, err
 		}
-		return &resources.Info{Resource: &bankCard, Meta: resource.Meta}, nil
+		kind, _ := resources.KindOf(resType)
+		usage.Types = append(usage.Types, clmodel.TypeUsage{Type: kind.Alias, Items: typeUsage.Items, Bytes: typeUsage.Bytes})
+	}
+	return usage, nil
+}
+
+func toQuotaUsage(usage *pb.QuotaUsage) clmodel.QuotaUsage {
+	return clmodel.QuotaUsage{
+		Items:    usage.GetItems(),
+		Bytes:    usage.GetBytes(),
+		MaxItems: usage.GetMaxItems(),
+		MaxBytes: usage.GetMaxBytes(),
+	}
+}
+
+func (s *resourceService) parseResource(resource *pb.Resource) (*resources.Info, error) {
+	resType, err := registry.FromWire(resource.Type)
+	if err != nil {
+		return nil, err
+	}
+	kind, ok := resources.KindOf(resType)
+	if !ok {
+		return nil, fmt.Errorf("undefined type %v", resource.Type)
+	}
+	res := kind.New()
+	if err := json.Unmarshal(resource.Data, res); err != nil {
+		return nil, err
+	}
+	labels, err := resources.ParseLabels(resource.Labels)
+	if err != nil {
+		return nil, err
+	}
+	return &resources.Info{Resource: res, Meta: resource.Meta, Labels: labels}, nil
+}
+
+func (s *resourceService) SaveFile(ctx context.Context, path string, meta []byte, fields []resources.CustomField) (int32, error) {
+	return s.uploadFile(ctx, 0, path, meta, fields)
+}
+
+func (s *resourceService) ReplaceFile(ctx context.Context, resId int32, path string, meta []byte) error {
+	info, err := s.Get(ctx, resId)
+	if err != nil {
+		return err
+	}
+	file, ok := info.Resource.(*resources.File)
+	if !ok {
+		return fmt.Errorf("resource %d is not a file", resId)
 	}
-	return nil, fmt.Errorf("undefined type %v", resource.Type)
+	_, err = s.uploadFile(ctx, resId, path, meta, file.Fields)
+	return err
 }
 
-func (s *resourceService) SaveFile(ctx context.Context, path string, meta []byte) (int32, error) {
+// uploadFile saves the new file or replaces content of the file with resId
+func (s *resourceService) uploadFile(ctx context.Context, resId int32, path string, meta []byte, fields []resources.CustomField) (int32, error) {
 	stream, err := s.resourceClient.SaveFile(ctx)
 	if err != nil {
 		return 0, err
 	}
+	snapshot, contentHash, err := snapshotFile(path)
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(snapshot)
+	stat, err := os.Stat(snapshot)
+	if err != nil {
+		return 0, err
+	}
+	compressed := s.compressor.compressesFile(path, stat.Size())
+	key, err := fileKey(s.cryptoService, contentHash, compressed)
+	if err != nil {
+		return 0, err
+	}
+	sealer, err := newFileSealer(key, compressed)
+	if err != nil {
+		return 0, err
+	}
+	encryptedKey, err := s.cryptoService.Encrypt(key)
+	if err != nil {
+		return 0, err
+	}
+	digest, err := encryptedDigest(key, compressed, snapshot)
+	if err != nil {
+		return 0, err
+	}
 	errCh := make(chan error)
-	chunks, stat, err := s.fileService.ReadFile(path, errCh)
+	chunks, stat, err := s.fileService.ReadFile(snapshot, errCh)
 	if err != nil {
 		return 0, err
 	}
-	fileDescriptionJson, err := json.Marshal(resources.File{
-		Name:      stat.Name(),
+	progress := intsrv.ProgressFrom(ctx)
+	progress.SetTotal(stat.Size())
+	description := resources.File{
+		Name:      filepath.Base(path),
 		Extension: filepath.Ext(path),
 		Size:      stat.Size(),
-	})
+		Key:       encryptedKey,
+		Digest:    digest,
+	}
+	description.Fields = fields
+	if compressed {
+		description.Compression = zstdCompression
+	}
+	fileDescriptionJson, err := s.sealFileDescription(&description)
+	if err != nil {
+		return 0, err
+	}
 	err = stream.Send(&pb.FileChunk{
-		Meta: meta,
-		Data: fileDescriptionJson,
+		Id:         resId,
+		Meta:       meta,
+		Data:       fileDescriptionJson,
+		Digest:     intsrv.Digest(fileDescriptionJson),
+		FileDigest: digest,
 	})
 	if err != nil {
 		return 0, err
@@ -178,69 +477,219 @@ func (s *resourceService) SaveFile(ctx context.Context, path string, meta []byte
 		if !ok {
 			break
 		}
-		encrypt, err := s.cryptoService.Encrypt(chunk)
+		// closed errCh stops the reader even if it has read the whole file
+		if err := ctx.Err(); err != nil {
+			close(errCh)
+			return 0, err
+		}
+		encrypt, err := sealer.Seal(chunk)
 		if err != nil {
+			close(errCh)
 			return 0, err
 		}
+		progress.Add(chunk)
+		if len(encrypt) == 0 {
+			continue
+		}
 		err = stream.Send(&pb.FileChunk{
-			Data: encrypt,
+			Data:   encrypt,
+			Digest: intsrv.Digest(encrypt),
 		})
 		if err != nil {
-			errCh <- err
+			close(errCh)
 			return 0, err
 		}
 	}
-	resId, err := stream.CloseAndRecv()
+	final, err := sealer.Close()
+	if err != nil {
+		return 0, err
+	}
+	if err := stream.Send(&pb.FileChunk{Data: final, Digest: intsrv.Digest(final)}); err != nil {
+		return 0, err
+	}
+	id, err := stream.CloseAndRecv()
 	if err != nil {
 		return 0, err
 	}
-	return resId.Id, nil
+	return id.Id, nil
+}
+
+func (s *resourceService) RenameFile(ctx context.Context, resId int32, name string, meta []byte, fields []resources.CustomField) error {
+	info, err := s.Get(ctx, resId)
+	if err != nil {
+		return err
+	}
+	file, ok := info.Resource.(*resources.File)
+	if !ok {
+		return fmt.Errorf("resource %d is not a file", resId)
+	}
+	if name != "" {
+		file.Name = name
+		file.Extension = filepath.Ext(name)
+	}
+	if meta == nil {
+		meta = info.Meta
+	}
+	if fields != nil {
+		file.Fields = fields
+	}
+	data, err := s.sealFileDescription(file)
+	if err != nil {
+		return err
+	}
+	_, err = s.resourceClient.Update(ctx, &pb.Resource{Id: resId, Type: pb.TYPE_FILE, Data: data, Meta: meta})
+	return err
+}
+
+func (s *resourceService) GetFileTo(ctx context.Context, resId int32, path string) error {
+	return s.GetFileVersionTo(ctx, resId, 0, path)
 }
 
-func (s *resourceService) GetFile(ctx context.Context, resId int32) (string, error) {
-	stream, err := s.resourceClient.GetFile(ctx, &pb.ResourceId{Id: resId})
+func (s *resourceService) GetFileVersionTo(ctx context.Context, resId int32, version int32, path string) error {
+	temp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.part")
 	if err != nil {
-		return "", err
+		return err
+	}
+	err = s.readFile(ctx, resId, version, temp)
+	if closeErr := temp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(temp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(temp.Name())
+		return err
+	}
+	return nil
+}
+
+func (s *resourceService) WriteFile(ctx context.Context, resId int32, w io.Writer) error {
+	return s.readFile(ctx, resId, 0, w)
+}
+
+func (s *resourceService) WriteFileVersion(ctx context.Context, resId int32, version int32, w io.Writer) error {
+	return s.readFile(ctx, resId, version, w)
+}
+
+func (s *resourceService) FileVersions(ctx context.Context, resId int32) (clmodel.FileVersions, error) {
+	response, err := s.resourceClient.GetFileVersions(ctx, &pb.ResourceId{Id: resId})
+	if err != nil {
+		return nil, err
+	}
+	versions := make(clmodel.FileVersions, 0, len(response.Versions))
+	for _, version := range response.Versions {
+		var description resources.File
+		if err := json.Unmarshal(version.Data, &description); err != nil {
+			return nil, err
+		}
+		size := description.Size
+		if size == 0 {
+			size = version.Size
+		}
+		versions = append(versions, clmodel.FileVersion{
+			Version:    version.Version,
+			Name:       description.Name,
+			Size:       size,
+			ReplacedAt: optionalTime(version.ReplacedAt),
+		})
+	}
+	return versions, nil
+}
+
+// newFileDecrypter returns decrypter writing the file content to w,
+// files uploaded without content key are decrypted chunk by chunk with the vault key
+func (s *resourceService) newFileDecrypter(description *resources.File, w io.Writer) (fileDecrypter, error) {
+	if description.Key == nil {
+		return &chunkDecrypter{decrypt: s.cryptoService.Decrypt, w: w}, nil
+	}
+	key, err := s.cryptoService.Decrypt(description.Key)
+	if err != nil {
+		return nil, err
+	}
+	switch description.Compression {
+	case "", zstdCompression:
+	default:
+		return nil, fmt.Errorf("file compression '%s' is not supported", description.Compression)
+	}
+	return newFileOpener(key, description.Compression == zstdCompression, w)
+}
+
+// fileChunkReceiver is the stream of the current file content or of its version
+type fileChunkReceiver interface {
+	Recv() (*pb.FileChunk, error)
+}
+
+// readFile verifies digests of the file chunks and writes decrypted content to w, version zero is the current content,
+// the whole file digest is verified before the last chunk is written
+func (s *resourceService) readFile(ctx context.Context, resId int32, version int32, w io.Writer) error {
+	var stream fileChunkReceiver
+	var err error
+	if version == 0 {
+		stream, err = s.resourceClient.GetFile(ctx, &pb.ResourceId{Id: resId})
+	} else {
+		stream, err = s.resourceClient.GetFileVersion(ctx, &pb.FileVersionId{Id: resId, Version: version})
+	}
+	if err != nil {
+		return err
 	}
 	chunk, err := stream.Recv()
 	if err != nil {
-		return "", err
+		return err
+	}
+	if err := intsrv.VerifyDigest(chunk.Data, chunk.Digest); err != nil {
+		return fmt.Errorf("file description: %w", err)
 	}
 	var fileDescription resources.File
 	err = json.Unmarshal(chunk.Data, &fileDescription)
 	if err != nil {
-		return "", err
+		return err
+	}
+	// the digest recorded by the client is checked against the server one to detect a swapped blob
+	if fileDescription.Digest != nil && !bytes.Equal(fileDescription.Digest, chunk.FileDigest) {
+		return fmt.Errorf("file %w", restype.ErrDigestMismatch)
 	}
-	path := fmt.Sprintf("./%s", fileDescription.Name)
-	chunks := make(chan []byte)
-	errCh, err := s.fileService.SaveFile(path, chunks)
+	decrypter, err := s.newFileDecrypter(&fileDescription, w)
 	if err != nil {
-		return "", err
+		return err
 	}
-Loop:
+	defer decrypter.Abort()
+	progress := intsrv.ProgressFrom(ctx)
+	progress.SetTotal(chunk.Size)
+	hash := sha256.New()
 	for {
 		chunk, err := stream.Recv()
 		if err == io.EOF {
-			close(chunks)
-			break Loop
+			break
 		}
 		if err != nil {
-			close(chunks)
 			s.log.Errorf("failed to recieve file stream chunk: %v", err)
-			return "", err
+			return err
 		}
-
-		decrypt, err := s.cryptoService.Decrypt(chunk.Data)
-		if err != nil {
-			s.log.Errorf("failed to decrypt file stream chunk: %v", err)
-			return "", err
+		if err := intsrv.VerifyDigest(chunk.Data, chunk.Digest); err != nil {
+			return fmt.Errorf("file chunk: %w", err)
 		}
-		select {
-		case chunks <- decrypt:
-		case _ = <-errCh:
-			close(chunks)
-			break Loop
+		hash.Write(chunk.Data)
+		progress.Add(chunk.Data)
+		if err := decrypter.Open(chunk.Data); err != nil {
+			s.log.Errorf("failed to decrypt file stream chunk: %v", err)
+			return err
 		}
 	}
-	return path, err
+	if fileDescription.Digest != nil && !bytes.Equal(hash.Sum(nil), fileDescription.Digest) {
+		return fmt.Errorf("file %w", restype.ErrDigestMismatch)
+	}
+	if err := decrypter.Close(); err != nil {
+		s.log.Errorf("failed to decrypt file stream end: %v", err)
+		return err
+	}
+	return nil
+}
+
+// optionalTime returns the zero time if the timestamp is not set, AsTime would return the unix epoch
+func optionalTime(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
 }
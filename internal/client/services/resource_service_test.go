@@ -0,0 +1,212 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+
+	"ydx-goadv-gophkeeper/internal/client/mocks/services"
+	"ydx-goadv-gophkeeper/internal/client/model"
+	"ydx-goadv-gophkeeper/internal/client/model/resources"
+	clservices "ydx-goadv-gophkeeper/internal/client/services"
+	pkgmocks "ydx-goadv-gophkeeper/pkg/mocks/services"
+	"ydx-goadv-gophkeeper/pkg/model/enum"
+	"ydx-goadv-gophkeeper/pkg/pb"
+	intsrv "ydx-goadv-gophkeeper/pkg/services"
+)
+
+// resourcesClient keeps saved resources and the chunks of the saved file in memory
+type resourcesClient struct {
+	pb.ResourcesClient
+	saved []*pb.Resource
+	file  []*pb.FileChunk
+}
+
+func (c *resourcesClient) Save(_ context.Context, resource *pb.Resource, _ ...grpc.CallOption) (*pb.ResourceId, error) {
+	c.saved = append(c.saved, resource)
+	return &pb.ResourceId{Id: int32(len(c.saved))}, nil
+}
+
+func (c *resourcesClient) Get(_ context.Context, id *pb.ResourceId, _ ...grpc.CallOption) (*pb.Resource, error) {
+	return c.saved[id.Id-1], nil
+}
+
+func (c *resourcesClient) SaveFile(_ context.Context, _ ...grpc.CallOption) (pb.Resources_SaveFileClient, error) {
+	c.file = nil
+	return &fileStream{client: c}, nil
+}
+
+func (c *resourcesClient) GetFile(_ context.Context, _ *pb.ResourceId, _ ...grpc.CallOption) (pb.Resources_GetFileClient, error) {
+	return &fileStream{client: c, chunks: c.file}, nil
+}
+
+type fileStream struct {
+	grpc.ClientStream
+	client *resourcesClient
+	chunks []*pb.FileChunk
+}
+
+func (s *fileStream) Send(chunk *pb.FileChunk) error {
+	s.client.file = append(s.client.file, chunk)
+	return nil
+}
+
+func (s *fileStream) CloseAndRecv() (*pb.ResourceId, error) {
+	return &pb.ResourceId{Id: 1}, nil
+}
+
+func (s *fileStream) Recv() (*pb.FileChunk, error) {
+	if len(s.chunks) == 0 {
+		return nil, io.EOF
+	}
+	chunk := s.chunks[0]
+	s.chunks = s.chunks[1:]
+	return chunk, nil
+}
+
+func TestResourceService_Compression(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	cryptService := services.NewMockCryptService(ctrl)
+	identity := func(data []byte) ([]byte, error) { return data, nil }
+	cryptService.EXPECT().Encrypt(gomock.Any()).DoAndReturn(identity).AnyTimes()
+	cryptService.EXPECT().Decrypt(gomock.Any()).DoAndReturn(identity).AnyTimes()
+	client := &resourcesClient{}
+	resourceService := clservices.NewResourceService(client, intsrv.NewFileService(), cryptService, model.CompressionPolicy{Types: []string{"nt"}, MinSize: 64})
+
+	dump := resources.NewSecureNote(strings.Repeat("listen_address = 0.0.0.0:8080\n", 200))
+	data, err := json.Marshal(dump)
+	assert.NoError(t, err)
+	_, err = resourceService.Save(context.Background(), enum.SecureNote, data, []byte("config dump"))
+	assert.NoError(t, err)
+	assert.Less(t, len(client.saved[0].Data)*10, len(data))
+	info, err := resourceService.Get(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, dump, info.Resource)
+
+	// small payloads and other types are stored as is
+	for _, resource := range []resources.ResourceClIFormatter{resources.NewSecureNote("pin"), resources.NewLoginPassword("octocat", strings.Repeat("a", 100), "")} {
+		data, err := json.Marshal(resource)
+		assert.NoError(t, err)
+		_, err = resourceService.Save(context.Background(), resource.Type(), data, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, data, client.saved[len(client.saved)-1].Data)
+	}
+}
+
+func TestResourceService_CompressedFile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	cryptService := services.NewMockCryptService(ctrl)
+	identity := func(data []byte) ([]byte, error) { return data, nil }
+	cryptService.EXPECT().Encrypt(gomock.Any()).DoAndReturn(identity).AnyTimes()
+	cryptService.EXPECT().Decrypt(gomock.Any()).DoAndReturn(identity).AnyTimes()
+	blindIndex := func(data []byte) ([]byte, error) {
+		hash := sha256.Sum256(data)
+		return hash[:], nil
+	}
+	cryptService.EXPECT().BlindIndex(gomock.Any()).DoAndReturn(blindIndex).AnyTimes()
+	// decompressed content is larger than the limit of compressed payloads
+	content := bytes.Repeat([]byte("listen_address = 0.0.0.0:8080\n"), 3<<20)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "dump.txt")
+	assert.NoError(t, os.WriteFile(path, content, 0600))
+	// file service reads files of limited size, the content is streamed as is
+	fileService := pkgmocks.NewMockFileService(ctrl)
+	// the uploaded file is the snapshot of the path
+	fileService.EXPECT().ReadFile(gomock.Any(), gomock.Any()).DoAndReturn(func(path string, _ chan error) (chan []byte, os.FileInfo, error) {
+		chunks := make(chan []byte)
+		go func() {
+			defer close(chunks)
+			for start := 0; start < len(content); start += 1 << 20 {
+				end := start + 1<<20
+				if end > len(content) {
+					end = len(content)
+				}
+				chunks <- content[start:end]
+			}
+		}()
+		stat, err := os.Stat(path)
+		return chunks, stat, err
+	}).Times(2)
+	client := &resourcesClient{}
+	resourceService := clservices.NewResourceService(client, fileService, cryptService, model.CompressionPolicy{Types: []string{"fl"}, MinSize: 64})
+	_, err := resourceService.SaveFile(context.Background(), path, []byte("dump"), nil)
+	assert.NoError(t, err)
+	var description resources.File
+	assert.NoError(t, json.Unmarshal(client.file[0].Data, &description))
+	assert.Equal(t, "dump.txt", description.Name)
+	var stored int
+	for _, chunk := range client.file[1:] {
+		stored += len(chunk.Data)
+	}
+	assert.Less(t, stored*10, len(content))
+
+	restored := filepath.Join(dir, "restored.txt")
+	assert.NoError(t, resourceService.GetFileTo(context.Background(), 1, restored))
+	data, err := os.ReadFile(restored)
+	assert.NoError(t, err)
+	assert.True(t, bytes.Equal(content, data))
+
+	// truncated stream is not written
+	client.file = client.file[:len(client.file)-1]
+	assert.Error(t, resourceService.GetFileTo(context.Background(), 1, filepath.Join(dir, "truncated.txt")))
+	assert.NoFileExists(t, filepath.Join(dir, "truncated.txt"))
+
+	// the same content saved without compression is sealed with another key
+	rawPath := filepath.Join(dir, "dump.jpg")
+	assert.NoError(t, os.WriteFile(rawPath, content, 0600))
+	_, err = resourceService.SaveFile(context.Background(), rawPath, []byte("dump"), nil)
+	assert.NoError(t, err)
+	var rawDescription resources.File
+	assert.NoError(t, json.Unmarshal(client.file[0].Data, &rawDescription))
+	assert.Empty(t, rawDescription.Compression)
+	assert.NotEqual(t, description.Key, rawDescription.Key)
+}
+
+func TestResourceService_FileCustomFields(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	cryptService := services.NewMockCryptService(ctrl)
+	invert := func(data []byte) ([]byte, error) {
+		inverted := make([]byte, len(data))
+		for i, b := range data {
+			inverted[i] = ^b
+		}
+		return inverted, nil
+	}
+	cryptService.EXPECT().Encrypt(gomock.Any()).DoAndReturn(invert).AnyTimes()
+	cryptService.EXPECT().Decrypt(gomock.Any()).DoAndReturn(invert).AnyTimes()
+	cryptService.EXPECT().BlindIndex(gomock.Any()).DoAndReturn(func(data []byte) ([]byte, error) {
+		hash := sha256.Sum256(data)
+		return hash[:], nil
+	}).AnyTimes()
+	path := filepath.Join(t.TempDir(), "cert.pem")
+	assert.NoError(t, os.WriteFile(path, []byte("PEM"), 0600))
+	client := &resourcesClient{}
+	resourceService := clservices.NewResourceService(client, intsrv.NewFileService(), cryptService, model.CompressionPolicy{})
+
+	pin, err := resources.NewCustomField("pin", resources.HiddenField, "1234")
+	assert.NoError(t, err)
+	_, err = resourceService.SaveFile(context.Background(), path, []byte("cert"), []resources.CustomField{pin})
+	assert.NoError(t, err)
+	// description is stored as is, so custom fields are encrypted
+	assert.NotContains(t, string(client.file[0].Data), "1234")
+
+	client.saved = append(client.saved, &pb.Resource{Type: pb.TYPE_FILE, Data: client.file[0].Data})
+	info, err := resourceService.Get(context.Background(), 1)
+	assert.NoError(t, err)
+	file := info.Resource.(*resources.File)
+	assert.Equal(t, "cert.pem", file.Name)
+	assert.Equal(t, []resources.CustomField{pin}, file.Fields)
+}
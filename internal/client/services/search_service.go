@@ -0,0 +1,84 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"ydx-goadv-gophkeeper/internal/client/model/resources"
+	"ydx-goadv-gophkeeper/internal/client/search"
+	"ydx-goadv-gophkeeper/pkg/logger"
+	"ydx-goadv-gophkeeper/pkg/model/enum"
+)
+
+//go:generate mockgen -source=search_service.go -destination=../mocks/services/search_service.go -package=services
+
+type SearchService interface {
+	// Find searches resources by the query, i.e. 'github type:lp login:octo', results are ordered by score
+	Find(ctx context.Context, query string, limit int) ([]search.Result, error)
+}
+
+type searchService struct {
+	log             *zap.SugaredLogger
+	resourceService ResourceService
+	mu              sync.Mutex
+	index           *search.Index
+	// indexed are update times of the indexed resources
+	indexed map[int32]time.Time
+}
+
+func NewSearchService(resourceService ResourceService) SearchService {
+	return &searchService{
+		log:             logger.NewLogger("search-service"),
+		resourceService: resourceService,
+		index:           search.NewIndex(),
+		indexed:         make(map[int32]time.Time),
+	}
+}
+
+func (s *searchService) Find(ctx context.Context, query string, limit int) ([]search.Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.refresh(ctx); err != nil {
+		return nil, err
+	}
+	return s.index.Search(query, limit)
+}
+
+// refresh decrypts only new and updated resources, the query never leaves the client
+func (s *searchService) refresh(ctx context.Context) error {
+	descriptions, err := s.resourceService.GetDescriptions(ctx, enum.Nan)
+	if err != nil {
+		return err
+	}
+	actual := make(map[int32]bool, len(descriptions))
+	for _, description := range descriptions {
+		actual[description.Id] = true
+		labels, err := resources.ParseLabels(description.Labels)
+		if err != nil {
+			return fmt.Errorf("failed to parse labels of resource %d: %w", description.Id, err)
+		}
+		// labels update does not change resource update time
+		if updatedAt, ok := s.indexed[description.Id]; ok && updatedAt.Equal(description.UpdatedAt) {
+			s.index.SetLabels(description.Id, labels)
+			continue
+		}
+		info, err := s.resourceService.Get(ctx, description.Id)
+		if err != nil {
+			return fmt.Errorf("failed to get resource %d: %w", description.Id, err)
+		}
+		s.index.Put(search.NewDocument(description.Id, description.Type, string(description.Meta), labels, info.Resource))
+		s.indexed[description.Id] = description.UpdatedAt
+	}
+	for id := range s.indexed {
+		if !actual[id] {
+			s.index.Remove(id)
+			delete(s.indexed, id)
+		}
+	}
+	s.log.Debugf("search index has %d resources", s.index.Len())
+	return nil
+}
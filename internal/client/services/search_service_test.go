@@ -0,0 +1,47 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"ydx-goadv-gophkeeper/internal/client/mocks/services"
+	"ydx-goadv-gophkeeper/internal/client/model/resources"
+	clservices "ydx-goadv-gophkeeper/internal/client/services"
+	srvmodel "ydx-goadv-gophkeeper/internal/server/model"
+	"ydx-goadv-gophkeeper/pkg/model/enum"
+)
+
+func TestSearchService_Find(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	resourceService := services.NewMockResourceService(ctrl)
+
+	updatedAt := time.Now()
+	github := &srvmodel.ResourceDescription{Id: 1, Type: enum.LoginPassword, Meta: []byte("github"), UpdatedAt: updatedAt}
+	note := &srvmodel.ResourceDescription{Id: 2, Type: enum.SecureNote, Meta: []byte("wifi"), UpdatedAt: updatedAt}
+	resourceService.EXPECT().GetDescriptions(gomock.Any(), enum.Nan).Return([]*srvmodel.ResourceDescription{github, note}, nil)
+	resourceService.EXPECT().Get(gomock.Any(), int32(1)).Return(&resources.Info{Resource: resources.NewLoginPassword("octocat", "secret", "")}, nil)
+	resourceService.EXPECT().Get(gomock.Any(), int32(2)).Return(&resources.Info{Resource: resources.NewSecureNote("qwerty")}, nil)
+
+	searchService := clservices.NewSearchService(resourceService)
+	results, err := searchService.Find(context.Background(), "octocat", 0)
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, int32(1), results[0].Id)
+
+	// only updated resource is decrypted again, deleted one is removed from index
+	updated := *github
+	updated.UpdatedAt = updatedAt.Add(time.Minute)
+	resourceService.EXPECT().GetDescriptions(gomock.Any(), enum.Nan).Return([]*srvmodel.ResourceDescription{&updated}, nil).Times(2)
+	resourceService.EXPECT().Get(gomock.Any(), int32(1)).Return(&resources.Info{Resource: resources.NewLoginPassword("hubot", "secret", "")}, nil)
+	results, err = searchService.Find(context.Background(), "octocat", 0)
+	assert.NoError(t, err)
+	assert.Empty(t, results)
+	results, err = searchService.Find(context.Background(), "qwerty", 0)
+	assert.NoError(t, err)
+	assert.Empty(t, results)
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"ydx-goadv-gophkeeper/internal/client/model/resources"
+)
+
+const refPrefix = "ref://"
+
+//go:generate mockgen -source=secret_resolver.go -destination=../mocks/services/secret_resolver.go -package=services
+
+// SecretResolver resolves references like 'ref://42/password' to the decrypted field values
+type SecretResolver interface {
+	Resolve(ctx context.Context, ref string) (string, error)
+	ResolveField(ctx context.Context, resId int32, field string) (string, error)
+}
+
+type secretResolver struct {
+	resourceService ResourceService
+	cache           map[int32]*resources.Info
+}
+
+func NewSecretResolver(resourceService ResourceService) SecretResolver {
+	return &secretResolver{
+		resourceService: resourceService,
+		cache:           make(map[int32]*resources.Info),
+	}
+}
+
+func (r *secretResolver) Resolve(ctx context.Context, ref string) (string, error) {
+	resId, field, err := ParseRef(ref)
+	if err != nil {
+		return "", err
+	}
+	return r.ResolveField(ctx, resId, field)
+}
+
+func (r *secretResolver) ResolveField(ctx context.Context, resId int32, field string) (string, error) {
+	info, ok := r.cache[resId]
+	if !ok {
+		var err error
+		info, err = r.resourceService.Get(ctx, resId)
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve resource '%d': %v", resId, err)
+		}
+		r.cache[resId] = info
+	}
+	return info.Field(field)
+}
+
+// ParseRef parses reference in format 'ref://[id]/[field]'
+func ParseRef(ref string) (int32, string, error) {
+	if !strings.HasPrefix(ref, refPrefix) {
+		return 0, "", fmt.Errorf("reference '%s' must start with '%s'", ref, refPrefix)
+	}
+	idStr, field, ok := strings.Cut(strings.TrimPrefix(ref, refPrefix), "/")
+	if !ok || field == "" {
+		return 0, "", fmt.Errorf("reference '%s' must be in format '%s[id]/[field]'", ref, refPrefix)
+	}
+	resId, err := strconv.ParseInt(idStr, 10, 32)
+	if err != nil {
+		return 0, "", fmt.Errorf("reference '%s' has invalid id: %v", ref, err)
+	}
+	return int32(resId), field, nil
+}
@@ -4,14 +4,24 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"os/exec"
+	"sort"
 	"strconv"
 	"strings"
 	"syscall"
+	"text/tabwriter"
+	"time"
 
+	"github.com/spf13/pflag"
 	"golang.org/x/term"
 
+	"ydx-goadv-gophkeeper/internal/client/generator"
+	"ydx-goadv-gophkeeper/internal/client/importers"
+	clmodel "ydx-goadv-gophkeeper/internal/client/model"
 	"ydx-goadv-gophkeeper/internal/client/model/resources"
 	"ydx-goadv-gophkeeper/internal/client/services"
 	"ydx-goadv-gophkeeper/pkg/model"
@@ -20,24 +30,70 @@ import (
 )
 
 const (
-	maxCapacity   = 1024 * 1024
-	successResult = "success"
-	helpMsg       = "" +
-		"available commands:\n" +
-		"	'clear' - to clear terminal\n" +
-		"\n" +
-		"	'login' - to login\n" +
-		"	'register' - to register\n" +
-		"\n" +
-		"	's [type]' - save resource, where 'type' is: lp - LoginPassword, fl - File, bc - BankCard\n" +
-		"\n" +
-		"	'u [id]' - update resource\n" +
-		"	'd [id]' - delete resource by id\n" +
-		"	'l [type]' - get resources by type, where 'type' is: lp - LoginPassword, fl - File, bc - BankCard\n	or get all if type is empty\n" +
-		"	'g [id]' - get loginPassword or BankCard by id\n" +
-		"	'gf [id]' - get file by id\n"
+	maxCapacity      = 1024 * 1024
+	successResult    = "success"
+	maxInputAttempts = 3
 )
 
+var helpMsg = "" +
+	"available commands:\n" +
+	"	'clear' - to clear terminal\n" +
+	"\n" +
+	"	'login' - to login\n" +
+	"	'register' - to register\n" +
+	"\n" +
+	"	's [type]' - save resource, where 'type' is: " + typesHelp() + "\n" +
+	"	's fl --bg' uploads the file in background\n" +
+	"\n" +
+	"	every resource can carry custom fields: text, hidden, url, email, date\n" +
+	"\n" +
+	"	'u [id]' - update resource, file content is replaced keeping its id and previous versions, file can be renamed\n" +
+	"	'd [id]' - delete resource by id\n" +
+	"	'l [type...] [--folder path] [-r] [--tag tag]' - get resources by types, where 'type' is: " + typesHelp() + "\n" +
+	"	or get all if type is empty, '--folder' shows subfolders and resources of the folder, '-r' includes\n" +
+	"	subfolders resources, '--tag' filters resources with the tag, it can be repeated\n" +
+	"	'--sort id|created|updated' and '--desc' order resources, '--since YYYY-MM-DD' lists resources updated\n" +
+	"	since the date, '--limit n' lists a page of resources, next page is listed with '--cursor'\n" +
+	"	'mv [folder] [id...]' - move resources to the folder, '/' is the root folder\n" +
+	"	'tag [tag] [id...]', 'untag [tag] [id...]' - add or remove tag of resources\n" +
+	"	'g [id] [--reveal]' - get resource by id, File prints its description, Totp prints current code\n" +
+	"	hidden custom fields are masked unless '--reveal' is set\n" +
+	"	'gf [id] [dest] [-f] [--stdout] [--bg] [--version n]' - get file by id, saved to 'dest' file or directory,\n" +
+	"	the working directory by default, '-f' overwrites existing file without confirmation, '--stdout' prints\n" +
+	"	the file content, '--bg' downloads the file in background, '--version' gets the previous content\n" +
+	"	'versions [id]' - list previous versions of the file\n" +
+	"	'jobs' - list file transfers with progress, 'cancel [job]' - cancel the background transfer and remove\n" +
+	"	partial file, Ctrl-C cancels the running foreground transfer\n" +
+	"	'find [query] [--limit 20]' - fuzzy search by description, login, url and other not secret fields,\n" +
+	"	filters: 'type:lp', 'id:1', 'tag:prod', 'folder:work', '[field]:[value]', i.e. 'find github type:lp tag:prod'\n" +
+	"\n" +
+	"	'gen [length] [--no-lower] [--no-upper] [--no-digits] [--no-symbols] [--ambiguous]' - generate password\n" +
+	"	'gen words [count] [--sep separator] [--cap]' - generate diceware passphrase\n" +
+	"\n" +
+	"	'report [--days 180] [--expire-days 30] [--min-score 3] [--hibp file] [--json]' - report reused, weak, old\n" +
+	"	and breached passwords and expiring bank cards\n" +
+	"	'breach [file] [--json]' - check passwords against local sorted HIBP 'SHA1:COUNT' dataset,\n" +
+	"	'hibp_file' config by default\n" +
+	"\n" +
+	"	'import [format] [path] [--dry-run] [--map field=column,...] [--batch-size 20]' - import resources, where\n" +
+	"	'format' is: " + strings.Join(importers.Formats(), ", ") + "\n" +
+	"	KeePass database should be exported to xml, 'csv' format requires '--map', i.e.\n" +
+	"	'type=lp,login=Username,password=Password,url=URL,description=Title,field:pin=PIN'\n" +
+	"\n" +
+	"	'export [--out vault.gpk]' - export all resources and files to archive encrypted with passphrase\n" +
+	"	'restore [path] [--on-conflict skip|overwrite|keep]' - restore archive, conflicts are resources\n" +
+	"	of the same type and description\n" +
+	"\n" +
+	"	'usage' - show number and size of resources by type and storage quotas\n"
+
+func typesHelp() string {
+	var types []string
+	for _, kind := range resources.Kinds() {
+		types = append(types, fmt.Sprintf("%s - %s", kind.Alias, kind.Name))
+	}
+	return strings.Join(types, ", ")
+}
+
 type CommandParser interface {
 	Start(exit chan struct{})
 }
@@ -46,7 +102,14 @@ type commandParser struct {
 	scanner         *bufio.Scanner
 	authService     services.AuthService
 	resourceService services.ResourceService
+	reportService   services.ReportService
+	importService   services.ImportService
+	backupService   services.BackupService
+	searchService   services.SearchService
+	labelService    services.LabelService
+	generator       generator.Generator
 	exitHandler     shutdown.ExitHandler
+	transfers       *transfers
 	commands        map[string]func(args []string) (string, error)
 }
 
@@ -55,13 +118,26 @@ func NewCommandParser(
 	buildDate string,
 	authService services.AuthService,
 	resourceService services.ResourceService,
+	reportService services.ReportService,
+	importService services.ImportService,
+	backupService services.BackupService,
+	searchService services.SearchService,
+	labelService services.LabelService,
+	gen generator.Generator,
 	eh shutdown.ExitHandler,
 ) CommandParser {
 	fmt.Printf("buildVersion='%s' buildDate='%s'\n%s\n", buildVersion, buildDate, helpMsg)
 	cp := &commandParser{
 		authService:     authService,
 		resourceService: resourceService,
+		reportService:   reportService,
+		importService:   importService,
+		backupService:   backupService,
+		searchService:   searchService,
+		labelService:    labelService,
+		generator:       gen,
 		exitHandler:     eh,
+		transfers:       newTransfers(),
 	}
 	cp.commands = map[string]func(args []string) (string, error){
 		"login":    cp.handleLogin,
@@ -72,7 +148,21 @@ func NewCommandParser(
 		"l":        cp.handleList,
 		"g":        cp.handleGet,
 		"gf":       cp.handleGetFile,
+		"versions": cp.handleFileVersions,
+		"find":     cp.handleFind,
+		"mv":       cp.handleMove,
+		"tag":      cp.handleTag,
+		"untag":    cp.handleUntag,
 		"clear":    cp.handleClear,
+		"gen":      cp.handleGen,
+		"report":   cp.handleReport,
+		"breach":   cp.handleBreach,
+		"import":   cp.handleImport,
+		"export":   cp.handleExport,
+		"restore":  cp.handleRestore,
+		"usage":    cp.handleUsage,
+		"jobs":     cp.handleJobs,
+		"cancel":   cp.handleCancel,
 		"help":     cp.handleHelp,
 	}
 	return cp
@@ -87,6 +177,8 @@ Loop:
 		case <-commandHandled:
 		case <-exit:
 			fmt.Println("exit")
+			// exit handler waits for transfers, so they are canceled instead of being completed
+			cp.transfers.cancelAll()
 			break Loop
 		}
 	}
@@ -97,6 +189,9 @@ func (cp *commandParser) processCommands(commandHandled chan struct{}) {
 	defer func() {
 		commandHandled <- struct{}{}
 	}()
+	for _, finished := range cp.transfers.list(true) {
+		fmt.Println(finished)
+	}
 	cmd := cp.readString("")
 	if len(cmd) == 0 {
 		return
@@ -133,6 +228,210 @@ func (cp *commandParser) handleHelp(_ []string) (string, error) {
 	return "", nil
 }
 
+// handleReport prints vault health report as a table or json
+func (cp *commandParser) handleReport(args []string) (string, error) {
+	opts := services.DefaultReportOptions()
+	flags := pflag.NewFlagSet("report", pflag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	days := flags.Int("days", int(opts.MaxAge.Hours()/24), "report passwords not rotated for the days")
+	expireDays := flags.Int("expire-days", int(opts.ExpiresIn.Hours()/24), "report bank cards expiring in the days")
+	flags.IntVar(&opts.MinScore, "min-score", opts.MinScore, "report passwords with lower score, from 0 to 4")
+	flags.StringVar(&opts.HibpFile, "hibp", "", "HIBP dataset file to check breached passwords, 'hibp_file' config by default")
+	asJson := flags.Bool("json", false, "print report as json")
+	if err := flags.Parse(args); err != nil {
+		return "", err
+	}
+	opts.MaxAge = time.Duration(*days) * 24 * time.Hour
+	opts.ExpiresIn = time.Duration(*expireDays) * 24 * time.Hour
+
+	report, err := cp.reportService.Build(context.Background(), opts)
+	if err != nil {
+		return "", err
+	}
+	if *asJson {
+		return report.JSON()
+	}
+	return report.Table(), nil
+}
+
+// handleBreach checks passwords against local HIBP dataset
+func (cp *commandParser) handleBreach(args []string) (string, error) {
+	flags := pflag.NewFlagSet("breach", pflag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	asJson := flags.Bool("json", false, "print result as json")
+	if err := flags.Parse(args); err != nil {
+		return "", err
+	}
+	report, err := cp.reportService.Breaches(context.Background(), flags.Arg(0))
+	if err != nil {
+		return "", err
+	}
+	if *asJson {
+		return report.JSON()
+	}
+	return report.Table(), nil
+}
+
+// handleImport parses export file and uploads its resources which are not in the vault yet
+func (cp *commandParser) handleImport(args []string) (string, error) {
+	flags := pflag.NewFlagSet("import", pflag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	var opts services.ImportOptions
+	flags.BoolVar(&opts.DryRun, "dry-run", false, "print summary without saving")
+	flags.IntVar(&opts.BatchSize, "batch-size", services.DefaultImportBatchSize, "number of resources uploaded concurrently")
+	mapping := flags.String("map", "", "csv columns mapping")
+	if err := flags.Parse(args); err != nil {
+		return "", err
+	}
+	if opts.BatchSize < 1 || opts.BatchSize > services.MaxBatchSize {
+		return "", fmt.Errorf("batch size should be from 1 to %d", services.MaxBatchSize)
+	}
+	if flags.NArg() < 2 {
+		return "", fmt.Errorf("args '[format] [path]' are empty, type 'help' to display available commands format")
+	}
+	parser, err := importers.NewParser(flags.Arg(0), *mapping)
+	if err != nil {
+		return "", err
+	}
+	entries, err := parser.Parse(flags.Arg(1))
+	if err != nil {
+		return "", err
+	}
+	cp.exitHandler.AddFuncInProcessing("importing resources")
+	defer cp.exitHandler.FuncFinished("importing resources")
+	summary, err := cp.importService.Import(context.Background(), entries, opts)
+	if err != nil {
+		if summary != nil {
+			return "", fmt.Errorf("%v\n%s", err, summary)
+		}
+		return "", err
+	}
+	return summary.String(), nil
+}
+
+// handleExport writes archive to temp file and renames it when export is done
+func (cp *commandParser) handleExport(args []string) (string, error) {
+	flags := pflag.NewFlagSet("export", pflag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	out := flags.String("out", "vault.gpk", "archive path")
+	if err := flags.Parse(args); err != nil {
+		return "", err
+	}
+	fmt.Println("input archive passphrase")
+	passphrase := cp.readPassword()
+	if passphrase == "" {
+		return "", fmt.Errorf("passphrase is empty")
+	}
+	fmt.Println("repeat archive passphrase")
+	if cp.readPassword() != passphrase {
+		return "", fmt.Errorf("passphrases do not match")
+	}
+
+	cp.exitHandler.AddFuncInProcessing("exporting vault")
+	defer cp.exitHandler.FuncFinished("exporting vault")
+	tmpPath := *out + ".tmp"
+	file, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return "", err
+	}
+	manifest, err := cp.backupService.Export(context.Background(), file, []byte(passphrase))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return "", err
+	}
+	if err := os.Rename(tmpPath, *out); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("exported %d resources to %s", len(manifest.Resources), *out), nil
+}
+
+func (cp *commandParser) handleRestore(args []string) (string, error) {
+	flags := pflag.NewFlagSet("restore", pflag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	onConflict := flags.String("on-conflict", string(services.ConflictSkip), "skip, overwrite or keep both resources")
+	if err := flags.Parse(args); err != nil {
+		return "", err
+	}
+	if flags.NArg() == 0 {
+		return "", fmt.Errorf("arg '[path]' is empty, type 'help' to display available commands format")
+	}
+	policy, err := services.ParseConflictPolicy(*onConflict)
+	if err != nil {
+		return "", err
+	}
+	file, err := os.Open(flags.Arg(0))
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	fmt.Println("input archive passphrase")
+	passphrase := cp.readPassword()
+
+	cp.exitHandler.AddFuncInProcessing("restoring vault")
+	defer cp.exitHandler.FuncFinished("restoring vault")
+	summary, err := cp.backupService.Restore(context.Background(), file, []byte(passphrase), policy)
+	if err != nil {
+		if summary != nil {
+			return "", fmt.Errorf("%v\n%s", err, summary)
+		}
+		return "", err
+	}
+	return summary.String(), nil
+}
+
+// handleGen prints generated password or diceware passphrase if the first arg is 'words'
+func (cp *commandParser) handleGen(args []string) (string, error) {
+	flags := pflag.NewFlagSet("gen", pflag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	passwordOpts := generator.DefaultPasswordOptions()
+	noLower := flags.Bool("no-lower", false, "exclude lower case letters")
+	noUpper := flags.Bool("no-upper", false, "exclude upper case letters")
+	noDigits := flags.Bool("no-digits", false, "exclude digits")
+	noSymbols := flags.Bool("no-symbols", false, "exclude symbols")
+	ambiguous := flags.Bool("ambiguous", false, "allow ambiguous characters like 'l', '1', 'O', '0'")
+	passphraseOpts := generator.DefaultPassphraseOptions()
+	flags.StringVar(&passphraseOpts.Separator, "sep", passphraseOpts.Separator, "passphrase words separator")
+	flags.BoolVar(&passphraseOpts.Capitalize, "cap", false, "capitalize passphrase words")
+	if err := flags.Parse(args); err != nil {
+		return "", err
+	}
+
+	words := flags.Arg(0) == "words"
+	countArg := flags.Arg(0)
+	if words {
+		countArg = flags.Arg(1)
+	}
+	if countArg != "" {
+		count, err := strconv.Atoi(countArg)
+		if err != nil {
+			return "", fmt.Errorf("length '%s' is not a number", countArg)
+		}
+		passwordOpts.Length = count
+		passphraseOpts.Words = count
+	}
+
+	var secret string
+	var entropy float64
+	var err error
+	if words {
+		secret, entropy, err = cp.generator.Passphrase(passphraseOpts)
+	} else {
+		passwordOpts.Lower = !*noLower
+		passwordOpts.Upper = !*noUpper
+		passwordOpts.Digits = !*noDigits
+		passwordOpts.Symbols = !*noSymbols
+		passwordOpts.ExcludeAmbiguous = !*ambiguous
+		secret, entropy, err = cp.generator.Password(passwordOpts)
+	}
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s\nentropy: %.0f bits (%s)", secret, entropy, generator.Strength(entropy)), nil
+}
+
 func (cp *commandParser) handleLogin(_ []string) (string, error) {
 	login := cp.readString("input username")
 	password := cp.readPassword()
@@ -147,7 +446,58 @@ func (cp *commandParser) handleRegistration(_ []string) (string, error) {
 	return successResult, err
 }
 
+// handleGetFile saves the file to the destination, the stored file name in the working directory by default,
+// '--stdout' prints the file content, '--bg' downloads the file in background
 func (cp *commandParser) handleGetFile(args []string) (string, error) {
+	flags := pflag.NewFlagSet("gf", pflag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	toStdout := flags.Bool("stdout", false, "print the file content")
+	force := flags.BoolP("force", "f", false, "overwrite existing file without confirmation")
+	background := flags.Bool("bg", false, "download the file in background")
+	version := flags.Int32("version", 0, "previous version of the file")
+	if err := flags.Parse(args); err != nil {
+		return "", err
+	}
+	if flags.NArg() == 0 {
+		return "", fmt.Errorf("arg '[id]' is empty, type 'help' to display available commands format")
+	}
+	resId, err := strconv.ParseInt(flags.Arg(0), 10, 32)
+	if err != nil {
+		return "", err
+	}
+	if *toStdout {
+		return cp.runTransfer(fmt.Sprintf("download of file %d", resId), silentTransfer, func(ctx context.Context) (string, error) {
+			if *version != 0 {
+				return "", cp.resourceService.WriteFileVersion(ctx, int32(resId), *version, os.Stdout)
+			}
+			return "", cp.resourceService.WriteFile(ctx, int32(resId), os.Stdout)
+		})
+	}
+	ask := func(prompt string) (string, error) {
+		return cp.readString(prompt), nil
+	}
+	download, err := services.NewFileDownload(context.Background(), cp.resourceService, int32(resId), flags.Arg(1), *force, ask)
+	if errors.Is(err, services.ErrNotOverwritten) {
+		return "file is not overwritten", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	download.Version = *version
+	mode := foregroundTransfer
+	if *background {
+		mode = backgroundTransfer
+	}
+	return cp.runTransfer(fmt.Sprintf("download of file %d to %s", resId, download.Path), mode, func(ctx context.Context) (string, error) {
+		if err := download.Run(ctx); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("recieved file saved to: %v", download.Path), nil
+	})
+}
+
+// handleFileVersions prints previous versions of the file which can be got by 'gf [id] --version n'
+func (cp *commandParser) handleFileVersions(args []string) (string, error) {
 	if len(args) == 0 {
 		return "", fmt.Errorf("arg '[id]' is empty, type 'help' to display available commands format")
 	}
@@ -155,14 +505,79 @@ func (cp *commandParser) handleGetFile(args []string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	cp.exitHandler.AddFuncInProcessing("getting file")
-	defer cp.exitHandler.FuncFinished("getting file")
-	path, err := cp.resourceService.GetFile(context.Background(), int32(resId))
+	versions, err := cp.resourceService.FileVersions(context.Background(), int32(resId))
 	if err != nil {
 		return "", err
 	}
+	return versions.String(), nil
+}
 
-	return fmt.Sprintf("recieved file saved to: %v", path), nil
+// handleJobs prints running transfers and background transfers finished since they were reported
+func (cp *commandParser) handleJobs(_ []string) (string, error) {
+	lines := cp.transfers.list(false)
+	if len(lines) == 0 {
+		return "no transfers", nil
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+func (cp *commandParser) handleCancel(args []string) (string, error) {
+	if len(args) == 0 {
+		return "", fmt.Errorf("arg '[job]' is empty, type 'jobs' to list transfers")
+	}
+	id, err := strconv.Atoi(strings.Trim(args[0], "[]"))
+	if err != nil {
+		return "", err
+	}
+	if !cp.transfers.cancel(id) {
+		return "", fmt.Errorf("transfer %d is not running", id)
+	}
+	return fmt.Sprintf("transfer %d is canceled", id), nil
+}
+
+// runTransfer runs the file transfer which is canceled by 'cancel' command or on exit,
+// foreground transfer draws its progress and background one returns at once
+func (cp *commandParser) runTransfer(name string, mode transferMode, run func(ctx context.Context) (string, error)) (string, error) {
+	t, ctx := cp.transfers.start(name)
+	cp.exitHandler.AddFuncInProcessing(name)
+	if mode == backgroundTransfer {
+		go func() {
+			defer cp.exitHandler.FuncFinished(name)
+			result, err := run(ctx)
+			cp.transfers.finish(t, ctx, result, err)
+		}()
+		return fmt.Sprintf("[%d] %s is started, type 'jobs' to list transfers", t.id, name), nil
+	}
+	defer cp.exitHandler.FuncFinished(name)
+	defer cp.transfers.remove(t.id)
+	// Ctrl-C cancels the foreground transfer only, the shell keeps running
+	defer cp.exitHandler.OnInterrupt(t.cancel)()
+	stop, drawn := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(drawn)
+		if mode != foregroundTransfer {
+			return
+		}
+		ticker := time.NewTicker(progressInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				fmt.Fprintf(os.Stderr, "\r\033[K%s: %s", name, formatProgress(t.progress.Stats()))
+			case <-stop:
+				fmt.Fprint(os.Stderr, "\r\033[K")
+				return
+			}
+		}
+	}()
+	result, err := run(ctx)
+	close(stop)
+	<-drawn
+	if ctx.Err() != nil {
+		return "", fmt.Errorf("%s is canceled", name)
+	}
+	t.cancel()
+	return result, err
 }
 
 func (cp *commandParser) handleGet(args []string) (string, error) {
@@ -177,57 +592,237 @@ func (cp *commandParser) handleGet(args []string) (string, error) {
 	if err != nil {
 		return "", err
 	}
+	resDescription.Reveal = len(args) > 1 && args[1] == "--reveal"
+	if loginPassword, ok := resDescription.Resource.(*resources.LoginPassword); ok && loginPassword.TotpId != 0 {
+		totpDescription, err := cp.resourceService.Get(context.Background(), loginPassword.TotpId)
+		if err != nil {
+			return "", fmt.Errorf("failed to get linked totp: %w", err)
+		}
+		totp, ok := totpDescription.Resource.(*resources.Totp)
+		if !ok {
+			return "", fmt.Errorf("linked resource %d is not a totp", loginPassword.TotpId)
+		}
+		code, left, err := totp.Code(time.Now())
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s\ncode: %s (%ds left)", resDescription.Format(), code, left), nil
+	}
 
 	return resDescription.Format(), nil
 }
 
+// handleList prints resources by type, '--folder' shows subfolders and resources of the folder
 func (cp *commandParser) handleList(args []string) (string, error) {
-	resType := enum.Nan
-	if len(args) != 0 {
-		if rType, ok := model.ArgToType[args[0]]; ok {
-			resType = rType
+	flags := pflag.NewFlagSet("l", pflag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	folderArg := flags.String("folder", "", "folder path, '/' is the root folder")
+	tags := flags.StringSlice("tag", nil, "show resources with all the tags")
+	recursive := flags.BoolP("recursive", "r", false, "include resources of subfolders")
+	limit := flags.Int("limit", 0, "page size, 0 to list all resources")
+	cursor := flags.String("cursor", "", "cursor of the next page")
+	order := flags.String("sort", string(clmodel.OrderById), "order by id, created or updated")
+	descending := flags.Bool("desc", false, "descending order")
+	since := flags.String("since", "", "resources updated since the date, YYYY-MM-DD")
+	if err := flags.Parse(args); err != nil {
+		return "", err
+	}
+	query := clmodel.ListQuery{
+		Order:      clmodel.ListOrder(*order),
+		Descending: *descending,
+		Limit:      *limit,
+		Cursor:     *cursor,
+	}
+	for _, arg := range flags.Args() {
+		if rType, ok := model.ArgToType[arg]; ok && rType != enum.Nan {
+			query.Types = append(query.Types, rType)
 		}
 	}
+	if *since != "" {
+		updatedSince, err := time.ParseInLocation("2006-01-02", *since, time.Local)
+		if err != nil {
+			return "", fmt.Errorf("date '%s' is not valid, it should be YYYY-MM-DD", *since)
+		}
+		query.UpdatedSince = updatedSince
+	}
+	for _, tag := range *tags {
+		tag, err := resources.NormalizeTag(tag)
+		if err != nil {
+			return "", err
+		}
+		query.Tags = append(query.Tags, tag)
+	}
+	navigate := flags.Changed("folder")
+	folder := resources.NormalizeFolder(*folderArg)
+	// folder is filtered with subfolders on server, direct resources are picked here to count subfolders
+	query.Folder = folder
+	if navigate && !*recursive && (query.Limit != 0 || query.Cursor != "") {
+		return "", fmt.Errorf("'--limit' and '--cursor' can be used with '--folder' only if '-r' is set")
+	}
 
-	resDescriptions, err := cp.resourceService.GetDescriptions(context.Background(), resType)
+	page, err := cp.resourceService.ListDescriptions(context.Background(), query)
 	if err != nil {
 		return "", err
 	}
 	var writer strings.Builder
-	if len(resDescriptions) == 0 {
-		_, err := writer.WriteString("empty")
+	subfolders := make(map[string]int)
+	listed := 0
+	for _, resDescription := range page.Descriptions {
+		labels, err := resources.ParseLabels(resDescription.Labels)
 		if err != nil {
-			return "", err
+			return "", fmt.Errorf("failed to parse labels of resource %d: %w", resDescription.Id, err)
+		}
+		if navigate {
+			if subfolder, ok := labels.Subfolder(folder); ok {
+				subfolders[subfolder]++
+			}
+			if !labels.InFolder(folder, *recursive) {
+				continue
+			}
 		}
+		listed++
+		line := fmt.Sprintf("id: %d - type: '%s', descr: '%s'", resDescription.Id, model.TypeToArg[resDescription.Type], string(resDescription.Meta))
+		if labels.Folder != "" && (!navigate || *recursive) {
+			line += fmt.Sprintf(", folder: '%s'", labels.Folder)
+		}
+		if len(labels.Tags) != 0 {
+			line += fmt.Sprintf(", tags: %s", strings.Join(labels.Tags, ", "))
+		}
+		writer.WriteString(line + "\n")
 	}
-	for _, resDescription := range resDescriptions {
-		_, err := writer.WriteString(fmt.Sprintf("id: %d - type: '%s', descr: '%s'\n", resDescription.Id, model.TypeToArg[resDescription.Type], string(resDescription.Meta)))
-		if err != nil {
-			return "", err
+	if page.Next != "" {
+		writer.WriteString(fmt.Sprintf("next page: '--cursor %s'\n", page.Next))
+	}
+	if navigate && !*recursive {
+		names := make([]string, 0, len(subfolders))
+		for name := range subfolders {
+			names = append(names, name)
 		}
+		sort.Strings(names)
+		var folders strings.Builder
+		for _, name := range names {
+			folders.WriteString(fmt.Sprintf("%s/ (%d)\n", name, subfolders[name]))
+		}
+		return folders.String() + writer.String() + fmt.Sprintf("folder '/%s': %d subfolders, %d resources", folder, len(names), listed), nil
+	}
+	if listed == 0 {
+		return "empty", nil
 	}
 	return writer.String(), nil
 }
 
+// handleUsage prints resources consumption and quotas
+func (cp *commandParser) handleUsage(_ []string) (string, error) {
+	usage, err := cp.resourceService.Usage(context.Background())
+	if err != nil {
+		return "", err
+	}
+	return usage.String(), nil
+}
+
+// handleMove moves resources to the folder
+func (cp *commandParser) handleMove(args []string) (string, error) {
+	if len(args) < 2 {
+		return "", fmt.Errorf("args '[folder] [id...]' are empty, type 'help' to display available commands format")
+	}
+	ids, err := parseIds(args[1:])
+	if err != nil {
+		return "", err
+	}
+	return successResult, cp.labelService.Move(context.Background(), args[0], ids)
+}
+
+func (cp *commandParser) handleTag(args []string) (string, error) {
+	if len(args) < 2 {
+		return "", fmt.Errorf("args '[tag] [id...]' are empty, type 'help' to display available commands format")
+	}
+	ids, err := parseIds(args[1:])
+	if err != nil {
+		return "", err
+	}
+	return successResult, cp.labelService.Tag(context.Background(), args[0], ids)
+}
+
+func (cp *commandParser) handleUntag(args []string) (string, error) {
+	if len(args) < 2 {
+		return "", fmt.Errorf("args '[tag] [id...]' are empty, type 'help' to display available commands format")
+	}
+	ids, err := parseIds(args[1:])
+	if err != nil {
+		return "", err
+	}
+	return successResult, cp.labelService.Untag(context.Background(), args[0], ids)
+}
+
+func parseIds(args []string) ([]int32, error) {
+	ids := make([]int32, 0, len(args))
+	for _, arg := range args {
+		if arg == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(arg, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("id '%s' is not a number", arg)
+		}
+		ids = append(ids, int32(id))
+	}
+	return ids, nil
+}
+
+// handleFind prints resources matching the query ordered by score
+func (cp *commandParser) handleFind(args []string) (string, error) {
+	flags := pflag.NewFlagSet("find", pflag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	limit := flags.Int("limit", 20, "max number of results, 0 to print all")
+	if err := flags.Parse(args); err != nil {
+		return "", err
+	}
+	query := strings.Join(flags.Args(), " ")
+	if strings.TrimSpace(query) == "" {
+		return "", fmt.Errorf("arg '[query]' is empty, type 'help' to display available commands format")
+	}
+	results, err := cp.searchService.Find(context.Background(), query, *limit)
+	if err != nil {
+		return "", err
+	}
+	if len(results) == 0 {
+		return "nothing is found", nil
+	}
+	var builder strings.Builder
+	writer := tabwriter.NewWriter(&builder, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "ID\tTYPE\tDESCRIPTION\tMATCH")
+	for _, result := range results {
+		match := ""
+		if result.Match.Name != "" {
+			// multiline notes are cut to fit the table
+			value, _, _ := strings.Cut(result.Match.Value, "\n")
+			if runes := []rune(value); len(runes) > 40 {
+				value = string(runes[:40]) + "..."
+			}
+			match = fmt.Sprintf("%s: %s", result.Match.Name, value)
+		}
+		fmt.Fprintf(writer, "%d\t%s\t%s\t%s\n", result.Id, model.TypeToArg[result.Type], result.Description, match)
+	}
+	writer.Flush()
+	return strings.TrimSuffix(builder.String(), "\n"), nil
+}
+
 func (cp *commandParser) handleSave(args []string) (string, error) {
 	if len(args) == 0 {
 		return "", fmt.Errorf("arg '[type]' is empty, type 'help' to display available commands format")
 	}
-	var resource any
-	var meta string
-	resType := args[0]
-	switch resType {
-	case model.LoginPasswordArg:
-		resource, meta = cp.readLoginPassword()
-		return cp.saveTextResource(resource, meta, enum.LoginPassword)
-	case model.BankCardArg:
-		resource, meta = cp.readBankCard()
-		return cp.saveTextResource(resource, meta, enum.BankCard)
-	case model.FileArg:
-		return cp.saveFile()
-	default:
-		return "", fmt.Errorf("resource type argument '%s' is not supported, type 'help' to display available types", resType)
+	kind, ok := resources.KindByAlias(args[0])
+	if !ok {
+		return "", fmt.Errorf("resource type argument '%s' is not supported, type 'help' to display available types", args[0])
+	}
+	if kind.Type == enum.File {
+		return cp.saveFile(len(args) > 1 && args[1] == "--bg")
 	}
+	resource, meta, err := cp.readResource(kind, nil)
+	if err != nil {
+		return "", err
+	}
+	return cp.saveTextResource(resource, meta, kind.Type)
 }
 
 func (cp *commandParser) handleUpdate(args []string) (string, error) {
@@ -239,24 +834,26 @@ func (cp *commandParser) handleUpdate(args []string) (string, error) {
 		return "", err
 	}
 	id := int32(resId)
-	var resource any
 	resDescription, err := cp.resourceService.Get(context.Background(), id)
 	if err != nil {
 		return "", err
 	}
-	var meta string
-	switch resDescription.Resource.Type() {
-	case enum.LoginPassword:
-		resource, meta = cp.readLoginPassword()
-		return cp.updateTextResource(id, resource, meta, enum.LoginPassword)
-	case enum.BankCard:
-		resource, meta = cp.readBankCard()
-		return cp.updateTextResource(id, resource, meta, enum.BankCard)
-	case enum.File:
-		return "", fmt.Errorf("file update is not implemented, create a new")
-	default:
+	kind, ok := resources.KindOf(resDescription.Resource.Type())
+	if !ok {
 		return "", fmt.Errorf("resource type argument '%d' is not supported, type 'help' to display available types", resDescription.Resource.Type())
 	}
+	if kind.Type == enum.File {
+		return cp.updateFile(id, resDescription)
+	}
+	var existingFields []resources.CustomField
+	if holder, ok := resDescription.Resource.(resources.CustomFieldsHolder); ok {
+		existingFields = holder.Custom().Fields
+	}
+	resource, meta, err := cp.readResource(kind, resDescription.Resource)
+	if err != nil {
+		return "", err
+	}
+	return cp.updateTextResource(id, resource, existingFields, meta, kind.Type)
 }
 
 func (cp *commandParser) handleDelete(args []string) (string, error) {
@@ -275,6 +872,9 @@ func (cp *commandParser) handleDelete(args []string) (string, error) {
 }
 
 func (cp *commandParser) saveTextResource(resource any, meta string, resType enum.ResourceType) (string, error) {
+	if holder, ok := resource.(resources.CustomFieldsHolder); ok {
+		cp.readCustomFields(holder.Custom())
+	}
 	resourceJson, err := json.Marshal(resource)
 	if err != nil {
 		return "", err
@@ -286,7 +886,11 @@ func (cp *commandParser) saveTextResource(resource any, meta string, resType enu
 	return fmt.Sprintf("saved successfully, id: %v", id), nil
 }
 
-func (cp *commandParser) updateTextResource(resId int32, resource any, meta string, resType enum.ResourceType) (string, error) {
+func (cp *commandParser) updateTextResource(resId int32, resource any, existingFields []resources.CustomField, meta string, resType enum.ResourceType) (string, error) {
+	if holder, ok := resource.(resources.CustomFieldsHolder); ok {
+		holder.Custom().Fields = existingFields
+		cp.readCustomFields(holder.Custom())
+	}
 	resourceJson, err := json.Marshal(resource)
 	if err != nil {
 		return "", err
@@ -298,34 +902,261 @@ func (cp *commandParser) updateTextResource(resId int32, resource any, meta stri
 	return fmt.Sprintf("updated successfully, id: %v", resId), nil
 }
 
-func (cp *commandParser) saveFile() (string, error) {
+func (cp *commandParser) saveFile(background bool) (string, error) {
 	filePath := cp.readString("input file path")
 	meta := cp.readString("input description")
-	cp.exitHandler.AddFuncInProcessing("sending file")
-	defer cp.exitHandler.FuncFinished("sending file")
-	id, err := cp.resourceService.SaveFile(context.Background(), filePath, []byte(meta))
-	if err != nil {
+	var custom resources.CustomFields
+	cp.readCustomFields(&custom)
+	mode := foregroundTransfer
+	if background {
+		mode = backgroundTransfer
+	}
+	return cp.runTransfer(fmt.Sprintf("upload of %s", filePath), mode, func(ctx context.Context) (string, error) {
+		id, err := cp.resourceService.SaveFile(ctx, filePath, []byte(meta), custom.Fields)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%d", id), nil
+	})
+}
+
+// updateFile replaces the file content keeping its id, renames the file and edits its custom fields,
+// empty input keeps the current value, the replaced file takes the name of the new file unless the name is set
+func (cp *commandParser) updateFile(resId int32, resDescription *resources.Info) (string, error) {
+	filePath := cp.readString("input new file path, empty to keep the content")
+	name := cp.readString("input new file name, empty to keep the name")
+	meta := []byte(cp.readString("input new description, empty to keep the description"))
+	if len(meta) == 0 {
+		meta = resDescription.Meta
+	}
+	var custom resources.CustomFields
+	if file, ok := resDescription.Resource.(*resources.File); ok {
+		custom.Fields = append(custom.Fields, file.Fields...)
+	}
+	cp.readCustomFields(&custom)
+	if filePath != "" {
+		_, err := cp.runTransfer(fmt.Sprintf("upload of %s", filePath), foregroundTransfer, func(ctx context.Context) (string, error) {
+			return "", cp.resourceService.ReplaceFile(ctx, resId, filePath, meta)
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+	if err := cp.resourceService.RenameFile(context.Background(), resId, name, meta, custom.Fields); err != nil {
 		return "", err
 	}
-	return fmt.Sprintf("%d", id), nil
+	return fmt.Sprintf("updated successfully, id: %v", resId), nil
 }
 
-func (cp *commandParser) readLoginPassword() (*resources.LoginPassword, string) {
-	login := cp.readString("input login")
-	password := cp.readPassword()
-	description := cp.readString("input description")
+// readResource prompts fields of the kind schema, current resource values prefill multiline fields,
+// invalid fields are prompted again up to maxInputAttempts times
+func (cp *commandParser) readResource(kind resources.Kind, current resources.ResourceClIFormatter) (resources.ResourceClIFormatter, string, error) {
+	currentValues := make(map[string]any)
+	if current != nil {
+		currentJson, err := json.Marshal(current)
+		if err != nil {
+			return nil, "", err
+		}
+		if err := json.Unmarshal(currentJson, &currentValues); err != nil {
+			return nil, "", err
+		}
+	}
 
-	return resources.NewLoginPassword(login, password), description
+	values := make(map[string]any)
+	fields := kind.Schema
+	for attempt := 1; ; attempt++ {
+		validation := &resources.Validation{}
+		for _, field := range fields {
+			value, err := cp.readField(field, currentValues[field.Name])
+			if err != nil {
+				validation.Fail(field.Name, "%v", err)
+				continue
+			}
+			values[field.Name] = value
+		}
+
+		valuesJson, err := json.Marshal(values)
+		if err != nil {
+			return nil, "", err
+		}
+		resource := kind.New()
+		if err := json.Unmarshal(valuesJson, resource); err != nil {
+			return nil, "", err
+		}
+		if len(validation.Errors) == 0 {
+			validation = kind.Validate(resource)
+		}
+		for _, warning := range validation.Warnings {
+			fmt.Printf("warning: %s: %s\n", warning.Field, warning.Message)
+		}
+		err = validation.Err()
+		if err == nil {
+			description := cp.readString("input description")
+			return resource, description, nil
+		}
+		if attempt == maxInputAttempts {
+			return nil, "", err
+		}
+		fmt.Printf("error: %v, try again\n", err)
+		fields = invalidFields(kind.Schema, err.(*resources.ValidationError))
+	}
 }
 
-func (cp *commandParser) readBankCard() (*resources.BankCard, string) {
-	number := cp.readString("input number")
-	expireAt := cp.readString("input expireAt in format: MM/YY")
-	name := cp.readString("input name")
-	surname := cp.readString("input surname")
-	description := cp.readString("input description")
+func (cp *commandParser) readField(field resources.SchemaField, current any) (any, error) {
+	switch field.Input {
+	case resources.SecretInput:
+		if field.Prompt != "" {
+			fmt.Println(field.Prompt)
+		}
+		if field.Generate {
+			return cp.readOrGeneratePassword()
+		}
+		return cp.readPassword(), nil
+	case resources.MultilineInput:
+		currentText, _ := current.(string)
+		return cp.editText(currentText)
+	case resources.FileContentInput:
+		content, err := os.ReadFile(cp.readString(field.Prompt))
+		if err != nil {
+			return nil, err
+		}
+		return string(content), nil
+	case resources.NumberInput:
+		input := cp.readString(field.Prompt)
+		if input == "" {
+			return nil, nil
+		}
+		number, err := strconv.ParseInt(input, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("'%s' is not a number", input)
+		}
+		return number, nil
+	case resources.ListInput:
+		var list []string
+		for {
+			item := cp.readString(field.Prompt)
+			if item == "" {
+				return list, nil
+			}
+			list = append(list, item)
+		}
+	default:
+		return cp.readString(field.Prompt), nil
+	}
+}
+
+// invalidFields returns schema fields to prompt again, all of them if error is not bound to schema field
+func invalidFields(schema []resources.SchemaField, err *resources.ValidationError) []resources.SchemaField {
+	var fields []resources.SchemaField
+	for _, field := range schema {
+		for _, name := range err.Fields() {
+			if field.Name == name {
+				fields = append(fields, field)
+				break
+			}
+		}
+	}
+	if len(fields) == 0 {
+		return schema
+	}
+	return fields
+}
 
-	return resources.NewBankCard(number, expireAt, name, surname), description
+// readCustomFields adds, replaces or removes custom fields until empty name is input
+func (cp *commandParser) readCustomFields(custom *resources.CustomFields) {
+	if fields := custom.Format(false); fields != "" {
+		fmt.Println(fields)
+	}
+	for {
+		name := cp.readString("input custom field name to add or replace, '-name' to remove, empty to finish")
+		if name == "" {
+			return
+		}
+		if strings.HasPrefix(name, "-") {
+			if !custom.Remove(strings.TrimPrefix(name, "-")) {
+				fmt.Printf("custom field '%s' is not found\n", strings.TrimPrefix(name, "-"))
+			}
+			continue
+		}
+		fieldType := resources.CustomFieldType(cp.readString(fmt.Sprintf("input field type: %v", resources.CustomFieldTypes)))
+		var value string
+		if fieldType == resources.HiddenField {
+			value = cp.readPassword()
+		} else {
+			value = cp.readString("input value")
+		}
+		field, err := resources.NewCustomField(name, fieldType, value)
+		if err != nil {
+			fmt.Printf("error: %v\n", err)
+			continue
+		}
+		custom.Set(field)
+	}
+}
+
+// editText opens text in $EDITOR, or reads lines until a single '.' if it is not set.
+func (cp *commandParser) editText(text string) (string, error) {
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		fmt.Println("input text, finish with a line containing only '.'")
+		var lines []string
+		for cp.scanner.Scan() {
+			line := cp.scanner.Text()
+			if line == "." {
+				break
+			}
+			lines = append(lines, line)
+		}
+		return strings.Join(lines, "\n"), nil
+	}
+
+	file, err := os.CreateTemp("", "gophkeeper-note-*.txt")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(file.Name())
+	_, err = file.WriteString(text)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", err
+	}
+
+	args := strings.Fields(editor)
+	cmd := exec.Command(args[0], append(args[1:], file.Name())...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("failed to run editor '%s': %w", editor, err)
+	}
+	edited, err := os.ReadFile(file.Name())
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(string(edited), "\n"), nil
+}
+
+// readOrGeneratePassword generates password with default options or reads it and prints its strength
+func (cp *commandParser) readOrGeneratePassword() (string, error) {
+	answer := strings.ToLower(strings.TrimSpace(cp.readString("generate? [Y/n]")))
+	if answer == "" || answer == "y" || answer == "yes" {
+		password, entropy, err := cp.generator.Password(generator.DefaultPasswordOptions())
+		if err != nil {
+			return "", err
+		}
+		fmt.Printf("generated password: %s\nentropy: %.0f bits (%s)\n", password, entropy, generator.Strength(entropy))
+		return password, nil
+	}
+	password := cp.readPassword()
+	analysis := generator.Analyze(password)
+	fmt.Printf("score: %d/4, entropy: %.0f bits", analysis.Score, analysis.Entropy)
+	if len(analysis.Patterns) != 0 {
+		fmt.Printf(", found: %s", strings.Join(analysis.Patterns, ", "))
+	}
+	fmt.Println()
+	return password, nil
 }
 
 func (cp *commandParser) readString(label string) string {
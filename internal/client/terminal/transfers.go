@@ -0,0 +1,159 @@
+package terminal
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	clmodel "ydx-goadv-gophkeeper/internal/client/model"
+	pkgsrv "ydx-goadv-gophkeeper/pkg/services"
+)
+
+// progressInterval is how often the progress of the foreground transfer is redrawn
+const progressInterval = 200 * time.Millisecond
+
+type transferMode int
+
+const (
+	// foregroundTransfer blocks the terminal and draws its progress
+	foregroundTransfer transferMode = iota
+	// silentTransfer blocks the terminal without progress as it prints the file content
+	silentTransfer
+	// backgroundTransfer is listed by 'jobs' and reported before the next command once finished
+	backgroundTransfer
+)
+
+type transferState string
+
+const (
+	transferRunning  transferState = "running"
+	transferDone     transferState = "done"
+	transferFailed   transferState = "failed"
+	transferCanceled transferState = "canceled"
+)
+
+// transfer is the file upload or download started by the terminal
+type transfer struct {
+	id       int
+	name     string
+	progress *pkgsrv.Progress
+	cancel   context.CancelFunc
+	state    transferState
+	// result is the result or the error of the finished transfer
+	result string
+}
+
+func (t *transfer) String() string {
+	line := fmt.Sprintf("[%d] %s %s", t.id, t.name, t.state)
+	if t.state == transferRunning {
+		return line + ": " + formatProgress(t.progress.Stats())
+	}
+	if t.result != "" {
+		return line + ": " + t.result
+	}
+	return line
+}
+
+// transfers keeps running transfers and finished background transfers until they are reported
+type transfers struct {
+	mu     sync.Mutex
+	nextId int
+	items  map[int]*transfer
+}
+
+func newTransfers() *transfers {
+	return &transfers{items: make(map[int]*transfer)}
+}
+
+// start registers the transfer, its context is canceled by 'cancel' command or on exit
+func (ts *transfers) start(name string) (*transfer, context.Context) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	ts.nextId++
+	progress := pkgsrv.NewProgress()
+	ctx, cancel := context.WithCancel(pkgsrv.WithProgress(context.Background(), progress))
+	t := &transfer{id: ts.nextId, name: name, progress: progress, cancel: cancel, state: transferRunning}
+	ts.items[t.id] = t
+	return t, ctx
+}
+
+func (ts *transfers) finish(t *transfer, ctx context.Context, result string, err error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	switch {
+	case ctx.Err() != nil:
+		t.state = transferCanceled
+	case err != nil:
+		t.state, t.result = transferFailed, err.Error()
+	default:
+		t.state, t.result = transferDone, result
+	}
+	t.cancel()
+}
+
+func (ts *transfers) remove(id int) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	delete(ts.items, id)
+}
+
+func (ts *transfers) cancel(id int) bool {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	t, ok := ts.items[id]
+	if !ok || t.state != transferRunning {
+		return false
+	}
+	t.cancel()
+	return true
+}
+
+func (ts *transfers) cancelAll() {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	for _, t := range ts.items {
+		t.cancel()
+	}
+}
+
+// list returns transfers ordered by id, finished transfers are removed once listed,
+// only finished ones are listed if finishedOnly is set
+func (ts *transfers) list(finishedOnly bool) []string {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	ids := make([]int, 0, len(ts.items))
+	for id, t := range ts.items {
+		if !finishedOnly || t.state != transferRunning {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+	lines := make([]string, 0, len(ids))
+	for _, id := range ids {
+		t := ts.items[id]
+		lines = append(lines, t.String())
+		if t.state != transferRunning {
+			delete(ts.items, id)
+		}
+	}
+	return lines
+}
+
+// formatProgress returns transferred bytes, rate and estimated time left
+func formatProgress(stats pkgsrv.ProgressStats) string {
+	rate := clmodel.FormatBytes(int64(stats.Rate)) + "/s"
+	if stats.Total <= 0 {
+		return fmt.Sprintf("%s %s", clmodel.FormatBytes(stats.Done), rate)
+	}
+	percent := stats.Done * 100 / stats.Total
+	if percent > 100 {
+		percent = 100
+	}
+	line := fmt.Sprintf("%s / %s (%d%%) %s", clmodel.FormatBytes(stats.Done), clmodel.FormatBytes(stats.Total), percent, rate)
+	if stats.ETA > 0 {
+		line += fmt.Sprintf(" ETA %s", stats.ETA.Round(time.Second))
+	}
+	return line
+}
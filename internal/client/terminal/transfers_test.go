@@ -0,0 +1,70 @@
+package terminal
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"ydx-goadv-gophkeeper/pkg/mocks/shutdown"
+	pkgsrv "ydx-goadv-gophkeeper/pkg/services"
+)
+
+func TestCommandParser_RunTransfer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	exitHandler := shutdown.NewMockExitHandler(ctrl)
+	exitHandler.EXPECT().AddFuncInProcessing(gomock.Any()).AnyTimes()
+	exitHandler.EXPECT().FuncFinished(gomock.Any()).AnyTimes()
+	var interrupt func()
+	exitHandler.EXPECT().OnInterrupt(gomock.Any()).DoAndReturn(func(handler func()) func() {
+		interrupt = handler
+		return func() { interrupt = nil }
+	}).AnyTimes()
+	cp := &commandParser{exitHandler: exitHandler, transfers: newTransfers()}
+
+	started := make(chan struct{})
+	result, err := cp.runTransfer("download of file 1", backgroundTransfer, func(ctx context.Context) (string, error) {
+		pkgsrv.ProgressFrom(ctx).SetTotal(4)
+		pkgsrv.ProgressFrom(ctx).Add([]byte("da"))
+		close(started)
+		<-ctx.Done()
+		return "", ctx.Err()
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "[1] download of file 1 is started, type 'jobs' to list transfers", result)
+	<-started
+	jobs, _ := cp.handleJobs(nil)
+	assert.Contains(t, jobs, "[1] download of file 1 running: 2 B / 4 B (50%)")
+
+	_, err = cp.handleCancel([]string{"1"})
+	assert.NoError(t, err)
+	var finished []string
+	assert.Eventually(t, func() bool {
+		finished = cp.transfers.list(true)
+		return len(finished) == 1
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"[1] download of file 1 canceled"}, finished)
+	// finished transfers are listed once
+	jobs, _ = cp.handleJobs(nil)
+	assert.Equal(t, "no transfers", jobs)
+
+	_, err = cp.runTransfer("upload of cert.pem", foregroundTransfer, func(ctx context.Context) (string, error) {
+		return "", errors.New("failed")
+	})
+	assert.EqualError(t, err, "failed")
+	_, err = cp.handleCancel([]string{"2"})
+	assert.Error(t, err)
+	assert.Nil(t, interrupt)
+
+	// Ctrl-C cancels the foreground transfer
+	_, err = cp.runTransfer("upload of key.pem", foregroundTransfer, func(ctx context.Context) (string, error) {
+		interrupt()
+		<-ctx.Done()
+		return "", ctx.Err()
+	})
+	assert.EqualError(t, err, "upload of key.pem is canceled")
+	assert.Nil(t, interrupt)
+}
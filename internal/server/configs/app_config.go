@@ -14,17 +14,45 @@ import (
 )
 
 const (
-	defaultPort      = ":3200"
-	defaultSecretKey = ""
-	defaultDBConfig  = ""
+	defaultPort           = ":3200"
+	defaultSecretKey      = ""
+	defaultDBConfig       = ""
+	defaultBlobsDir       = "./cmd/server/blobs"
+	defaultBlobGCInterval = 60
 )
 
 type AppConfig struct {
 	log              *zap.SugaredLogger
-	ServerPort       string `env:"SERVER_PORT" json:"server_port"`
-	TokenKey         string `env:"TOKEN_KEY" json:"token_key"`
-	DBConnection     string `env:"DV_CONNECTION" json:"db_connection"`
-	DBMaxConnections int    `env:"DB_MAX_CONNECTIONS" json:"db_max_connections"`
+	ServerPort       string      `env:"SERVER_PORT" json:"server_port"`
+	TokenKey         string      `env:"TOKEN_KEY" json:"token_key"`
+	DBConnection     string      `env:"DV_CONNECTION" json:"db_connection"`
+	DBMaxConnections int         `env:"DB_MAX_CONNECTIONS" json:"db_max_connections"`
+	Quotas           QuotaConfig `json:"quotas"`
+	// BlobsDir keeps uploaded files by hash
+	BlobsDir string `json:"blobs_dir"`
+	// BlobGCMinutes is the interval of removing unreferenced blobs
+	BlobGCMinutes int `json:"blob_gc_minutes"`
+}
+
+// Limit is the max number of resources and their total size, zero is unlimited
+type Limit struct {
+	MaxItems int64 `json:"max_items"`
+	MaxBytes int64 `json:"max_bytes"`
+}
+
+// Organization quota is shared by its members
+type Organization struct {
+	Name    string   `json:"name"`
+	Members []string `json:"members"`
+	Limit
+}
+
+type QuotaConfig struct {
+	// User is the default quota of every user
+	User Limit `json:"user"`
+	// Users overrides the default quota by username
+	Users         map[string]Limit `json:"users"`
+	Organizations []Organization   `json:"organizations"`
 }
 
 func InitAppConfig(configPath string) (*AppConfig, error) {
@@ -59,6 +87,12 @@ func readConfig(configFilePath string) (*AppConfig, error) {
 		return nil, fmt.Errorf("failed to unmarshal config json '%s': %v", string(configBytes), err)
 	}
 	config.log = logger.NewLogger("app-config")
+	if config.BlobsDir == "" {
+		config.BlobsDir = defaultBlobsDir
+	}
+	if config.BlobGCMinutes <= 0 {
+		config.BlobGCMinutes = defaultBlobGCInterval
+	}
 	return &config, nil
 }
 
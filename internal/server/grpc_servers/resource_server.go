@@ -1,15 +1,20 @@
 package grpc_servers
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
 	"errors"
 	"fmt"
 	"io"
+	"os"
+	"time"
 
 	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"ydx-goadv-gophkeeper/internal/server/model"
 	"ydx-goadv-gophkeeper/internal/server/model/consts"
@@ -17,29 +22,57 @@ import (
 	"ydx-goadv-gophkeeper/internal/server/services"
 	"ydx-goadv-gophkeeper/pkg/logger"
 	"ydx-goadv-gophkeeper/pkg/model/enum"
+	"ydx-goadv-gophkeeper/pkg/model/registry"
 	"ydx-goadv-gophkeeper/pkg/pb"
 	intsrv "ydx-goadv-gophkeeper/pkg/services"
 	"ydx-goadv-gophkeeper/pkg/shutdown"
 )
 
+const (
+	// maxPageSize limits both requested pages and pages read from db at once
+	maxPageSize = 1000
+	// maxBatchSize limits operations applied in one transaction
+	maxBatchSize = 1000
+	// discardTimeout limits cleanup of canceled and failed uploads
+	discardTimeout = 10 * time.Second
+)
+
+var batchKinds = map[pb.BATCH_KIND]model.BatchKind{
+	pb.BATCH_KIND_CREATE: model.BatchCreate,
+	pb.BATCH_KIND_UPDATE: model.BatchUpdate,
+	pb.BATCH_KIND_DELETE: model.BatchDelete,
+}
+
+var descriptionsOrders = map[pb.ORDER]model.DescriptionsOrder{
+	pb.ORDER_BY_ID:      model.OrderById,
+	pb.ORDER_BY_CREATED: model.OrderByCreated,
+	pb.ORDER_BY_UPDATED: model.OrderByUpdated,
+}
+
 type ResourceServer struct {
 	log *zap.SugaredLogger
 	pb.UnimplementedResourcesServer
-	service     services.ResourceService
-	fileService intsrv.FileService
-	eh          shutdown.ExitHandler
+	service      services.ResourceService
+	quotaService services.QuotaService
+	blobService  services.BlobService
+	fileService  intsrv.FileService
+	eh           shutdown.ExitHandler
 }
 
 func NewResourcesServer(
 	service services.ResourceService,
+	quotaService services.QuotaService,
+	blobService services.BlobService,
 	fileService intsrv.FileService,
 	eh shutdown.ExitHandler,
 ) pb.ResourcesServer {
 	return &ResourceServer{
-		log:         logger.NewLogger("res-service"),
-		service:     service,
-		fileService: fileService,
-		eh:          eh,
+		log:          logger.NewLogger("res-service"),
+		service:      service,
+		quotaService: quotaService,
+		blobService:  blobService,
+		fileService:  fileService,
+		eh:           eh,
 	}
 }
 
@@ -49,10 +82,22 @@ func (s *ResourceServer) Save(ctx context.Context, resource *pb.Resource) (*pb.R
 		Data:   resource.Data,
 	}
 	res.Meta = resource.Meta
+	res.Labels = resource.Labels
+	resType, err := registry.FromWire(resource.Type)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	res.Type = resType
+	limits, err := s.quotaLimits(ctx, res.UserId)
+	if err != nil {
+		return nil, err
+	}
 
-	res.Type = enum.ResourceType(resource.Type)
 	s.log.Infof("Saving resource: %v", res.ResourceDescription)
-	err := s.service.Save(ctx, res)
+	err = s.service.Save(ctx, res, limits)
+	if errors.Is(err, errs.ErrQuotaExceeded) {
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	}
 	if err != nil {
 		s.log.Errorf("failed to save resource: %v", res.ResourceDescription)
 		return nil, status.Error(codes.Internal, err.Error())
@@ -68,10 +113,32 @@ func (s *ResourceServer) Update(ctx context.Context, resource *pb.Resource) (*em
 	}
 	res.Id = resource.Id
 	res.Meta = resource.Meta
-	res.Type = enum.ResourceType(resource.Type)
+	resType, err := registry.FromWire(resource.Type)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	res.Type = resType
 
 	s.log.Infof("Updating resource: %v", res.ResourceDescription)
-	err := s.service.Update(ctx, res)
+	if resType == enum.File {
+		// file content is replaced by SaveFile, only its description is updated here
+		err = s.service.UpdateFileDescription(ctx, res)
+		if errors.Is(err, errs.ErrResNotFound) {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+	} else {
+		var limits *model.QuotaLimits
+		if limits, err = s.quotaLimits(ctx, res.UserId); err != nil {
+			return nil, err
+		}
+		err = s.service.Update(ctx, res, limits)
+		if errors.Is(err, errs.ErrQuotaExceeded) {
+			return nil, status.Error(codes.ResourceExhausted, err.Error())
+		}
+		if errors.Is(err, errs.ErrResNotFound) {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+	}
 	if err != nil {
 		s.log.Errorf("failed to update resource: %v", res.ResourceDescription)
 		return nil, status.Error(codes.Internal, err.Error())
@@ -88,29 +155,90 @@ func (s *ResourceServer) Delete(ctx context.Context, resId *pb.ResourceId) (*emp
 	return &emptypb.Empty{}, nil
 }
 
+// GetDescriptions streams one page of descriptions if page size is set, otherwise all of them page by page
 func (s *ResourceServer) GetDescriptions(query *pb.Query, stream pb.Resources_GetDescriptionsServer) error {
-	t := enum.ResourceType(query.ResourceType)
 	userId := s.getUserIdFromCtx(stream.Context())
-	s.log.Infof("Getting list descriptions of resources for user: %d", userId)
-	resourceDescriptions, err := s.service.GetDescriptions(stream.Context(), userId, t)
+	descrQuery, err := toDescriptionsQuery(userId, query)
 	if err != nil {
-		s.log.Errorf("failed to collect list descriptions of resources for user: %d", userId)
-		return status.Error(codes.Internal, err.Error())
+		return status.Error(codes.InvalidArgument, err.Error())
 	}
-
-	for _, resDescription := range resourceDescriptions {
-		err := stream.Send(&pb.ResourceDescription{
-			Id:   resDescription.Id,
-			Type: pb.TYPE(resDescription.Type),
-			Meta: resDescription.Meta,
-		})
+	s.log.Infof("Getting list descriptions of resources for user: %d", userId)
+	left := int(query.PageSize)
+	for {
+		descrQuery.Limit = maxPageSize
+		if query.PageSize > 0 && left < maxPageSize {
+			descrQuery.Limit = left
+		}
+		resourceDescriptions, err := s.service.GetDescriptions(stream.Context(), descrQuery)
 		if err != nil {
-			s.log.Errorf("failed to send '%v' of  user %d: %v", resDescription, userId, err)
+			s.log.Errorf("failed to collect list descriptions of resources for user: %d", userId)
 			return status.Error(codes.Internal, err.Error())
 		}
+
+		for _, resDescription := range resourceDescriptions {
+			err := stream.Send(&pb.ResourceDescription{
+				Id:        resDescription.Id,
+				Type:      registry.ToWire(resDescription.Type),
+				Meta:      resDescription.Meta,
+				CreatedAt: timestamppb.New(resDescription.CreatedAt),
+				UpdatedAt: optionalTimestamp(resDescription.UpdatedAt),
+				Labels:    resDescription.Labels,
+				Cursor:    resDescription.Cursor(descrQuery.Order).Encode(),
+			})
+			if err != nil {
+				s.log.Errorf("failed to send '%v' of  user %d: %v", resDescription, userId, err)
+				return status.Error(codes.Internal, err.Error())
+			}
+		}
+		left -= len(resourceDescriptions)
+		if len(resourceDescriptions) < descrQuery.Limit || (query.PageSize > 0 && left <= 0) {
+			return nil
+		}
+		cursor := resourceDescriptions[len(resourceDescriptions)-1].Cursor(descrQuery.Order)
+		descrQuery.After = &cursor
 	}
+}
 
-	return nil
+func toDescriptionsQuery(userId int32, query *pb.Query) (*model.DescriptionsQuery, error) {
+	if query.PageSize < 0 || query.PageSize > maxPageSize {
+		return nil, fmt.Errorf("page size should be from 1 to %d or 0 for all resources", maxPageSize)
+	}
+	order, ok := descriptionsOrders[query.Order]
+	if !ok {
+		return nil, fmt.Errorf("unknown order %v", query.Order)
+	}
+	descrQuery := &model.DescriptionsQuery{
+		UserId:      userId,
+		Ids:         query.Ids,
+		LabelTokens: query.LabelTokens,
+		Order:       order,
+		Descending:  query.Descending,
+	}
+	types := query.Types
+	if len(types) == 0 && query.ResourceType != pb.TYPE_NAN {
+		types = []pb.TYPE{query.ResourceType}
+	}
+	for _, wireType := range types {
+		resType, err := registry.FromWire(wireType)
+		if err != nil {
+			return nil, err
+		}
+		descrQuery.Types = append(descrQuery.Types, resType)
+	}
+	if query.UpdatedSince != nil {
+		descrQuery.UpdatedSince = query.UpdatedSince.AsTime()
+	}
+	if query.Cursor != "" {
+		cursor, err := model.DecodeCursor(query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		if cursor.Order != order {
+			return nil, fmt.Errorf("cursor was issued for another order")
+		}
+		descrQuery.After = cursor
+	}
+	return descrQuery, nil
 }
 
 func (s *ResourceServer) Get(ctx context.Context, id *pb.ResourceId) (*pb.Resource, error) {
@@ -124,12 +252,81 @@ func (s *ResourceServer) Get(ctx context.Context, id *pb.ResourceId) (*pb.Resour
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 	return &pb.Resource{
-		Type: pb.TYPE(result.Type),
-		Data: result.Data,
-		Meta: result.Meta,
+		Type:      registry.ToWire(result.Type),
+		Data:      result.Data,
+		Meta:      result.Meta,
+		CreatedAt: timestamppb.New(result.CreatedAt),
+		UpdatedAt: optionalTimestamp(result.UpdatedAt),
+		Labels:    result.Labels,
 	}, nil
 }
 
+func (s *ResourceServer) UpdateLabels(ctx context.Context, update *pb.LabelsUpdate) (*emptypb.Empty, error) {
+	userId := s.getUserIdFromCtx(ctx)
+	s.log.Infof("Updating labels of %d resources for user: %d", len(update.Resources), userId)
+	labels := make([]model.ResourceLabels, 0, len(update.Resources))
+	for _, resLabels := range update.Resources {
+		labels = append(labels, model.ResourceLabels{Id: resLabels.Id, Labels: resLabels.Labels, Tokens: resLabels.Tokens})
+	}
+	if err := s.service.UpdateLabels(ctx, userId, labels); err != nil {
+		s.log.Errorf("failed to update labels for user %d: %v", userId, err)
+		if errors.Is(err, errs.ErrResNotFound) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *ResourceServer) Batch(ctx context.Context, request *pb.BatchRequest) (*pb.BatchResponse, error) {
+	userId := s.getUserIdFromCtx(ctx)
+	s.log.Infof("Applying batch of %d operations for user: %d", len(request.Operations), userId)
+	if len(request.Operations) > maxBatchSize {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("batch should have at most %d operations", maxBatchSize))
+	}
+	operations := make([]model.BatchOperation, 0, len(request.Operations))
+	for i, operation := range request.Operations {
+		kind, ok := batchKinds[operation.Kind]
+		if !ok || operation.Resource == nil {
+			return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("operation %d is not valid", i))
+		}
+		resType, err := registry.FromWire(operation.Resource.Type)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("operation %d: %v", i, err))
+		}
+		// file content is saved by the stream with its blob
+		if resType == enum.File {
+			return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("operation %d: files are not supported in batch", i))
+		}
+		res := &model.Resource{UserId: userId, Data: operation.Resource.Data}
+		res.Id = operation.Resource.Id
+		res.Type = resType
+		res.Meta = operation.Resource.Meta
+		res.Labels = operation.Resource.Labels
+		operations = append(operations, model.BatchOperation{Kind: kind, Resource: res})
+	}
+	// operations are checked against quota one by one in the batch transaction
+	limits, err := s.quotaLimits(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	results, err := s.service.Batch(ctx, userId, operations, request.Atomic, limits)
+	if err != nil {
+		s.log.Errorf("failed to apply batch for user %d: %v", userId, err)
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	response := &pb.BatchResponse{Results: make([]*pb.BatchResult, 0, len(results))}
+	for _, result := range results {
+		pbResult := &pb.BatchResult{Id: result.Id}
+		if result.Err != nil {
+			pbResult.Error = result.Err.Error()
+			pbResult.Aborted = errors.Is(result.Err, errs.ErrBatchAborted)
+		}
+		response.Results = append(response.Results, pbResult)
+	}
+	return response, nil
+}
+
 func (s *ResourceServer) SaveFile(stream pb.Resources_SaveFileServer) error {
 	userId := s.getUserIdFromCtx(stream.Context())
 	s.log.Infof("Saving file resource, user: %d", userId)
@@ -144,23 +341,82 @@ func (s *ResourceServer) SaveFile(stream pb.Resources_SaveFileServer) error {
 		s.log.Errorf("failed to save file resource for '%d' user: %v", userId, err)
 		return err
 	}
+	if err := intsrv.VerifyDigest(chunk.Data, chunk.Digest); err != nil {
+		s.log.Errorf("failed to save file resource for '%d' user: description %v", userId, err)
+		return status.Error(codes.DataLoss, err.Error())
+	}
+	fileDigest := chunk.FileDigest
+	if len(fileDigest) != sha256.Size {
+		return status.Error(codes.InvalidArgument, "file digest is required")
+	}
+	replace := chunk.Id != 0
+	var replacedSize int64
+	if replace {
+		replaced, err := s.checkFile(stream.Context(), chunk.Id, userId)
+		if err != nil {
+			return err
+		}
+		replacedSize = replaced.Size
+	}
+	limits, err := s.quotaLimits(stream.Context(), userId)
+	if err != nil {
+		return err
+	}
+	// quota left aborts the upload early, the stored file is checked against limits in the write transaction
+	itemsLeft, bytesLeft, err := s.quotaService.Left(stream.Context(), userId)
+	if err != nil {
+		s.log.Errorf("failed to get quota of '%d' user: %v", userId, err)
+		return status.Error(codes.Internal, err.Error())
+	}
+	// the replaced content is released, so only the growth of the file is limited
+	if bytesLeft < model.Unlimited-replacedSize {
+		bytesLeft += replacedSize
+	}
+	if itemsLeft < 1 && !replace {
+		return status.Error(codes.ResourceExhausted, fmt.Errorf("%w: no more resources can be saved", errs.ErrQuotaExceeded).Error())
+	}
 	chunks := make(chan []byte)
 
-	resId, err := s.service.SaveFileDescription(
-		stream.Context(),
-		userId,
-		chunk.Meta,
-		chunk.Data,
-	)
+	description := &model.Resource{UserId: userId, Data: chunk.Data}
+	description.Id = chunk.Id
+	description.Meta = chunk.Meta
+	resId := chunk.Id
+	discardFile := s.discardFile
+	if replace {
+		// replaced file keeps its content and description until the new content is stored
+		discardFile = func(_ int32, _ int32, path string, errCh chan error) {
+			s.discardUpload(path, errCh)
+		}
+	} else {
+		resId, err = s.service.SaveFileDescription(
+			stream.Context(),
+			userId,
+			chunk.Meta,
+			chunk.Data,
+			limits,
+		)
+		if errors.Is(err, errs.ErrQuotaExceeded) {
+			return status.Error(codes.ResourceExhausted, err.Error())
+		}
+		if err != nil {
+			s.log.Errorf("failed to save file '%s' description for '%d' user: %v", string(chunk.Meta), userId, err)
+			return err
+		}
+	}
+	path, err := s.blobService.UploadPath()
 	if err != nil {
-		s.log.Errorf("failed to save file '%s' description for '%d' user: %v", string(chunk.Meta), userId, err)
-		return err
+		s.log.Errorf("failed to create upload of file '%d': %v", resId, err)
+		discardFile(resId, userId, "", nil)
+		return status.Error(codes.Internal, err.Error())
 	}
-	errCh, err := s.fileService.SaveFile(fmt.Sprintf("./cmd/server/%d", resId), chunks)
+	errCh, err := s.fileService.SaveFile(path, chunks)
 	if err != nil {
 		s.log.Errorf("failed to save file '%d' for '%d' user: %v", resId, userId, err)
+		discardFile(resId, userId, path, nil)
 		return status.Error(codes.Internal, err.Error())
 	}
+	hash := sha256.New()
+	var size int64
 Loop:
 	for {
 		chunk, err = stream.Recv()
@@ -171,23 +427,142 @@ Loop:
 		}
 		if err != nil {
 			close(chunks)
+			discardFile(resId, userId, path, errCh)
+			if status.Code(err) == codes.Canceled || stream.Context().Err() != nil {
+				s.log.Infof("upload of file '%d' is canceled", resId)
+				return status.Error(codes.Canceled, "upload is canceled")
+			}
 			s.log.Errorf("failed to get stream chunk, resource: %d", resId)
 			return status.Error(codes.Internal, errs.StreamError{Err: err}.Error())
 		}
+		size += int64(len(chunk.Data))
+		if size > bytesLeft {
+			s.log.Warnf("file '%d' of '%d' user exceeds quota", resId, userId)
+			close(chunks)
+			discardFile(resId, userId, path, errCh)
+			return status.Error(codes.ResourceExhausted, fmt.Errorf("%w: file does not fit in %d bytes left", errs.ErrQuotaExceeded, bytesLeft).Error())
+		}
+		if err := intsrv.VerifyDigest(chunk.Data, chunk.Digest); err != nil {
+			s.log.Errorf("failed to verify stream chunk of '%d' resource: %v", resId, err)
+			close(chunks)
+			discardFile(resId, userId, path, errCh)
+			return status.Error(codes.DataLoss, err.Error())
+		}
+		hash.Write(chunk.Data)
 		select {
 		case chunks <- chunk.Data:
 		case err := <-errCh:
 			s.log.Errorf("failed to save stream chunk of '%d' resource: %v", resId, err)
 			close(chunks)
+			discardFile(resId, userId, path, errCh)
 			return status.Error(codes.Internal, err.Error())
 		}
 	}
+	// upload is complete once errCh is closed
+	if err, ok := <-errCh; ok {
+		s.log.Errorf("failed to save file '%d': %v", resId, err)
+		discardFile(resId, userId, path, errCh)
+		return status.Error(codes.Internal, err.Error())
+	}
+	if !bytes.Equal(hash.Sum(nil), fileDigest) {
+		s.log.Errorf("file '%d' does not match its digest", resId)
+		discardFile(resId, userId, path, nil)
+		return status.Error(codes.DataLoss, fmt.Errorf("file %w", errs.ErrDigestMismatch).Error())
+	}
 
+	if replace {
+		err = s.blobService.Replace(stream.Context(), description, path, fileDigest, size, limits)
+	} else {
+		err = s.blobService.Store(stream.Context(), userId, resId, path, fileDigest, size, limits)
+	}
+	if err != nil {
+		s.log.Errorf("failed to store blob of file '%d': %v", resId, err)
+		discardFile(resId, userId, path, nil)
+		if errors.Is(err, errs.ErrQuotaExceeded) {
+			return status.Error(codes.ResourceExhausted, err.Error())
+		}
+		return status.Error(codes.Internal, err.Error())
+	}
 	id := &pb.ResourceId{Id: resId}
 	s.log.Infof("File '%d' was saved successfully", resId)
 	return stream.SendAndClose(id)
 }
 
+// discardFile deletes file description and partially written upload,
+// the stream context is not used as it is done when the client cancels the upload
+func (s *ResourceServer) discardFile(resId int32, userId int32, path string, errCh chan error) {
+	s.discardUpload(path, errCh)
+	ctx, cancel := context.WithTimeout(context.Background(), discardTimeout)
+	defer cancel()
+	if err := s.service.Delete(ctx, resId, userId); err != nil {
+		s.log.Errorf("failed to delete file description '%d': %v", resId, err)
+	}
+}
+
+// discardUpload waits for the upload to be written and removes it
+func (s *ResourceServer) discardUpload(path string, errCh chan error) {
+	if errCh != nil {
+		for range errCh {
+		}
+	}
+	if path != "" {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			s.log.Errorf("failed to remove file '%s': %v", path, err)
+		}
+	}
+}
+
+// checkFile returns the file or NotFound status if there is no such file of the user
+func (s *ResourceServer) checkFile(ctx context.Context, resId int32, userId int32) (*model.Resource, error) {
+	resource, err := s.service.Get(ctx, resId, userId)
+	if errors.Is(err, errs.ErrResNotFound) || err == nil && resource.Type != enum.File {
+		return nil, status.Error(codes.NotFound, fmt.Sprintf("there is no file %d", resId))
+	}
+	if err != nil {
+		s.log.Errorf("failed to get '%d' file of '%d' user: %v", resId, userId, err)
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return resource, nil
+}
+
+func (s *ResourceServer) Usage(ctx context.Context, _ *emptypb.Empty) (*pb.UsageReport, error) {
+	userId := s.getUserIdFromCtx(ctx)
+	s.log.Infof("Getting usage of user: %d", userId)
+	usage, err := s.quotaService.Usage(ctx, userId)
+	if err != nil {
+		s.log.Errorf("failed to get usage of user %d: %v", userId, err)
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	report := &pb.UsageReport{
+		Types:             make([]*pb.TypeUsage, 0, len(usage.Types)),
+		User:              toPbQuotaUsage(usage.User),
+		Organization:      usage.Organization,
+		OrganizationUsage: toPbQuotaUsage(usage.OrganizationUsage),
+	}
+	for _, typeUsage := range usage.Types {
+		report.Types = append(report.Types, &pb.TypeUsage{
+			Type:  registry.ToWire(typeUsage.Type),
+			Items: typeUsage.Items,
+			Bytes: typeUsage.Bytes,
+		})
+	}
+	return report, nil
+}
+
+func toPbQuotaUsage(usage model.QuotaUsage) *pb.QuotaUsage {
+	return &pb.QuotaUsage{Items: usage.Items, Bytes: usage.Bytes, MaxItems: usage.MaxItems, MaxBytes: usage.MaxBytes}
+}
+
+// quotaLimits returns the quota limits of the user checked by the write transaction
+func (s *ResourceServer) quotaLimits(ctx context.Context, userId int32) (*model.QuotaLimits, error) {
+	limits, err := s.quotaService.Limits(ctx, userId)
+	if err != nil {
+		s.log.Errorf("failed to get quota of '%d' user: %v", userId, err)
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return limits, nil
+}
+
 func (s *ResourceServer) GetFile(resId *pb.ResourceId, stream pb.Resources_GetFileServer) error {
 	s.log.Infof("Sending file resource: %d", resId.GetId())
 	s.eh.AddFuncInProcessing(fmt.Sprintf("sending file: %d", resId.GetId()))
@@ -198,30 +573,91 @@ func (s *ResourceServer) GetFile(resId *pb.ResourceId, stream pb.Resources_GetFi
 		s.log.Errorf("failed to get '%d' file description for '%d' user: %v", resId.GetId(), userId, err)
 		return status.Error(codes.Internal, err.Error())
 	}
-	err = stream.Send(&pb.FileChunk{
-		Meta: resource.Meta,
-		Data: resource.Data,
-	})
+	return s.sendFile(resource, stream)
+}
+
+func (s *ResourceServer) GetFileVersions(ctx context.Context, resId *pb.ResourceId) (*pb.FileVersions, error) {
+	userId := s.getUserIdFromCtx(ctx)
+	s.log.Infof("Getting versions of file '%d' for user: %d", resId.GetId(), userId)
+	if _, err := s.checkFile(ctx, resId.GetId(), userId); err != nil {
+		return nil, err
+	}
+	versions, err := s.service.GetFileVersions(ctx, resId.GetId(), userId)
 	if err != nil {
-		s.log.Errorf("failed to send '%d' file description for '%d' user: %v", resId.GetId(), userId, err)
-		return status.Error(codes.Internal, errs.StreamError{Err: err}.Error())
+		s.log.Errorf("failed to get versions of file '%d' for '%d' user: %v", resId.GetId(), userId, err)
+		return nil, status.Error(codes.Internal, err.Error())
 	}
+	response := &pb.FileVersions{Versions: make([]*pb.FileVersion, 0, len(versions))}
+	for _, version := range versions {
+		response.Versions = append(response.Versions, &pb.FileVersion{
+			Version:    version.Version,
+			Data:       version.Data,
+			Size:       version.Size,
+			ReplacedAt: timestamppb.New(version.ReplacedAt),
+		})
+	}
+	return response, nil
+}
+
+func (s *ResourceServer) GetFileVersion(versionId *pb.FileVersionId, stream pb.Resources_GetFileVersionServer) error {
+	s.log.Infof("Sending version '%d' of file resource: %d", versionId.GetVersion(), versionId.GetId())
+	s.eh.AddFuncInProcessing(fmt.Sprintf("sending file: %d", versionId.GetId()))
+	defer s.eh.FuncFinished(fmt.Sprintf("sending file: %d", versionId.GetId()))
+	userId := s.getUserIdFromCtx(stream.Context())
+	version, err := s.service.GetFileVersion(stream.Context(), versionId.GetId(), userId, versionId.GetVersion())
+	if errors.Is(err, errs.ErrResNotFound) {
+		return status.Error(codes.NotFound, err.Error())
+	}
+	if err != nil {
+		s.log.Errorf("failed to get version '%d' of file '%d' for '%d' user: %v", versionId.GetVersion(), versionId.GetId(), userId, err)
+		return status.Error(codes.Internal, err.Error())
+	}
+	resource := &model.Resource{UserId: userId, Data: version.Data, BlobHash: version.BlobHash}
+	resource.Id = version.ResourceId
+	resource.Type = enum.File
+	return s.sendFile(resource, stream)
+}
+
+// fileChunkSender is the stream of the current file content or of its version
+type fileChunkSender interface {
+	Send(*pb.FileChunk) error
+}
+
+// sendFile sends the file description and then its content chunk by chunk
+func (s *ResourceServer) sendFile(resource *model.Resource, stream fileChunkSender) error {
+	userId := resource.UserId
 	errCh := make(chan error)
-	chunks, _, err := s.fileService.ReadFile(fmt.Sprintf("./cmd/server/%d", resource.Id), errCh)
+	chunks, stat, err := s.fileService.ReadFile(s.blobService.Path(resource), errCh)
 	if err != nil {
 		s.log.Errorf("failed to read file '%d': %v", resource.Id, err)
 		return status.Error(codes.Internal, err.Error())
 	}
+	err = stream.Send(&pb.FileChunk{
+		Meta:       resource.Meta,
+		Data:       resource.Data,
+		Digest:     intsrv.Digest(resource.Data),
+		FileDigest: resource.BlobHash,
+		Size:       stat.Size(),
+	})
+	if err != nil {
+		s.log.Errorf("failed to send '%d' file description for '%d' user: %v", resource.Id, userId, err)
+		// the reader may have read the whole file already, closed errCh stops it anyway
+		close(errCh)
+		return status.Error(codes.Internal, errs.StreamError{Err: err}.Error())
+	}
 
+	hash := sha256.New()
 Loop:
 	for {
 		chunk, ok := <-chunks
 		if !ok {
 			break Loop
 		}
+		hash.Write(chunk)
 		err := stream.Send(&pb.FileChunk{
-			Meta: nil,
-			Data: chunk,
+			Meta:   nil,
+			Data:   chunk,
+			Digest: intsrv.Digest(chunk),
 		})
 		if err != nil {
 			s.log.Errorf("failed to send '%d' file's chunk: %v", resource.Id, err)
@@ -229,9 +665,22 @@ Loop:
 			return status.Error(codes.Internal, errs.StreamError{Err: err}.Error())
 		}
 	}
+	// files uploaded before blobs have no digest
+	if resource.BlobHash != nil && !bytes.Equal(hash.Sum(nil), resource.BlobHash) {
+		s.log.Errorf("stored file '%d' does not match its digest", resource.Id)
+		return status.Error(codes.DataLoss, fmt.Errorf("stored file %w", errs.ErrDigestMismatch).Error())
+	}
 	return nil
 }
 
+// optionalTimestamp returns nil for the zero time, which is unknown time
+func optionalTimestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
 func (s *ResourceServer) getUserIdFromCtx(ctx context.Context) int32 {
 	return ctx.Value(consts.UserIDCtxKey).(int32)
 }
@@ -2,18 +2,28 @@ package grpc_servers
 
 import (
 	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"ydx-goadv-gophkeeper/internal/server/mocks/services"
 	"ydx-goadv-gophkeeper/internal/server/model"
 	"ydx-goadv-gophkeeper/internal/server/model/consts"
+	"ydx-goadv-gophkeeper/internal/server/model/errs"
 	intsrv "ydx-goadv-gophkeeper/pkg/mocks/services"
 	"ydx-goadv-gophkeeper/pkg/mocks/shutdown"
 	"ydx-goadv-gophkeeper/pkg/model/enum"
 	"ydx-goadv-gophkeeper/pkg/pb"
+	pkgsrv "ydx-goadv-gophkeeper/pkg/services"
 )
 
 // Another type of tests
@@ -44,7 +54,11 @@ func testResourceServerGet(t *testing.T) {
 	fileService := intsrv.NewMockFileService(ctrl)
 	exitHandler := shutdown.NewMockExitHandler(ctrl)
 
-	resourcesServer := NewResourcesServer(resourceService, fileService, exitHandler)
+	quotaService := services.NewMockQuotaService(ctrl)
+	limits := &model.QuotaLimits{}
+	quotaService.EXPECT().Limits(gomock.Any(), int32(1)).Return(limits, nil)
+
+	resourcesServer := NewResourcesServer(resourceService, quotaService, services.NewMockBlobService(ctrl), fileService, exitHandler)
 
 	resRequest := &pb.Resource{
 		Type: pb.TYPE_LOGIN_PASSWORD,
@@ -65,8 +79,8 @@ func testResourceServerGet(t *testing.T) {
 	resId := int32(2)
 	resourceService.
 		EXPECT().
-		Save(ctx, gomock.Eq(res)).
-		Do(func(ctx context.Context, r *model.Resource) {
+		Save(ctx, gomock.Eq(res), limits).
+		Do(func(ctx context.Context, r *model.Resource, _ *model.QuotaLimits) {
 			r.Id = resId
 		}).
 		Return(nil)
@@ -77,6 +91,382 @@ func testResourceServerGet(t *testing.T) {
 	assert.Equal(t, resId, resourceId.Id)
 }
 
+func TestResourceServer_UpdateLabels(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	resourceService := services.NewMockResourceService(ctrl)
+	resourcesServer := NewResourcesServer(resourceService, services.NewMockQuotaService(ctrl), services.NewMockBlobService(ctrl), intsrv.NewMockFileService(ctrl), shutdown.NewMockExitHandler(ctrl))
+
+	userId := int32(1)
+	ctx := context.WithValue(context.Background(), consts.UserIDCtxKey, userId)
+	update := &pb.LabelsUpdate{Resources: []*pb.ResourceLabels{{Id: 2, Labels: []byte("encrypted")}, {Id: 3}}}
+	resourceService.EXPECT().UpdateLabels(ctx, userId, []model.ResourceLabels{{Id: 2, Labels: []byte("encrypted")}, {Id: 3}}).Return(nil)
+	_, err := resourcesServer.UpdateLabels(ctx, update)
+	assert.NoError(t, err)
+
+	resourceService.EXPECT().UpdateLabels(ctx, userId, gomock.Any()).Return(errs.ErrResNotFound)
+	_, err = resourcesServer.UpdateLabels(ctx, update)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+type descriptionsStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent []*pb.ResourceDescription
+}
+
+func (s *descriptionsStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *descriptionsStream) Send(description *pb.ResourceDescription) error {
+	s.sent = append(s.sent, description)
+	return nil
+}
+
+func TestResourceServer_GetDescriptions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	resourceService := services.NewMockResourceService(ctrl)
+	resourcesServer := NewResourcesServer(resourceService, services.NewMockQuotaService(ctrl), services.NewMockBlobService(ctrl), intsrv.NewMockFileService(ctrl), shutdown.NewMockExitHandler(ctrl))
+
+	userId := int32(1)
+	stream := &descriptionsStream{ctx: context.WithValue(context.Background(), consts.UserIDCtxKey, userId)}
+	updatedAt := time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC)
+	after := model.Cursor{Order: model.OrderByUpdated, Time: updatedAt, Id: 5}
+	resourceService.EXPECT().GetDescriptions(gomock.Any(), &model.DescriptionsQuery{
+		UserId:     userId,
+		Types:      []enum.ResourceType{enum.LoginPassword, enum.BankCard},
+		Order:      model.OrderByUpdated,
+		Descending: true,
+		Limit:      2,
+		After:      &after,
+	}).Return([]*model.ResourceDescription{
+		{Id: 4, Type: enum.LoginPassword, UpdatedAt: updatedAt},
+		{Id: 7, Type: enum.BankCard, UpdatedAt: updatedAt.Add(-time.Hour)},
+	}, nil)
+	err := resourcesServer.GetDescriptions(&pb.Query{
+		Types:      []pb.TYPE{pb.TYPE_LOGIN_PASSWORD, pb.TYPE_BANK_CARD},
+		Order:      pb.ORDER_BY_UPDATED,
+		Descending: true,
+		PageSize:   2,
+		Cursor:     after.Encode(),
+	}, stream)
+	assert.NoError(t, err)
+	assert.Len(t, stream.sent, 2)
+	next, err := model.DecodeCursor(stream.sent[1].Cursor)
+	assert.NoError(t, err)
+	assert.Equal(t, &model.Cursor{Order: model.OrderByUpdated, Time: updatedAt.Add(-time.Hour), Id: 7}, next)
+
+	err = resourcesServer.GetDescriptions(&pb.Query{Order: pb.ORDER_BY_CREATED, Cursor: after.Encode()}, stream)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	err = resourcesServer.GetDescriptions(&pb.Query{Cursor: "not a cursor"}, stream)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestResourceServer_GetDescriptions_AllPages(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	resourceService := services.NewMockResourceService(ctrl)
+	resourcesServer := NewResourcesServer(resourceService, services.NewMockQuotaService(ctrl), services.NewMockBlobService(ctrl), intsrv.NewMockFileService(ctrl), shutdown.NewMockExitHandler(ctrl))
+
+	stream := &descriptionsStream{ctx: context.WithValue(context.Background(), consts.UserIDCtxKey, int32(1))}
+	fullPage := make([]*model.ResourceDescription, maxPageSize)
+	for i := range fullPage {
+		fullPage[i] = &model.ResourceDescription{Id: int32(i + 1), Type: enum.File}
+	}
+	gomock.InOrder(
+		resourceService.EXPECT().GetDescriptions(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, query *model.DescriptionsQuery) ([]*model.ResourceDescription, error) {
+				assert.Nil(t, query.After)
+				assert.Equal(t, []enum.ResourceType{enum.File}, query.Types)
+				return fullPage, nil
+			}),
+		resourceService.EXPECT().GetDescriptions(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, query *model.DescriptionsQuery) ([]*model.ResourceDescription, error) {
+				assert.Equal(t, int32(maxPageSize), query.After.Id)
+				return []*model.ResourceDescription{{Id: maxPageSize + 1, Type: enum.File}}, nil
+			}),
+	)
+	err := resourcesServer.GetDescriptions(&pb.Query{ResourceType: pb.TYPE_FILE}, stream)
+	assert.NoError(t, err)
+	assert.Len(t, stream.sent, maxPageSize+1)
+}
+
+func TestResourceServer_Batch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	resourceService := services.NewMockResourceService(ctrl)
+	quotaService := services.NewMockQuotaService(ctrl)
+	limits := &model.QuotaLimits{MaxItems: 1, MaxBytes: 4}
+	quotaService.EXPECT().Limits(gomock.Any(), int32(1)).Return(limits, nil)
+	resourcesServer := NewResourcesServer(resourceService, quotaService, services.NewMockBlobService(ctrl), intsrv.NewMockFileService(ctrl), shutdown.NewMockExitHandler(ctrl))
+
+	userId := int32(1)
+	ctx := context.WithValue(context.Background(), consts.UserIDCtxKey, userId)
+	request := &pb.BatchRequest{
+		Operations: []*pb.BatchOperation{
+			{Kind: pb.BATCH_KIND_CREATE, Resource: &pb.Resource{Type: pb.TYPE_SECURE_NOTE, Data: []byte("data"), Meta: []byte("note")}},
+			{Kind: pb.BATCH_KIND_DELETE, Resource: &pb.Resource{Id: 5}},
+		},
+		Atomic: true,
+	}
+	note := &model.Resource{UserId: userId, Data: []byte("data")}
+	note.Type = enum.SecureNote
+	note.Meta = []byte("note")
+	deleted := &model.Resource{UserId: userId}
+	deleted.Id = 5
+	resourceService.EXPECT().Batch(ctx, userId, []model.BatchOperation{
+		{Kind: model.BatchCreate, Resource: note},
+		{Kind: model.BatchDelete, Resource: deleted},
+	}, true, limits).Return([]model.BatchResult{{Err: errs.ErrBatchAborted}, {Err: errs.ErrResNotFound}}, nil)
+	response, err := resourcesServer.Batch(ctx, request)
+	assert.NoError(t, err)
+	assert.True(t, response.Results[0].Aborted)
+	assert.False(t, response.Results[1].Aborted)
+	assert.Equal(t, errs.ErrResNotFound.Error(), response.Results[1].Error)
+
+	_, err = resourcesServer.Batch(ctx, &pb.BatchRequest{Operations: []*pb.BatchOperation{{Kind: pb.BATCH_KIND_UPDATE}}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = resourcesServer.Batch(ctx, &pb.BatchRequest{Operations: []*pb.BatchOperation{
+		{Kind: pb.BATCH_KIND_CREATE, Resource: &pb.Resource{Type: pb.TYPE_FILE, Data: []byte("data")}},
+	}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestResourceServer_SaveQuota(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	resourceService := services.NewMockResourceService(ctrl)
+	quotaService := services.NewMockQuotaService(ctrl)
+	resourcesServer := NewResourcesServer(resourceService, quotaService, services.NewMockBlobService(ctrl), intsrv.NewMockFileService(ctrl), shutdown.NewMockExitHandler(ctrl))
+
+	ctx := context.WithValue(context.Background(), consts.UserIDCtxKey, int32(1))
+	resource := &pb.Resource{Type: pb.TYPE_SECURE_NOTE, Data: []byte("data")}
+	limits := &model.QuotaLimits{MaxBytes: 3}
+	quotaService.EXPECT().Limits(ctx, int32(1)).Return(limits, nil)
+	// quota is checked by the repository in the write transaction
+	resourceService.EXPECT().Save(ctx, gomock.Any(), limits).Return(errs.ErrQuotaExceeded)
+	_, err := resourcesServer.Save(ctx, resource)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	quotaService.EXPECT().Limits(ctx, int32(1)).Return(nil, errors.New("no user"))
+	_, err = resourcesServer.Save(ctx, resource)
+	assert.Equal(t, codes.Internal, status.Code(err))
+}
+
+func TestResourceServer_UpdateQuota(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	resourceService := services.NewMockResourceService(ctrl)
+	quotaService := services.NewMockQuotaService(ctrl)
+	resourcesServer := NewResourcesServer(resourceService, quotaService, services.NewMockBlobService(ctrl), intsrv.NewMockFileService(ctrl), shutdown.NewMockExitHandler(ctrl))
+
+	ctx := context.WithValue(context.Background(), consts.UserIDCtxKey, int32(1))
+	resource := &pb.Resource{Id: 2, Type: pb.TYPE_SECURE_NOTE, Data: []byte("longer data")}
+	limits := &model.QuotaLimits{MaxBytes: 3}
+	quotaService.EXPECT().Limits(ctx, int32(1)).Return(limits, nil).Times(4)
+	// size growth is checked with the update
+	resourceService.EXPECT().Update(ctx, gomock.Any(), limits).Return(errs.ErrQuotaExceeded)
+	_, err := resourcesServer.Update(ctx, resource)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	// resource of another user or type is not updated
+	resourceService.EXPECT().Update(ctx, gomock.Any(), limits).Return(errs.ErrResNotFound)
+	_, err = resourcesServer.Update(ctx, resource)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	resourceService.EXPECT().Batch(ctx, int32(1), gomock.Len(1), true, limits).
+		Return([]model.BatchResult{{Err: errs.ErrQuotaExceeded}}, nil)
+	response, err := resourcesServer.Batch(ctx, &pb.BatchRequest{
+		Operations: []*pb.BatchOperation{{Kind: pb.BATCH_KIND_UPDATE, Resource: resource}},
+		Atomic:     true,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, errs.ErrQuotaExceeded.Error(), response.Results[0].Error)
+
+	resourceService.EXPECT().Batch(ctx, int32(1), gomock.Len(1), false, limits).Return([]model.BatchResult{{Id: 2}}, nil)
+	_, err = resourcesServer.Batch(ctx, &pb.BatchRequest{Operations: []*pb.BatchOperation{{Kind: pb.BATCH_KIND_DELETE, Resource: &pb.Resource{Id: 2}}}})
+	assert.NoError(t, err)
+}
+
+type fileChunksStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	chunks []*pb.FileChunk
+	id     *pb.ResourceId
+	// err is returned after chunks, io.EOF if empty
+	err  error
+	sent []*pb.FileChunk
+}
+
+func (s *fileChunksStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *fileChunksStream) Recv() (*pb.FileChunk, error) {
+	if len(s.chunks) == 0 && s.err != nil {
+		return nil, s.err
+	}
+	if len(s.chunks) == 0 {
+		return nil, io.EOF
+	}
+	chunk := s.chunks[0]
+	s.chunks = s.chunks[1:]
+	return chunk, nil
+}
+
+func (s *fileChunksStream) SendAndClose(id *pb.ResourceId) error {
+	s.id = id
+	return nil
+}
+
+func (s *fileChunksStream) Send(chunk *pb.FileChunk) error {
+	s.sent = append(s.sent, chunk)
+	return nil
+}
+
+func TestResourceServer_SaveFileDigest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	resourceService := services.NewMockResourceService(ctrl)
+	quotaService := services.NewMockQuotaService(ctrl)
+	blobService := services.NewMockBlobService(ctrl)
+	exitHandler := shutdown.NewMockExitHandler(ctrl)
+	exitHandler.EXPECT().AddFuncInProcessing(gomock.Any()).AnyTimes()
+	exitHandler.EXPECT().FuncFinished(gomock.Any()).AnyTimes()
+	resourcesServer := NewResourcesServer(resourceService, quotaService, blobService, pkgsrv.NewFileService(), exitHandler)
+
+	ctx := context.WithValue(context.Background(), consts.UserIDCtxKey, int32(1))
+	description, data := []byte(`{"Name":"cert.pem"}`), []byte("ciphertext")
+	fileDigest := pkgsrv.Digest(data)
+	chunks := func(dataDigest []byte) []*pb.FileChunk {
+		return []*pb.FileChunk{
+			{Data: description, Digest: pkgsrv.Digest(description), FileDigest: fileDigest},
+			{Data: data, Digest: dataDigest},
+		}
+	}
+	limits := &model.QuotaLimits{}
+	quotaService.EXPECT().Limits(gomock.Any(), int32(1)).Return(limits, nil).Times(2)
+	quotaService.EXPECT().Left(gomock.Any(), int32(1)).Return(model.Unlimited, model.Unlimited, nil).Times(2)
+	resourceService.EXPECT().SaveFileDescription(gomock.Any(), int32(1), gomock.Any(), description, limits).Return(int32(2), nil).Times(2)
+	uploadPath := func() (string, error) {
+		return filepath.Join(t.TempDir(), "upload"), nil
+	}
+	blobService.EXPECT().UploadPath().DoAndReturn(uploadPath).Times(2)
+
+	// corrupted chunk is not stored
+	resourceService.EXPECT().Delete(gomock.Any(), int32(2), int32(1)).Return(nil)
+	err := resourcesServer.SaveFile(&fileChunksStream{ctx: ctx, chunks: chunks(pkgsrv.Digest([]byte("corrupted")))})
+	assert.Equal(t, codes.DataLoss, status.Code(err))
+
+	blobService.EXPECT().Store(gomock.Any(), int32(1), int32(2), gomock.Any(), fileDigest, int64(len(data)), limits).Return(nil)
+	stream := &fileChunksStream{ctx: ctx, chunks: chunks(pkgsrv.Digest(data))}
+	assert.NoError(t, resourcesServer.SaveFile(stream))
+	assert.Equal(t, int32(2), stream.id.Id)
+
+	// file digest is required
+	err = resourcesServer.SaveFile(&fileChunksStream{ctx: ctx, chunks: []*pb.FileChunk{{Data: description, Digest: pkgsrv.Digest(description)}}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestResourceServer_SaveFileCanceled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	resourceService := services.NewMockResourceService(ctrl)
+	quotaService := services.NewMockQuotaService(ctrl)
+	blobService := services.NewMockBlobService(ctrl)
+	exitHandler := shutdown.NewMockExitHandler(ctrl)
+	exitHandler.EXPECT().AddFuncInProcessing(gomock.Any()).AnyTimes()
+	exitHandler.EXPECT().FuncFinished(gomock.Any()).AnyTimes()
+	resourcesServer := NewResourcesServer(resourceService, quotaService, blobService, pkgsrv.NewFileService(), exitHandler)
+
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), consts.UserIDCtxKey, int32(1)))
+	description, data := []byte(`{"Name":"cert.pem"}`), []byte("ciphertext")
+	quotaService.EXPECT().Limits(gomock.Any(), int32(1)).Return(&model.QuotaLimits{}, nil)
+	quotaService.EXPECT().Left(gomock.Any(), int32(1)).Return(model.Unlimited, model.Unlimited, nil)
+	resourceService.EXPECT().SaveFileDescription(gomock.Any(), int32(1), gomock.Any(), description, gomock.Any()).Return(int32(2), nil)
+	uploadPath := filepath.Join(t.TempDir(), "upload")
+	blobService.EXPECT().UploadPath().Return(uploadPath, nil)
+	// description is deleted although the stream context is done
+	resourceService.EXPECT().Delete(gomock.Any(), int32(2), int32(1)).DoAndReturn(func(ctx context.Context, _ int32, _ int32) error {
+		assert.NoError(t, ctx.Err())
+		return nil
+	})
+	cancel()
+	err := resourcesServer.SaveFile(&fileChunksStream{
+		ctx: ctx,
+		chunks: []*pb.FileChunk{
+			{Data: description, Digest: pkgsrv.Digest(description), FileDigest: pkgsrv.Digest(data)},
+			{Data: data, Digest: pkgsrv.Digest(data)},
+		},
+		err: status.Error(codes.Canceled, context.Canceled.Error()),
+	})
+	assert.Equal(t, codes.Canceled, status.Code(err))
+	_, err = os.Stat(uploadPath)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestResourceServer_ReplaceFile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	resourceService := services.NewMockResourceService(ctrl)
+	quotaService := services.NewMockQuotaService(ctrl)
+	blobService := services.NewMockBlobService(ctrl)
+	exitHandler := shutdown.NewMockExitHandler(ctrl)
+	exitHandler.EXPECT().AddFuncInProcessing(gomock.Any()).AnyTimes()
+	exitHandler.EXPECT().FuncFinished(gomock.Any()).AnyTimes()
+	resourcesServer := NewResourcesServer(resourceService, quotaService, blobService, pkgsrv.NewFileService(), exitHandler)
+
+	ctx := context.WithValue(context.Background(), consts.UserIDCtxKey, int32(1))
+	description, data := []byte(`{"Name":"cert.pem"}`), []byte("ciphertext")
+	chunks := func(id int32) []*pb.FileChunk {
+		return []*pb.FileChunk{
+			{Id: id, Meta: []byte("cert"), Data: description, Digest: pkgsrv.Digest(description), FileDigest: pkgsrv.Digest(data)},
+			{Data: data, Digest: pkgsrv.Digest(data)},
+		}
+	}
+	file := &model.Resource{UserId: 1, Size: int64(len(data)), ResourceDescription: model.ResourceDescription{Id: 5, Type: enum.File}}
+	resourceService.EXPECT().Get(gomock.Any(), int32(5), int32(1)).Return(file, nil)
+	// replaced file is not a new item and its size is released
+	limits := &model.QuotaLimits{MaxItems: 1, MaxBytes: int64(len(data))}
+	quotaService.EXPECT().Limits(gomock.Any(), int32(1)).Return(limits, nil)
+	quotaService.EXPECT().Left(gomock.Any(), int32(1)).Return(int64(0), int64(0), nil)
+	blobService.EXPECT().UploadPath().Return(filepath.Join(t.TempDir(), "upload"), nil)
+	replaced := &model.Resource{UserId: 1, Data: description, ResourceDescription: model.ResourceDescription{Id: 5, Meta: []byte("cert")}}
+	blobService.EXPECT().Replace(gomock.Any(), replaced, gomock.Any(), pkgsrv.Digest(data), int64(len(data)), limits).Return(nil)
+	stream := &fileChunksStream{ctx: ctx, chunks: chunks(5)}
+	assert.NoError(t, resourcesServer.SaveFile(stream))
+	assert.Equal(t, int32(5), stream.id.Id)
+
+	resourceService.EXPECT().Get(gomock.Any(), int32(6), int32(1)).Return(nil, errs.ErrResNotFound)
+	err := resourcesServer.SaveFile(&fileChunksStream{ctx: ctx, chunks: chunks(6)})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
 func testAnythingElse(t *testing.T) {
 	//etc
 }
+
+func TestResourceServer_GetFileVersion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	resourceService := services.NewMockResourceService(ctrl)
+	blobService := services.NewMockBlobService(ctrl)
+	exitHandler := shutdown.NewMockExitHandler(ctrl)
+	exitHandler.EXPECT().AddFuncInProcessing(gomock.Any()).AnyTimes()
+	exitHandler.EXPECT().FuncFinished(gomock.Any()).AnyTimes()
+	resourcesServer := NewResourcesServer(resourceService, services.NewMockQuotaService(ctrl), blobService, pkgsrv.NewFileService(), exitHandler)
+
+	ctx := context.WithValue(context.Background(), consts.UserIDCtxKey, int32(1))
+	description, data := []byte(`{"Name":"cert.pem"}`), []byte("old ciphertext")
+	blobPath := filepath.Join(t.TempDir(), "blob")
+	assert.NoError(t, os.WriteFile(blobPath, data, 0600))
+	version := &model.FileVersion{Version: 3, ResourceId: 5, UserId: 1, Data: description, BlobHash: pkgsrv.Digest(data), Size: int64(len(data))}
+	resourceService.EXPECT().GetFileVersion(gomock.Any(), int32(5), int32(1), int32(3)).Return(version, nil)
+	blobService.EXPECT().Path(gomock.Any()).DoAndReturn(func(resource *model.Resource) string {
+		assert.Equal(t, version.BlobHash, resource.BlobHash)
+		return blobPath
+	})
+	stream := &fileChunksStream{ctx: ctx}
+	assert.NoError(t, resourcesServer.GetFileVersion(&pb.FileVersionId{Id: 5, Version: 3}, stream))
+	assert.Len(t, stream.sent, 2)
+	assert.Equal(t, description, stream.sent[0].Data)
+	assert.Equal(t, version.BlobHash, stream.sent[0].FileDigest)
+	assert.Equal(t, data, stream.sent[1].Data)
+
+	resourceService.EXPECT().GetFileVersion(gomock.Any(), int32(5), int32(1), int32(4)).Return(nil, errs.ErrResNotFound)
+	err := resourcesServer.GetFileVersion(&pb.FileVersionId{Id: 5, Version: 4}, &fileChunksStream{ctx: ctx})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
@@ -8,9 +8,10 @@ import (
 	context "context"
 	reflect "reflect"
 	model "ydx-goadv-gophkeeper/internal/server/model"
-	enum "ydx-goadv-gophkeeper/pkg/model/enum"
 
 	gomock "github.com/golang/mock/gomock"
+	pgconn "github.com/jackc/pgconn"
+	pgx "github.com/jackc/pgx/v4"
 )
 
 // MockResourceRepository is a mock of ResourceRepository interface.
@@ -36,6 +37,35 @@ func (m *MockResourceRepository) EXPECT() *MockResourceRepositoryMockRecorder {
 	return m.recorder
 }
 
+// AttachBlob mocks base method.
+func (m *MockResourceRepository) AttachBlob(ctx context.Context, resId, userId int32, hash []byte, size int64, limits *model.QuotaLimits) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AttachBlob", ctx, resId, userId, hash, size, limits)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AttachBlob indicates an expected call of AttachBlob.
+func (mr *MockResourceRepositoryMockRecorder) AttachBlob(ctx, resId, userId, hash, size, limits interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttachBlob", reflect.TypeOf((*MockResourceRepository)(nil).AttachBlob), ctx, resId, userId, hash, size, limits)
+}
+
+// Batch mocks base method.
+func (m *MockResourceRepository) Batch(ctx context.Context, userId int32, operations []model.BatchOperation, atomic bool, limits *model.QuotaLimits) ([]model.BatchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Batch", ctx, userId, operations, atomic, limits)
+	ret0, _ := ret[0].([]model.BatchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Batch indicates an expected call of Batch.
+func (mr *MockResourceRepositoryMockRecorder) Batch(ctx, userId, operations, atomic, limits interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Batch", reflect.TypeOf((*MockResourceRepository)(nil).Batch), ctx, userId, operations, atomic, limits)
+}
+
 // Delete mocks base method.
 func (m *MockResourceRepository) Delete(ctx context.Context, resId, userId int32) error {
 	m.ctrl.T.Helper()
@@ -50,6 +80,21 @@ func (mr *MockResourceRepositoryMockRecorder) Delete(ctx, resId, userId interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockResourceRepository)(nil).Delete), ctx, resId, userId)
 }
 
+// DeleteUnreferencedBlobs mocks base method.
+func (m *MockResourceRepository) DeleteUnreferencedBlobs(ctx context.Context) ([]model.Blob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUnreferencedBlobs", ctx)
+	ret0, _ := ret[0].([]model.Blob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteUnreferencedBlobs indicates an expected call of DeleteUnreferencedBlobs.
+func (mr *MockResourceRepositoryMockRecorder) DeleteUnreferencedBlobs(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUnreferencedBlobs", reflect.TypeOf((*MockResourceRepository)(nil).DeleteUnreferencedBlobs), ctx)
+}
+
 // Get mocks base method.
 func (m *MockResourceRepository) Get(ctx context.Context, resId, userId int32) (*model.Resource, error) {
 	m.ctrl.T.Helper()
@@ -65,45 +110,194 @@ func (mr *MockResourceRepositoryMockRecorder) Get(ctx, resId, userId interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockResourceRepository)(nil).Get), ctx, resId, userId)
 }
 
-// GetResDescriptionsByType mocks base method.
-func (m *MockResourceRepository) GetResDescriptionsByType(ctx context.Context, userId int32, resType enum.ResourceType) ([]*model.ResourceDescription, error) {
+// GetFileVersion mocks base method.
+func (m *MockResourceRepository) GetFileVersion(ctx context.Context, resId, userId, version int32) (*model.FileVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFileVersion", ctx, resId, userId, version)
+	ret0, _ := ret[0].(*model.FileVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFileVersion indicates an expected call of GetFileVersion.
+func (mr *MockResourceRepositoryMockRecorder) GetFileVersion(ctx, resId, userId, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFileVersion", reflect.TypeOf((*MockResourceRepository)(nil).GetFileVersion), ctx, resId, userId, version)
+}
+
+// GetFileVersions mocks base method.
+func (m *MockResourceRepository) GetFileVersions(ctx context.Context, resId, userId int32) ([]*model.FileVersion, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetResDescriptionsByType", ctx, userId, resType)
+	ret := m.ctrl.Call(m, "GetFileVersions", ctx, resId, userId)
+	ret0, _ := ret[0].([]*model.FileVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFileVersions indicates an expected call of GetFileVersions.
+func (mr *MockResourceRepositoryMockRecorder) GetFileVersions(ctx, resId, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFileVersions", reflect.TypeOf((*MockResourceRepository)(nil).GetFileVersions), ctx, resId, userId)
+}
+
+// GetResDescriptions mocks base method.
+func (m *MockResourceRepository) GetResDescriptions(ctx context.Context, query *model.DescriptionsQuery) ([]*model.ResourceDescription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetResDescriptions", ctx, query)
 	ret0, _ := ret[0].([]*model.ResourceDescription)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetResDescriptionsByType indicates an expected call of GetResDescriptionsByType.
-func (mr *MockResourceRepositoryMockRecorder) GetResDescriptionsByType(ctx, userId, resType interface{}) *gomock.Call {
+// GetResDescriptions indicates an expected call of GetResDescriptions.
+func (mr *MockResourceRepositoryMockRecorder) GetResDescriptions(ctx, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetResDescriptions", reflect.TypeOf((*MockResourceRepository)(nil).GetResDescriptions), ctx, query)
+}
+
+// ReplaceBlob mocks base method.
+func (m *MockResourceRepository) ReplaceBlob(ctx context.Context, resource *model.Resource, hash []byte, size int64, limits *model.QuotaLimits) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceBlob", ctx, resource, hash, size, limits)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceBlob indicates an expected call of ReplaceBlob.
+func (mr *MockResourceRepositoryMockRecorder) ReplaceBlob(ctx, resource, hash, size, limits interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetResDescriptionsByType", reflect.TypeOf((*MockResourceRepository)(nil).GetResDescriptionsByType), ctx, userId, resType)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceBlob", reflect.TypeOf((*MockResourceRepository)(nil).ReplaceBlob), ctx, resource, hash, size, limits)
 }
 
 // Save mocks base method.
-func (m *MockResourceRepository) Save(ctx context.Context, resource *model.Resource) error {
+func (m *MockResourceRepository) Save(ctx context.Context, resource *model.Resource, limits *model.QuotaLimits) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Save", ctx, resource)
+	ret := m.ctrl.Call(m, "Save", ctx, resource, limits)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Save indicates an expected call of Save.
-func (mr *MockResourceRepositoryMockRecorder) Save(ctx, resource interface{}) *gomock.Call {
+func (mr *MockResourceRepositoryMockRecorder) Save(ctx, resource, limits interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockResourceRepository)(nil).Save), ctx, resource)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockResourceRepository)(nil).Save), ctx, resource, limits)
 }
 
 // Update mocks base method.
-func (m *MockResourceRepository) Update(ctx context.Context, resource *model.Resource) error {
+func (m *MockResourceRepository) Update(ctx context.Context, resource *model.Resource, limits *model.QuotaLimits) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", ctx, resource)
+	ret := m.ctrl.Call(m, "Update", ctx, resource, limits)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockResourceRepositoryMockRecorder) Update(ctx, resource interface{}) *gomock.Call {
+func (mr *MockResourceRepositoryMockRecorder) Update(ctx, resource, limits interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockResourceRepository)(nil).Update), ctx, resource, limits)
+}
+
+// UpdateDescription mocks base method.
+func (m *MockResourceRepository) UpdateDescription(ctx context.Context, resource *model.Resource) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDescription", ctx, resource)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateDescription indicates an expected call of UpdateDescription.
+func (mr *MockResourceRepositoryMockRecorder) UpdateDescription(ctx, resource interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDescription", reflect.TypeOf((*MockResourceRepository)(nil).UpdateDescription), ctx, resource)
+}
+
+// UpdateLabels mocks base method.
+func (m *MockResourceRepository) UpdateLabels(ctx context.Context, userId int32, labels []model.ResourceLabels) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLabels", ctx, userId, labels)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLabels indicates an expected call of UpdateLabels.
+func (mr *MockResourceRepositoryMockRecorder) UpdateLabels(ctx, userId, labels interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLabels", reflect.TypeOf((*MockResourceRepository)(nil).UpdateLabels), ctx, userId, labels)
+}
+
+// Usage mocks base method.
+func (m *MockResourceRepository) Usage(ctx context.Context, usernames []string) ([]model.TypeUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Usage", ctx, usernames)
+	ret0, _ := ret[0].([]model.TypeUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Usage indicates an expected call of Usage.
+func (mr *MockResourceRepositoryMockRecorder) Usage(ctx, usernames interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Usage", reflect.TypeOf((*MockResourceRepository)(nil).Usage), ctx, usernames)
+}
+
+// Mockquerier is a mock of querier interface.
+type Mockquerier struct {
+	ctrl     *gomock.Controller
+	recorder *MockquerierMockRecorder
+}
+
+// MockquerierMockRecorder is the mock recorder for Mockquerier.
+type MockquerierMockRecorder struct {
+	mock *Mockquerier
+}
+
+// NewMockquerier creates a new mock instance.
+func NewMockquerier(ctrl *gomock.Controller) *Mockquerier {
+	mock := &Mockquerier{ctrl: ctrl}
+	mock.recorder = &MockquerierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *Mockquerier) EXPECT() *MockquerierMockRecorder {
+	return m.recorder
+}
+
+// Exec mocks base method.
+func (m *Mockquerier) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, sql}
+	for _, a := range args {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Exec", varargs...)
+	ret0, _ := ret[0].(pgconn.CommandTag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Exec indicates an expected call of Exec.
+func (mr *MockquerierMockRecorder) Exec(ctx, sql interface{}, args ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, sql}, args...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exec", reflect.TypeOf((*Mockquerier)(nil).Exec), varargs...)
+}
+
+// QueryRow mocks base method.
+func (m *Mockquerier) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, sql}
+	for _, a := range args {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "QueryRow", varargs...)
+	ret0, _ := ret[0].(pgx.Row)
+	return ret0
+}
+
+// QueryRow indicates an expected call of QueryRow.
+func (mr *MockquerierMockRecorder) QueryRow(ctx, sql interface{}, args ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockResourceRepository)(nil).Update), ctx, resource)
+	varargs := append([]interface{}{ctx, sql}, args...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryRow", reflect.TypeOf((*Mockquerier)(nil).QueryRow), varargs...)
 }
@@ -64,3 +64,18 @@ func (mr *MockUserRepositoryMockRecorder) GetUser(ctx, username interface{}) *go
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockUserRepository)(nil).GetUser), ctx, username)
 }
+
+// GetUsername mocks base method.
+func (m *MockUserRepository) GetUsername(ctx context.Context, userId int32) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUsername", ctx, userId)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUsername indicates an expected call of GetUsername.
+func (mr *MockUserRepositoryMockRecorder) GetUsername(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsername", reflect.TypeOf((*MockUserRepository)(nil).GetUsername), ctx, userId)
+}
@@ -0,0 +1,121 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: blob_service.go
+
+// Package services is a generated GoMock package.
+package services
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+	model "ydx-goadv-gophkeeper/internal/server/model"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockBlobService is a mock of BlobService interface.
+type MockBlobService struct {
+	ctrl     *gomock.Controller
+	recorder *MockBlobServiceMockRecorder
+}
+
+// MockBlobServiceMockRecorder is the mock recorder for MockBlobService.
+type MockBlobServiceMockRecorder struct {
+	mock *MockBlobService
+}
+
+// NewMockBlobService creates a new mock instance.
+func NewMockBlobService(ctrl *gomock.Controller) *MockBlobService {
+	mock := &MockBlobService{ctrl: ctrl}
+	mock.recorder = &MockBlobServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBlobService) EXPECT() *MockBlobServiceMockRecorder {
+	return m.recorder
+}
+
+// Collect mocks base method.
+func (m *MockBlobService) Collect(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Collect", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Collect indicates an expected call of Collect.
+func (mr *MockBlobServiceMockRecorder) Collect(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Collect", reflect.TypeOf((*MockBlobService)(nil).Collect), ctx)
+}
+
+// Path mocks base method.
+func (m *MockBlobService) Path(resource *model.Resource) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Path", resource)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Path indicates an expected call of Path.
+func (mr *MockBlobServiceMockRecorder) Path(resource interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Path", reflect.TypeOf((*MockBlobService)(nil).Path), resource)
+}
+
+// Replace mocks base method.
+func (m *MockBlobService) Replace(ctx context.Context, resource *model.Resource, uploadPath string, hash []byte, size int64, limits *model.QuotaLimits) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Replace", ctx, resource, uploadPath, hash, size, limits)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Replace indicates an expected call of Replace.
+func (mr *MockBlobServiceMockRecorder) Replace(ctx, resource, uploadPath, hash, size, limits interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Replace", reflect.TypeOf((*MockBlobService)(nil).Replace), ctx, resource, uploadPath, hash, size, limits)
+}
+
+// RunGC mocks base method.
+func (m *MockBlobService) RunGC(ctx context.Context, interval time.Duration) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RunGC", ctx, interval)
+}
+
+// RunGC indicates an expected call of RunGC.
+func (mr *MockBlobServiceMockRecorder) RunGC(ctx, interval interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunGC", reflect.TypeOf((*MockBlobService)(nil).RunGC), ctx, interval)
+}
+
+// Store mocks base method.
+func (m *MockBlobService) Store(ctx context.Context, userId, resId int32, uploadPath string, hash []byte, size int64, limits *model.QuotaLimits) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Store", ctx, userId, resId, uploadPath, hash, size, limits)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Store indicates an expected call of Store.
+func (mr *MockBlobServiceMockRecorder) Store(ctx, userId, resId, uploadPath, hash, size, limits interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Store", reflect.TypeOf((*MockBlobService)(nil).Store), ctx, userId, resId, uploadPath, hash, size, limits)
+}
+
+// UploadPath mocks base method.
+func (m *MockBlobService) UploadPath() (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadPath")
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UploadPath indicates an expected call of UploadPath.
+func (mr *MockBlobServiceMockRecorder) UploadPath() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadPath", reflect.TypeOf((*MockBlobService)(nil).UploadPath))
+}
@@ -0,0 +1,82 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: quota_service.go
+
+// Package services is a generated GoMock package.
+package services
+
+import (
+	context "context"
+	reflect "reflect"
+	model "ydx-goadv-gophkeeper/internal/server/model"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockQuotaService is a mock of QuotaService interface.
+type MockQuotaService struct {
+	ctrl     *gomock.Controller
+	recorder *MockQuotaServiceMockRecorder
+}
+
+// MockQuotaServiceMockRecorder is the mock recorder for MockQuotaService.
+type MockQuotaServiceMockRecorder struct {
+	mock *MockQuotaService
+}
+
+// NewMockQuotaService creates a new mock instance.
+func NewMockQuotaService(ctrl *gomock.Controller) *MockQuotaService {
+	mock := &MockQuotaService{ctrl: ctrl}
+	mock.recorder = &MockQuotaServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockQuotaService) EXPECT() *MockQuotaServiceMockRecorder {
+	return m.recorder
+}
+
+// Left mocks base method.
+func (m *MockQuotaService) Left(ctx context.Context, userId int32) (int64, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Left", ctx, userId)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Left indicates an expected call of Left.
+func (mr *MockQuotaServiceMockRecorder) Left(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Left", reflect.TypeOf((*MockQuotaService)(nil).Left), ctx, userId)
+}
+
+// Limits mocks base method.
+func (m *MockQuotaService) Limits(ctx context.Context, userId int32) (*model.QuotaLimits, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Limits", ctx, userId)
+	ret0, _ := ret[0].(*model.QuotaLimits)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Limits indicates an expected call of Limits.
+func (mr *MockQuotaServiceMockRecorder) Limits(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Limits", reflect.TypeOf((*MockQuotaService)(nil).Limits), ctx, userId)
+}
+
+// Usage mocks base method.
+func (m *MockQuotaService) Usage(ctx context.Context, userId int32) (*model.Usage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Usage", ctx, userId)
+	ret0, _ := ret[0].(*model.Usage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Usage indicates an expected call of Usage.
+func (mr *MockQuotaServiceMockRecorder) Usage(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Usage", reflect.TypeOf((*MockQuotaService)(nil).Usage), ctx, userId)
+}
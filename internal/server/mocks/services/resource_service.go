@@ -8,7 +8,6 @@ import (
 	context "context"
 	reflect "reflect"
 	model "ydx-goadv-gophkeeper/internal/server/model"
-	enum "ydx-goadv-gophkeeper/pkg/model/enum"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -36,6 +35,21 @@ func (m *MockResourceService) EXPECT() *MockResourceServiceMockRecorder {
 	return m.recorder
 }
 
+// Batch mocks base method.
+func (m *MockResourceService) Batch(ctx context.Context, userId int32, operations []model.BatchOperation, atomic bool, limits *model.QuotaLimits) ([]model.BatchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Batch", ctx, userId, operations, atomic, limits)
+	ret0, _ := ret[0].([]model.BatchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Batch indicates an expected call of Batch.
+func (mr *MockResourceServiceMockRecorder) Batch(ctx, userId, operations, atomic, limits interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Batch", reflect.TypeOf((*MockResourceService)(nil).Batch), ctx, userId, operations, atomic, limits)
+}
+
 // Delete mocks base method.
 func (m *MockResourceService) Delete(ctx context.Context, resId, userId int32) error {
 	m.ctrl.T.Helper()
@@ -66,18 +80,18 @@ func (mr *MockResourceServiceMockRecorder) Get(ctx, resId, userId interface{}) *
 }
 
 // GetDescriptions mocks base method.
-func (m *MockResourceService) GetDescriptions(ctx context.Context, userId int32, resType enum.ResourceType) ([]*model.ResourceDescription, error) {
+func (m *MockResourceService) GetDescriptions(ctx context.Context, query *model.DescriptionsQuery) ([]*model.ResourceDescription, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetDescriptions", ctx, userId, resType)
+	ret := m.ctrl.Call(m, "GetDescriptions", ctx, query)
 	ret0, _ := ret[0].([]*model.ResourceDescription)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetDescriptions indicates an expected call of GetDescriptions.
-func (mr *MockResourceServiceMockRecorder) GetDescriptions(ctx, userId, resType interface{}) *gomock.Call {
+func (mr *MockResourceServiceMockRecorder) GetDescriptions(ctx, query interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDescriptions", reflect.TypeOf((*MockResourceService)(nil).GetDescriptions), ctx, userId, resType)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDescriptions", reflect.TypeOf((*MockResourceService)(nil).GetDescriptions), ctx, query)
 }
 
 // GetFileDescription mocks base method.
@@ -95,45 +109,103 @@ func (mr *MockResourceServiceMockRecorder) GetFileDescription(ctx, resource inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFileDescription", reflect.TypeOf((*MockResourceService)(nil).GetFileDescription), ctx, resource)
 }
 
+// GetFileVersion mocks base method.
+func (m *MockResourceService) GetFileVersion(ctx context.Context, resId, userId, version int32) (*model.FileVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFileVersion", ctx, resId, userId, version)
+	ret0, _ := ret[0].(*model.FileVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFileVersion indicates an expected call of GetFileVersion.
+func (mr *MockResourceServiceMockRecorder) GetFileVersion(ctx, resId, userId, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFileVersion", reflect.TypeOf((*MockResourceService)(nil).GetFileVersion), ctx, resId, userId, version)
+}
+
+// GetFileVersions mocks base method.
+func (m *MockResourceService) GetFileVersions(ctx context.Context, resId, userId int32) ([]*model.FileVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFileVersions", ctx, resId, userId)
+	ret0, _ := ret[0].([]*model.FileVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFileVersions indicates an expected call of GetFileVersions.
+func (mr *MockResourceServiceMockRecorder) GetFileVersions(ctx, resId, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFileVersions", reflect.TypeOf((*MockResourceService)(nil).GetFileVersions), ctx, resId, userId)
+}
+
 // Save mocks base method.
-func (m *MockResourceService) Save(ctx context.Context, res *model.Resource) error {
+func (m *MockResourceService) Save(ctx context.Context, res *model.Resource, limits *model.QuotaLimits) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Save", ctx, res)
+	ret := m.ctrl.Call(m, "Save", ctx, res, limits)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Save indicates an expected call of Save.
-func (mr *MockResourceServiceMockRecorder) Save(ctx, res interface{}) *gomock.Call {
+func (mr *MockResourceServiceMockRecorder) Save(ctx, res, limits interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockResourceService)(nil).Save), ctx, res)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockResourceService)(nil).Save), ctx, res, limits)
 }
 
 // SaveFileDescription mocks base method.
-func (m *MockResourceService) SaveFileDescription(ctx context.Context, userId int32, meta, data []byte) (int32, error) {
+func (m *MockResourceService) SaveFileDescription(ctx context.Context, userId int32, meta, data []byte, limits *model.QuotaLimits) (int32, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SaveFileDescription", ctx, userId, meta, data)
+	ret := m.ctrl.Call(m, "SaveFileDescription", ctx, userId, meta, data, limits)
 	ret0, _ := ret[0].(int32)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // SaveFileDescription indicates an expected call of SaveFileDescription.
-func (mr *MockResourceServiceMockRecorder) SaveFileDescription(ctx, userId, meta, data interface{}) *gomock.Call {
+func (mr *MockResourceServiceMockRecorder) SaveFileDescription(ctx, userId, meta, data, limits interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveFileDescription", reflect.TypeOf((*MockResourceService)(nil).SaveFileDescription), ctx, userId, meta, data)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveFileDescription", reflect.TypeOf((*MockResourceService)(nil).SaveFileDescription), ctx, userId, meta, data, limits)
 }
 
 // Update mocks base method.
-func (m *MockResourceService) Update(ctx context.Context, res *model.Resource) error {
+func (m *MockResourceService) Update(ctx context.Context, res *model.Resource, limits *model.QuotaLimits) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", ctx, res)
+	ret := m.ctrl.Call(m, "Update", ctx, res, limits)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockResourceServiceMockRecorder) Update(ctx, res interface{}) *gomock.Call {
+func (mr *MockResourceServiceMockRecorder) Update(ctx, res, limits interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockResourceService)(nil).Update), ctx, res, limits)
+}
+
+// UpdateFileDescription mocks base method.
+func (m *MockResourceService) UpdateFileDescription(ctx context.Context, res *model.Resource) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateFileDescription", ctx, res)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateFileDescription indicates an expected call of UpdateFileDescription.
+func (mr *MockResourceServiceMockRecorder) UpdateFileDescription(ctx, res interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFileDescription", reflect.TypeOf((*MockResourceService)(nil).UpdateFileDescription), ctx, res)
+}
+
+// UpdateLabels mocks base method.
+func (m *MockResourceService) UpdateLabels(ctx context.Context, userId int32, labels []model.ResourceLabels) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLabels", ctx, userId, labels)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLabels indicates an expected call of UpdateLabels.
+func (mr *MockResourceServiceMockRecorder) UpdateLabels(ctx, userId, labels interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockResourceService)(nil).Update), ctx, res)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLabels", reflect.TypeOf((*MockResourceService)(nil).UpdateLabels), ctx, userId, labels)
 }
@@ -0,0 +1,20 @@
+package model
+
+type BatchKind uint8
+
+const (
+	BatchCreate BatchKind = iota
+	BatchUpdate
+	BatchDelete
+)
+
+type BatchOperation struct {
+	Kind     BatchKind
+	Resource *Resource
+}
+
+// BatchResult is the id of the created, updated or deleted resource or the operation error
+type BatchResult struct {
+	Id  int32
+	Err error
+}
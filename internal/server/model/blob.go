@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// Blob is the file content kept once per user by hash of its ciphertext
+type Blob struct {
+	UserId int32  `db:"user_id"`
+	Hash   []byte `db:"hash"`
+}
+
+// FileVersion is the previous content of the replaced file, it keeps the reference to its blob
+type FileVersion struct {
+	Version    int32     `db:"id"`
+	ResourceId int32     `db:"resource_id"`
+	UserId     int32     `db:"user_id"`
+	Data       []byte    `db:"data"`
+	BlobHash   []byte    `db:"blob_hash"`
+	Size       int64     `db:"size"`
+	ReplacedAt time.Time `db:"replaced_at"`
+}
@@ -0,0 +1,82 @@
+package model
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"ydx-goadv-gophkeeper/pkg/model/enum"
+)
+
+type DescriptionsOrder uint8
+
+const (
+	OrderById DescriptionsOrder = iota
+	OrderByCreated
+	OrderByUpdated
+)
+
+// DescriptionsQuery filters descriptions of the user resources, zero Limit is no limit
+type DescriptionsQuery struct {
+	UserId       int32
+	Types        []enum.ResourceType
+	Ids          []int32
+	UpdatedSince time.Time
+	// LabelTokens are opaque tokens computed by client, resource should have all of them
+	LabelTokens [][]byte
+	Order       DescriptionsOrder
+	Descending  bool
+	Limit       int
+	// After is the keyset of the last resource of the previous page
+	After *Cursor
+}
+
+// Cursor is the position of the resource in the ordered descriptions
+type Cursor struct {
+	Order DescriptionsOrder
+	Time  time.Time
+	Id    int32
+}
+
+func (rd *ResourceDescription) Cursor(order DescriptionsOrder) Cursor {
+	cursor := Cursor{Order: order, Id: rd.Id}
+	switch order {
+	case OrderByCreated:
+		cursor.Time = rd.CreatedAt
+	case OrderByUpdated:
+		cursor.Time = rd.UpdatedAt
+	}
+	return cursor
+}
+
+// Encode returns opaque cursor, time is kept in microseconds as postgres does
+func (c Cursor) Encode() string {
+	raw := fmt.Sprintf("%d:%d:%d", c.Order, c.Time.UnixMicro(), c.Id)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func DecodeCursor(cursor string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("cursor '%s' is not valid", cursor)
+	}
+	parts := strings.Split(string(raw), ":")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("cursor '%s' is not valid", cursor)
+	}
+	order, err := strconv.ParseUint(parts[0], 10, 8)
+	if err != nil || DescriptionsOrder(order) > OrderByUpdated {
+		return nil, fmt.Errorf("cursor '%s' is not valid", cursor)
+	}
+	micros, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("cursor '%s' is not valid", cursor)
+	}
+	id, err := strconv.ParseInt(parts[2], 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("cursor '%s' is not valid", cursor)
+	}
+	return &Cursor{Order: DescriptionsOrder(order), Time: time.UnixMicro(micros).UTC(), Id: int32(id)}, nil
+}
@@ -1,11 +1,18 @@
 package errs
 
-import "errors"
+import (
+	"errors"
+
+	"ydx-goadv-gophkeeper/pkg/model"
+)
 
 var ErrUserAlreadyExist = errors.New("user already exist")
 var ErrUserNotFound = errors.New("user not found")
 var ErrResNotFound = errors.New("resource not found")
 var ErrResTooBig = errors.New("resource is too big")
+var ErrBatchAborted = errors.New("batch is rolled back")
+var ErrQuotaExceeded = errors.New("quota is exceeded")
+var ErrDigestMismatch = model.ErrDigestMismatch
 
 var ErrTokenNotFound = errors.New("unauthorized")
 var ErrTokenInvalid = errors.New("invalid")
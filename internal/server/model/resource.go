@@ -2,6 +2,7 @@ package model
 
 import (
 	"fmt"
+	"time"
 
 	"ydx-goadv-gophkeeper/pkg/model"
 	"ydx-goadv-gophkeeper/pkg/model/enum"
@@ -10,13 +11,28 @@ import (
 type Resource struct {
 	UserId int32  `db:"user_id"`
 	Data   []byte `db:"data"`
+	// BlobHash is the hash of the file content, nil for other resources and files uploaded before blobs
+	BlobHash []byte `db:"blob_hash"`
+	// Size is the size of the data or of the file content counted by quota
+	Size int64 `db:"size"`
 	ResourceDescription
 }
 
 type ResourceDescription struct {
-	Id   int32             `db:"id"`
-	Meta []byte            `db:"meta"`
-	Type enum.ResourceType `db:"type"`
+	Id        int32             `db:"id"`
+	Meta      []byte            `db:"meta"`
+	Type      enum.ResourceType `db:"type"`
+	CreatedAt time.Time         `db:"created_at"`
+	// UpdatedAt is zero for resources created before update time was tracked
+	UpdatedAt time.Time `db:"updated_at"`
+	// Labels are folder and tags encrypted by client
+	Labels []byte `db:"labels"`
+}
+
+type ResourceLabels struct {
+	Id     int32    `db:"id"`
+	Labels []byte   `db:"labels"`
+	Tokens [][]byte `db:"label_tokens"`
 }
 
 func (rd *ResourceDescription) String() string {
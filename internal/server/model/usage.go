@@ -0,0 +1,54 @@
+package model
+
+import "ydx-goadv-gophkeeper/pkg/model/enum"
+
+// Unlimited is the left amount of items or bytes if quota is not set
+const Unlimited = int64(^uint64(0) >> 1)
+
+type TypeUsage struct {
+	Type  enum.ResourceType `db:"type"`
+	Items int64             `db:"items"`
+	Bytes int64             `db:"bytes"`
+}
+
+// QuotaUsage is the consumption of user or organization, zero max values are unlimited
+type QuotaUsage struct {
+	Items    int64
+	Bytes    int64
+	MaxItems int64
+	MaxBytes int64
+}
+
+// Left returns how many items and bytes can be added
+func (q QuotaUsage) Left() (int64, int64) {
+	return left(q.Items, q.MaxItems), left(q.Bytes, q.MaxBytes)
+}
+
+func left(used int64, max int64) int64 {
+	if max <= 0 {
+		return Unlimited
+	}
+	if used >= max {
+		return 0
+	}
+	return max - used
+}
+
+// QuotaLimits are the limits checked by writes of the user, zero max values are unlimited
+type QuotaLimits struct {
+	MaxItems int64
+	MaxBytes int64
+	// Organization is empty if user is not a member of organization, its limits are shared by Members
+	Organization string
+	Members      []string
+	OrgMaxItems  int64
+	OrgMaxBytes  int64
+}
+
+type Usage struct {
+	Types []TypeUsage
+	User  QuotaUsage
+	// Organization is empty if user is not a member of organization
+	Organization      string
+	OrganizationUsage QuotaUsage
+}
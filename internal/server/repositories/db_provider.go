@@ -112,13 +112,13 @@ func (p *pgProvider) HealthCheck(ctx context.Context) error {
 	conn, err := p.GetConnection(ctx)
 	if err != nil {
 		p.log.Error("failed to check connection to Postgres DB: %v", err)
-		return errs.InternalError{err}
+		return errs.InternalError{Err: err}
 	}
 	defer conn.Release()
 	err = conn.Conn().Ping(ctx)
 	if err != nil {
 		p.log.Error("failed to check connection to Postgres DB: %v", err)
-		return errs.InternalError{err}
+		return errs.InternalError{Err: err}
 	}
 	p.log.Info("Postgres DB connection is active")
 	return nil
@@ -4,27 +4,70 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/jackc/pgconn"
 	"github.com/jackc/pgx/v4"
 	"go.uber.org/zap"
 
 	"ydx-goadv-gophkeeper/internal/server/model"
 	"ydx-goadv-gophkeeper/internal/server/model/errs"
 	"ydx-goadv-gophkeeper/pkg/logger"
-	restype "ydx-goadv-gophkeeper/pkg/model"
 	"ydx-goadv-gophkeeper/pkg/model/enum"
 )
 
 //go:generate mockgen -source=resource_repository.go -destination=../mocks/repositories/resource_repository.go -package=repositories
 
 type ResourceRepository interface {
-	Save(ctx context.Context, resource *model.Resource) error
-	Update(ctx context.Context, resource *model.Resource) error
+	// Save inserts the resource, errs.ErrQuotaExceeded is returned if it does not fit in the quota limits
+	Save(ctx context.Context, resource *model.Resource, limits *model.QuotaLimits) error
+	// Update replaces data and meta of the resource, errs.ErrResNotFound is returned if the user has no such resource
+	// of the type, growth of its size over the quota limits fails with errs.ErrQuotaExceeded
+	Update(ctx context.Context, resource *model.Resource, limits *model.QuotaLimits) error
 	Get(ctx context.Context, resId int32, userId int32) (*model.Resource, error)
-	GetResDescriptionsByType(ctx context.Context, userId int32, resType enum.ResourceType) ([]*model.ResourceDescription, error)
+	GetResDescriptions(ctx context.Context, query *model.DescriptionsQuery) ([]*model.ResourceDescription, error)
 	Delete(ctx context.Context, resId int32, userId int32) error
+	// UpdateLabels updates labels of all the resources or none if some resource is not found
+	UpdateLabels(ctx context.Context, userId int32, labels []model.ResourceLabels) error
+	// Batch applies operations in one transaction, failed operations are rolled back alone or with the whole batch if atomic,
+	// operations exceeding the quota limits fail with errs.ErrQuotaExceeded
+	Batch(ctx context.Context, userId int32, operations []model.BatchOperation, atomic bool, limits *model.QuotaLimits) ([]model.BatchResult, error)
+	// Usage sums items and sizes of the users resources by type
+	Usage(ctx context.Context, usernames []string) ([]model.TypeUsage, error)
+	// AttachBlob references the blob by the uploaded file resource, blob is added on its first reference,
+	// errs.ErrQuotaExceeded is returned if the file does not fit in the quota limits
+	AttachBlob(ctx context.Context, resId int32, userId int32, hash []byte, size int64, limits *model.QuotaLimits) error
+	// ReplaceBlob replaces description and content of the file resource keeping its id, labels and creation time,
+	// the previous content is kept as the file version, errs.ErrQuotaExceeded is returned if growth of the file
+	// does not fit in the quota limits
+	ReplaceBlob(ctx context.Context, resource *model.Resource, hash []byte, size int64, limits *model.QuotaLimits) error
+	// GetFileVersions returns previous contents of the file from the latest one
+	GetFileVersions(ctx context.Context, resId int32, userId int32) ([]*model.FileVersion, error)
+	// GetFileVersion returns the previous content of the file, errs.ErrResNotFound is returned if there is no such version
+	GetFileVersion(ctx context.Context, resId int32, userId int32, version int32) (*model.FileVersion, error)
+	// UpdateDescription updates description and meta of the file resource keeping its content
+	UpdateDescription(ctx context.Context, resource *model.Resource) error
+	// DeleteUnreferencedBlobs deletes blobs which are not referenced by any resource or file version
+	DeleteUnreferencedBlobs(ctx context.Context) ([]model.Blob, error)
 }
 
+// querier is either connection or transaction
+type querier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+var orderColumns = map[model.DescriptionsOrder]string{
+	model.OrderById:      "id",
+	model.OrderByCreated: "created_at",
+	// unknown update time is the zero time of the cursor, the expression is indexed
+	model.OrderByUpdated: "coalesce(updated_at, '0001-01-01 00:00:00+00'::timestamptz)",
+}
+
+// maxFileVersions is the number of previous contents kept for the file, older versions release their blobs
+const maxFileVersions = 10
+
 type resourceRepository struct {
 	log *zap.SugaredLogger
 	db  DBProvider
@@ -34,7 +77,7 @@ func NewResourceRepository(db DBProvider) ResourceRepository {
 	return &resourceRepository{log: logger.NewLogger("res-repo"), db: db}
 }
 
-func (r *resourceRepository) Save(ctx context.Context, resource *model.Resource) error {
+func (r *resourceRepository) Save(ctx context.Context, resource *model.Resource, limits *model.QuotaLimits) error {
 	r.log.Infof("Saving resource: %v", resource)
 	conn, err := r.db.GetConnection(ctx)
 	if err != nil {
@@ -42,26 +85,48 @@ func (r *resourceRepository) Save(ctx context.Context, resource *model.Resource)
 		return errs.DbError{Err: err}
 	}
 	defer conn.Release()
-	var resId int32
-	row := conn.QueryRow(
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		r.log.Errorf("failed to begin transaction: %v", err)
+		return errs.DbError{Err: err}
+	}
+	defer tx.Rollback(ctx)
+	left, err := r.lockQuota(ctx, tx, resource.UserId, limits)
+	if err != nil {
+		return err
+	}
+	if _, err := r.apply(ctx, tx, model.BatchOperation{Kind: model.BatchCreate, Resource: resource}, left); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		r.log.Errorf("failed to commit '%d' resource: %v", resource.Id, err)
+		return errs.DbError{Err: err}
+	}
+	r.log.Infof("Resource saved: %v", resource)
+	return nil
+}
+
+func (r *resourceRepository) insert(ctx context.Context, q querier, resource *model.Resource) error {
+	row := q.QueryRow(
 		ctx,
-		"insert into resources(user_id, type, data, meta) values ($1, $2, $3, $4) RETURNING id",
+		"insert into resources(user_id, type, data, meta, labels, size) values ($1, $2, $3, $4, $5, $6) RETURNING id, created_at, updated_at",
 		resource.UserId,
 		resource.Type,
 		resource.Data,
 		resource.Meta,
+		resource.Labels,
+		len(resource.Data),
 	)
-	err = row.Scan(&resId)
+	err := row.Scan(&resource.Id, &resource.CreatedAt, &resource.UpdatedAt)
 	if err != nil {
 		r.log.Errorf("failed to scan resId: %v", err)
 		return errs.DbError{Err: err}
 	}
-	resource.Id = resId
-	r.log.Infof("Resource saved: %v", resource)
 	return nil
 }
 
-func (r *resourceRepository) Update(ctx context.Context, resource *model.Resource) error {
+func (r *resourceRepository) Update(ctx context.Context, resource *model.Resource, limits *model.QuotaLimits) error {
 	r.log.Infof("Updating resource: %v", resource)
 	conn, err := r.db.GetConnection(ctx)
 	if err != nil {
@@ -69,28 +134,119 @@ func (r *resourceRepository) Update(ctx context.Context, resource *model.Resourc
 		return errs.DbError{Err: err}
 	}
 	defer conn.Release()
-	var resId int32
-	row := conn.QueryRow(
-		ctx,
-		"insert into resources(id, user_id, type, data, meta) values ($1, $2, $3, $4, $5) "+
-			"ON CONFLICT (id) DO UPDATE SET data = excluded.data, meta = excluded.meta "+
-			"RETURNING id",
-		resource.Id,
-		resource.UserId,
-		resource.Type,
-		resource.Data,
-		resource.Meta,
-	)
-	err = row.Scan(&resId)
+
+	tx, err := conn.Begin(ctx)
 	if err != nil {
-		r.log.Errorf("failed to scan resId: %v", err)
+		r.log.Errorf("failed to begin transaction: %v", err)
+		return errs.DbError{Err: err}
+	}
+	defer tx.Rollback(ctx)
+	left, err := r.lockQuota(ctx, tx, resource.UserId, limits)
+	if err != nil {
+		return err
+	}
+	// only the existing resource of the user and of the same type is updated
+	if _, err := r.apply(ctx, tx, model.BatchOperation{Kind: model.BatchUpdate, Resource: resource}, left); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		r.log.Errorf("failed to commit '%d' resource: %v", resource.Id, err)
 		return errs.DbError{Err: err}
 	}
-	resource.Id = resId
 	r.log.Infof("Resource updated: %v", resource)
 	return nil
 }
 
+// growth locks the resource until the end of the transaction and returns how much its size grows with the new size
+func (r *resourceRepository) growth(ctx context.Context, q querier, resId int32, userId int32, resType enum.ResourceType, newSize int64) (int64, error) {
+	var size int64
+	row := q.QueryRow(
+		ctx,
+		"select size from resources where id = $1 and user_id = $2 and type = $3 for update",
+		resId,
+		userId,
+		resType,
+	)
+	err := row.Scan(&size)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, errs.ErrResNotFound
+	}
+	if err != nil {
+		r.log.Errorf("failed to get size of '%d' resource: %v", resId, err)
+		return 0, errs.DbError{Err: err}
+	}
+	return newSize - size, nil
+}
+
+// quotaLeft is the headroom of the user within the locked quota
+type quotaLeft struct {
+	items int64
+	bytes int64
+}
+
+// fit returns errs.ErrQuotaExceeded if the growth does not fit in the headroom, shrinking always fits
+func (q *quotaLeft) fit(items int64, bytes int64) error {
+	if items > 0 && items > q.items {
+		return fmt.Errorf("%w: %d more resources can be saved", errs.ErrQuotaExceeded, q.items)
+	}
+	if bytes > 0 && bytes > q.bytes {
+		return fmt.Errorf("%w: %d more bytes can be saved", errs.ErrQuotaExceeded, q.bytes)
+	}
+	return nil
+}
+
+// take reduces the headroom by the applied growth
+func (q *quotaLeft) take(items int64, bytes int64) {
+	if q.items != model.Unlimited {
+		q.items -= items
+	}
+	if q.bytes != model.Unlimited {
+		q.bytes -= bytes
+	}
+}
+
+// lockQuota locks the quota of the user until the end of the transaction and returns its headroom,
+// members of organization share the lock, so parallel writes do not exceed the shared quota
+func (r *resourceRepository) lockQuota(ctx context.Context, q querier, userId int32, limits *model.QuotaLimits) (*quotaLeft, error) {
+	scope := fmt.Sprintf("quota:user:%d", userId)
+	if limits.Organization != "" {
+		scope = "quota:org:" + limits.Organization
+	}
+	if _, err := q.Exec(ctx, "select pg_advisory_xact_lock(hashtext($1))", scope); err != nil {
+		r.log.Errorf("failed to lock quota of '%d' user: %v", userId, err)
+		return nil, errs.DbError{Err: err}
+	}
+	usage := model.QuotaUsage{MaxItems: limits.MaxItems, MaxBytes: limits.MaxBytes}
+	row := q.QueryRow(ctx, "select count(*), coalesce(sum(size), 0) from resources where user_id = $1", userId)
+	if err := row.Scan(&usage.Items, &usage.Bytes); err != nil {
+		r.log.Errorf("failed to get usage of '%d' user: %v", userId, err)
+		return nil, errs.DbError{Err: err}
+	}
+	left := &quotaLeft{}
+	left.items, left.bytes = usage.Left()
+	if limits.Organization == "" {
+		return left, nil
+	}
+	orgUsage := model.QuotaUsage{MaxItems: limits.OrgMaxItems, MaxBytes: limits.OrgMaxBytes}
+	row = q.QueryRow(
+		ctx,
+		"select count(*), coalesce(sum(r.size), 0) from resources r join users u on u.id = r.user_id where u.username = any($1)",
+		limits.Members,
+	)
+	if err := row.Scan(&orgUsage.Items, &orgUsage.Bytes); err != nil {
+		r.log.Errorf("failed to get usage of '%s' organization: %v", limits.Organization, err)
+		return nil, errs.DbError{Err: err}
+	}
+	orgItems, orgBytes := orgUsage.Left()
+	if orgItems < left.items {
+		left.items = orgItems
+	}
+	if orgBytes < left.bytes {
+		left.bytes = orgBytes
+	}
+	return left, nil
+}
+
 func (r *resourceRepository) Get(ctx context.Context, resId int32, userId int32) (*model.Resource, error) {
 	r.log.Infof("Getting '%d' resource of '%d' user", resId, userId)
 	var result model.Resource
@@ -101,8 +257,9 @@ func (r *resourceRepository) Get(ctx context.Context, resId int32, userId int32)
 	}
 	defer conn.Release()
 	var row pgx.Row
-	row = conn.QueryRow(ctx, "select id, user_id, type, meta, data from resources where id = $1 and user_id = $2", resId, userId)
-	err = row.Scan(&result.Id, &result.UserId, &result.Type, &result.Meta, &result.Data)
+	row = conn.QueryRow(ctx, "select id, user_id, type, meta, data, created_at, updated_at, labels, blob_hash, size from resources where id = $1 and user_id = $2", resId, userId)
+	var updatedAt *time.Time
+	err = row.Scan(&result.Id, &result.UserId, &result.Type, &result.Meta, &result.Data, &result.CreatedAt, &updatedAt, &result.Labels, &result.BlobHash, &result.Size)
 	if errors.Is(err, pgx.ErrNoRows) {
 		r.log.Warnf("There is no '%d' resource of '%d' user", resId, userId)
 		return nil, errs.ErrResNotFound
@@ -111,59 +268,346 @@ func (r *resourceRepository) Get(ctx context.Context, resId int32, userId int32)
 		r.log.Errorf("failed to parse scan resourse '%d' result row: %v", resId, err)
 		return nil, errs.DbError{Err: err}
 	}
+	if updatedAt != nil {
+		result.UpdatedAt = *updatedAt
+	}
 	return &result, nil
 }
 
-func (r *resourceRepository) GetResDescriptionsByType(
-	ctx context.Context,
-	userId int32,
-	resType enum.ResourceType,
-) ([]*model.ResourceDescription, error) {
-	r.log.Infof("Getting descriptions of '%d' user's resourses by type %s", userId, restype.TypeToArg[resType])
+// GetResDescriptions reads a page of descriptions, pages are continued by keyset of the order column and id
+func (r *resourceRepository) GetResDescriptions(ctx context.Context, query *model.DescriptionsQuery) ([]*model.ResourceDescription, error) {
+	r.log.Infof("Getting resource descriptions of '%d' user", query.UserId)
+	conn, err := r.db.GetConnection(ctx)
+	if err != nil {
+		r.log.Errorf("failed to get db connection: %v", err)
+		return nil, errs.DbError{Err: err}
+	}
+	defer conn.Release()
+
+	sql, args := descriptionsSql(query)
+	rows, err := conn.Query(ctx, sql, args...)
+	if err != nil {
+		r.log.Errorf("failed to query resources for '%d' user: %v", query.UserId, err)
+		return nil, errs.DbError{Err: err}
+	}
+	defer rows.Close()
 	var results []*model.ResourceDescription
+	for rows.Next() {
+		resDescr := &model.ResourceDescription{}
+		var updatedAt *time.Time
+		err := rows.Scan(&resDescr.Id, &resDescr.Meta, &resDescr.Type, &resDescr.CreatedAt, &updatedAt, &resDescr.Labels)
+		if err != nil {
+			r.log.Errorf("failed to scan resources of userId '%d': %v", query.UserId, err)
+			return nil, errs.DbError{Err: fmt.Errorf("failed to read resources of userId '%d': %v", query.UserId, err)}
+		}
+		if updatedAt != nil {
+			resDescr.UpdatedAt = *updatedAt
+		}
+		results = append(results, resDescr)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errs.DbError{Err: err}
+	}
+	return results, nil
+}
+
+func descriptionsSql(query *model.DescriptionsQuery) (string, []any) {
+	args := []any{query.UserId}
+	arg := func(value any) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+	var sql strings.Builder
+	sql.WriteString("select id, meta, type, created_at, updated_at, labels from resources where user_id = $1")
+	if len(query.Types) != 0 {
+		types := make([]int32, 0, len(query.Types))
+		for _, resType := range query.Types {
+			types = append(types, int32(resType))
+		}
+		sql.WriteString(" and type = any(" + arg(types) + ")")
+	}
+	if len(query.Ids) != 0 {
+		sql.WriteString(" and id = any(" + arg(query.Ids) + ")")
+	}
+	if !query.UpdatedSince.IsZero() {
+		sql.WriteString(" and updated_at >= " + arg(query.UpdatedSince))
+	}
+	if len(query.LabelTokens) != 0 {
+		sql.WriteString(" and label_tokens @> " + arg(query.LabelTokens) + "::bytea[]")
+	}
+
+	column := orderColumns[query.Order]
+	compare, direction := ">", "asc"
+	if query.Descending {
+		compare, direction = "<", "desc"
+	}
+	if query.After != nil {
+		if query.Order == model.OrderById {
+			sql.WriteString(fmt.Sprintf(" and id %s %s", compare, arg(query.After.Id)))
+		} else {
+			sql.WriteString(fmt.Sprintf(" and (%s, id) %s (%s, %s)", column, compare, arg(query.After.Time), arg(query.After.Id)))
+		}
+	}
+	if query.Order == model.OrderById {
+		sql.WriteString(" order by id " + direction)
+	} else {
+		sql.WriteString(fmt.Sprintf(" order by %s %s, id %s", column, direction, direction))
+	}
+	if query.Limit > 0 {
+		sql.WriteString(" limit " + arg(query.Limit))
+	}
+	return sql.String(), args
+}
+
+func (r *resourceRepository) Delete(ctx context.Context, resId int32, userId int32) error {
+	r.log.Infof("Deletting '%d' resource of '%d' user", resId, userId)
+	conn, err := r.db.GetConnection(ctx)
+	if err != nil {
+		r.log.Errorf("failed to get db connection: %v", err)
+		return errs.DbError{Err: err}
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		r.log.Errorf("failed to begin transaction: %v", err)
+		return errs.DbError{Err: err}
+	}
+	defer tx.Rollback(ctx)
+	if err := r.remove(ctx, tx, resId, userId); err != nil && !errors.Is(err, errs.ErrResNotFound) {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		r.log.Errorf("failed to commit deletion of '%d' resource: %v", resId, err)
+		return errs.DbError{Err: err}
+	}
+	return nil
+}
+
+// remove deletes the resource and releases its blob and the blobs of its versions
+func (r *resourceRepository) remove(ctx context.Context, q querier, resId int32, userId int32) error {
+	// versions are deleted with the resource, so their blobs are released first
+	_, err := q.Exec(
+		ctx,
+		"update blobs b set refs = b.refs - v.refs "+
+			"from (select blob_hash, count(*) refs from file_versions where resource_id = $1 and user_id = $2 group by blob_hash) v "+
+			"where b.user_id = $2 and b.hash = v.blob_hash",
+		resId,
+		userId,
+	)
+	if err != nil {
+		r.log.Errorf("failed to release versions of '%d' resource: %v", resId, err)
+		return errs.DbError{Err: err}
+	}
+	var blobHash []byte
+	err = q.QueryRow(ctx, "delete from resources where id = $1 and user_id = $2 RETURNING blob_hash", resId, userId).Scan(&blobHash)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return errs.ErrResNotFound
+	}
+	if err != nil {
+		r.log.Errorf("failed to delete '%d' resource of '%d' user: %v", resId, userId, err)
+		return errs.DbError{Err: err}
+	}
+	if blobHash == nil {
+		return nil
+	}
+	_, err = q.Exec(ctx, "update blobs set refs = refs - 1 where user_id = $1 and hash = $2", userId, blobHash)
+	if err != nil {
+		r.log.Errorf("failed to release blob of '%d' resource: %v", resId, err)
+		return errs.DbError{Err: err}
+	}
+	return nil
+}
+
+func (r *resourceRepository) UpdateLabels(ctx context.Context, userId int32, labels []model.ResourceLabels) error {
+	r.log.Infof("Updating labels of %d resources of '%d' user", len(labels), userId)
 	conn, err := r.db.GetConnection(ctx)
 	if err != nil {
 		r.log.Errorf("failed to get db connection: %v", err)
-		return results, errs.DbError{Err: err}
+		return errs.DbError{Err: err}
 	}
 	defer conn.Release()
 
-	var rows pgx.Rows
-	if resType == enum.Nan {
-		r.log.Infof("Getting all resource descriptions of '%d' user", userId)
-		rows, err = conn.Query(
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		r.log.Errorf("failed to begin transaction: %v", err)
+		return errs.DbError{Err: err}
+	}
+	defer tx.Rollback(ctx)
+	for _, resLabels := range labels {
+		tokens := resLabels.Tokens
+		if tokens == nil {
+			tokens = [][]byte{}
+		}
+		tag, err := tx.Exec(
 			ctx,
-			"select id, meta, type from resources where user_id = $1",
+			"update resources set labels = $1, label_tokens = $2 where id = $3 and user_id = $4",
+			resLabels.Labels,
+			tokens,
+			resLabels.Id,
 			userId,
 		)
-	} else {
-		r.log.Infof("Getting '%s' resource descriptions of '%d' user", restype.TypeToArg[resType], userId)
-		rows, err = conn.Query(
+		if err != nil {
+			r.log.Errorf("failed to update labels of '%d' resource of '%d' user: %v", resLabels.Id, userId, err)
+			return errs.DbError{Err: err}
+		}
+		if tag.RowsAffected() == 0 {
+			r.log.Warnf("There is no '%d' resource of '%d' user", resLabels.Id, userId)
+			return errs.ErrResNotFound
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		r.log.Errorf("failed to commit labels of '%d' user: %v", userId, err)
+		return errs.DbError{Err: err}
+	}
+	return nil
+}
+
+func (r *resourceRepository) Batch(ctx context.Context, userId int32, operations []model.BatchOperation, atomic bool, limits *model.QuotaLimits) ([]model.BatchResult, error) {
+	r.log.Infof("Applying batch of %d operations of '%d' user, atomic: %v", len(operations), userId, atomic)
+	conn, err := r.db.GetConnection(ctx)
+	if err != nil {
+		r.log.Errorf("failed to get db connection: %v", err)
+		return nil, errs.DbError{Err: err}
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		r.log.Errorf("failed to begin transaction: %v", err)
+		return nil, errs.DbError{Err: err}
+	}
+	defer tx.Rollback(ctx)
+	left, err := r.lockQuota(ctx, tx, userId, limits)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]model.BatchResult, len(operations))
+	for i, operation := range operations {
+		operation.Resource.UserId = userId
+		if atomic {
+			if _, err := r.apply(ctx, tx, operation, left); err != nil {
+				return abortBatch(results, i, err), nil
+			}
+			results[i].Id = operation.Resource.Id
+			continue
+		}
+		// savepoint rolls back only the failed operation
+		savepoint, err := tx.Begin(ctx)
+		if err != nil {
+			r.log.Errorf("failed to create savepoint: %v", err)
+			return nil, errs.DbError{Err: err}
+		}
+		// the headroom taken by the failed operation is restored with the savepoint
+		before := *left
+		if _, err := r.apply(ctx, savepoint, operation, left); err != nil {
+			*left = before
+			results[i].Err = err
+			if err := savepoint.Rollback(ctx); err != nil {
+				return nil, errs.DbError{Err: err}
+			}
+			continue
+		}
+		if err := savepoint.Commit(ctx); err != nil {
+			return nil, errs.DbError{Err: err}
+		}
+		results[i].Id = operation.Resource.Id
+	}
+	if err := tx.Commit(ctx); err != nil {
+		r.log.Errorf("failed to commit batch of '%d' user: %v", userId, err)
+		return nil, errs.DbError{Err: err}
+	}
+	return results, nil
+}
+
+// apply applies the operation and returns how much it grows the size of the user resources
+func (r *resourceRepository) apply(ctx context.Context, q querier, operation model.BatchOperation, left *quotaLeft) (int64, error) {
+	resource := operation.Resource
+	switch operation.Kind {
+	case model.BatchCreate:
+		growth := int64(len(resource.Data))
+		if err := left.fit(1, growth); err != nil {
+			return 0, err
+		}
+		if err := r.insert(ctx, q, resource); err != nil {
+			return 0, err
+		}
+		left.take(1, growth)
+		return growth, nil
+	case model.BatchUpdate:
+		growth, err := r.growth(ctx, q, resource.Id, resource.UserId, resource.Type, int64(len(resource.Data)))
+		if err != nil {
+			return 0, err
+		}
+		if err := left.fit(0, growth); err != nil {
+			return 0, err
+		}
+		row := q.QueryRow(
 			ctx,
-			"select id, meta, type from resources where user_id = $1 and type = $2",
-			userId,
-			resType,
+			"update resources set data = $1, meta = $2, size = $3, updated_at = now() where id = $4 and user_id = $5 and type = $6 "+
+				"RETURNING created_at, updated_at",
+			resource.Data,
+			resource.Meta,
+			len(resource.Data),
+			resource.Id,
+			resource.UserId,
+			resource.Type,
 		)
+		err = row.Scan(&resource.CreatedAt, &resource.UpdatedAt)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, errs.ErrResNotFound
+		}
+		if err != nil {
+			r.log.Errorf("failed to update '%d' resource: %v", resource.Id, err)
+			return 0, errs.DbError{Err: err}
+		}
+		left.take(0, growth)
+		return growth, nil
+	case model.BatchDelete:
+		if err := r.remove(ctx, q, resource.Id, resource.UserId); err != nil {
+			return 0, err
+		}
+		// freed items and bytes are not returned to the headroom, they are counted again by the next write
+		return 0, nil
 	}
+	return 0, fmt.Errorf("batch operation %d is not supported", operation.Kind)
+}
+
+func (r *resourceRepository) Usage(ctx context.Context, usernames []string) ([]model.TypeUsage, error) {
+	conn, err := r.db.GetConnection(ctx)
 	if err != nil {
-		r.log.Errorf("failed to query resources for '%d' user: %v", userId, err)
+		r.log.Errorf("failed to get db connection: %v", err)
+		return nil, errs.DbError{Err: err}
+	}
+	defer conn.Release()
+	rows, err := conn.Query(
+		ctx,
+		"select r.type, count(*), coalesce(sum(r.size), 0) from resources r join users u on u.id = r.user_id "+
+			"where u.username = any($1) group by r.type order by r.type",
+		usernames,
+	)
+	if err != nil {
+		r.log.Errorf("failed to query usage of %v users: %v", usernames, err)
 		return nil, errs.DbError{Err: err}
 	}
 	defer rows.Close()
+	var results []model.TypeUsage
 	for rows.Next() {
-		resDescr := &model.ResourceDescription{}
-		err := rows.Scan(&resDescr.Id, &resDescr.Meta, &resDescr.Type)
-		if err != nil {
-			r.log.Errorf("failed to scan '%s' resources of userId '%d': %v", restype.TypeToArg[resType], userId, err)
-			return nil, errs.DbError{Err: fmt.Errorf("failed to read '%d' resources of userId '%d': %v", resType, userId, err)}
+		var usage model.TypeUsage
+		if err := rows.Scan(&usage.Type, &usage.Items, &usage.Bytes); err != nil {
+			r.log.Errorf("failed to scan usage of %v users: %v", usernames, err)
+			return nil, errs.DbError{Err: err}
 		}
-		results = append(results, resDescr)
+		results = append(results, usage)
 	}
-	return results, err
+	if err := rows.Err(); err != nil {
+		return nil, errs.DbError{Err: err}
+	}
+	return results, nil
 }
 
-func (r *resourceRepository) Delete(ctx context.Context, resId int32, userId int32) error {
-	r.log.Infof("Deletting '%d' resource of '%d' user", resId, userId)
+func (r *resourceRepository) AttachBlob(ctx context.Context, resId int32, userId int32, hash []byte, size int64, limits *model.QuotaLimits) error {
+	r.log.Infof("Attaching blob to '%d' resource of '%d' user", resId, userId)
 	conn, err := r.db.GetConnection(ctx)
 	if err != nil {
 		r.log.Errorf("failed to get db connection: %v", err)
@@ -171,10 +615,314 @@ func (r *resourceRepository) Delete(ctx context.Context, resId int32, userId int
 	}
 	defer conn.Release()
 
-	_, err = conn.Exec(ctx, "delete from resources where id = $1 and user_id = $2", resId, userId)
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		r.log.Errorf("failed to begin transaction: %v", err)
+		return errs.DbError{Err: err}
+	}
+	defer tx.Rollback(ctx)
+	left, err := r.lockQuota(ctx, tx, userId, limits)
+	if err != nil {
+		return err
+	}
+	growth, err := r.growth(ctx, tx, resId, userId, enum.File, size)
 	if err != nil {
-		r.log.Errorf("failed to delete  '%d' resource of '%d' user: %v", resId, userId, err)
+		return err
+	}
+	if err := left.fit(0, growth); err != nil {
+		return err
+	}
+	tag, err := tx.Exec(
+		ctx,
+		"update resources set blob_hash = $1, size = $2 where id = $3 and user_id = $4 and blob_hash is null",
+		hash,
+		size,
+		resId,
+		userId,
+	)
+	if err != nil {
+		r.log.Errorf("failed to set blob of '%d' resource of '%d' user: %v", resId, userId, err)
+		return errs.DbError{Err: err}
+	}
+	if tag.RowsAffected() == 0 {
+		r.log.Warnf("There is no '%d' resource of '%d' user without blob", resId, userId)
+		return errs.ErrResNotFound
+	}
+	_, err = tx.Exec(
+		ctx,
+		"insert into blobs(user_id, hash, size, refs) values ($1, $2, $3, 1) "+
+			"ON CONFLICT (user_id, hash) DO UPDATE SET refs = blobs.refs + 1",
+		userId,
+		hash,
+		size,
+	)
+	if err != nil {
+		r.log.Errorf("failed to reference blob of '%d' user: %v", userId, err)
+		return errs.DbError{Err: err}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		r.log.Errorf("failed to commit blob of '%d' resource: %v", resId, err)
 		return errs.DbError{Err: err}
 	}
 	return nil
 }
+
+func (r *resourceRepository) ReplaceBlob(ctx context.Context, resource *model.Resource, hash []byte, size int64, limits *model.QuotaLimits) error {
+	r.log.Infof("Replacing blob of '%d' resource of '%d' user", resource.Id, resource.UserId)
+	conn, err := r.db.GetConnection(ctx)
+	if err != nil {
+		r.log.Errorf("failed to get db connection: %v", err)
+		return errs.DbError{Err: err}
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		r.log.Errorf("failed to begin transaction: %v", err)
+		return errs.DbError{Err: err}
+	}
+	defer tx.Rollback(ctx)
+	left, err := r.lockQuota(ctx, tx, resource.UserId, limits)
+	if err != nil {
+		return err
+	}
+	var oldData, oldHash []byte
+	var oldSize int64
+	row := tx.QueryRow(
+		ctx,
+		"select data, blob_hash, size from resources where id = $1 and user_id = $2 and type = $3 for update",
+		resource.Id,
+		resource.UserId,
+		enum.File,
+	)
+	err = row.Scan(&oldData, &oldHash, &oldSize)
+	if errors.Is(err, pgx.ErrNoRows) {
+		r.log.Warnf("There is no '%d' file of '%d' user", resource.Id, resource.UserId)
+		return errs.ErrResNotFound
+	}
+	if err != nil {
+		r.log.Errorf("failed to get blob of '%d' resource: %v", resource.Id, err)
+		return errs.DbError{Err: err}
+	}
+	// only the growth of the replaced file is limited by quota
+	if err := left.fit(0, size-oldSize); err != nil {
+		return err
+	}
+	row = tx.QueryRow(
+		ctx,
+		"update resources set data = $1, meta = $2, blob_hash = $3, size = $4, updated_at = now() where id = $5 "+
+			"RETURNING created_at, updated_at",
+		resource.Data,
+		resource.Meta,
+		hash,
+		size,
+		resource.Id,
+	)
+	if err := row.Scan(&resource.CreatedAt, &resource.UpdatedAt); err != nil {
+		r.log.Errorf("failed to replace '%d' resource: %v", resource.Id, err)
+		return errs.DbError{Err: err}
+	}
+	_, err = tx.Exec(
+		ctx,
+		"insert into blobs(user_id, hash, size, refs) values ($1, $2, $3, 1) "+
+			"ON CONFLICT (user_id, hash) DO UPDATE SET refs = blobs.refs + 1",
+		resource.UserId,
+		hash,
+		size,
+	)
+	if err != nil {
+		r.log.Errorf("failed to reference blob of '%d' user: %v", resource.UserId, err)
+		return errs.DbError{Err: err}
+	}
+	// files uploaded before blobs have no content to keep
+	if oldHash != nil {
+		if err := r.keepVersion(ctx, tx, resource, oldData, oldHash, oldSize); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		r.log.Errorf("failed to commit blob of '%d' resource: %v", resource.Id, err)
+		return errs.DbError{Err: err}
+	}
+	return nil
+}
+
+// keepVersion moves the reference to the replaced blob to the file version,
+// versions over maxFileVersions are deleted and release their blobs
+func (r *resourceRepository) keepVersion(ctx context.Context, q querier, resource *model.Resource, data []byte, hash []byte, size int64) error {
+	_, err := q.Exec(
+		ctx,
+		"insert into file_versions(resource_id, user_id, data, blob_hash, size) values ($1, $2, $3, $4, $5)",
+		resource.Id,
+		resource.UserId,
+		data,
+		hash,
+		size,
+	)
+	if err != nil {
+		r.log.Errorf("failed to keep version of '%d' resource: %v", resource.Id, err)
+		return errs.DbError{Err: err}
+	}
+	_, err = q.Exec(
+		ctx,
+		"with pruned as ("+
+			"delete from file_versions where resource_id = $1 and id not in "+
+			"(select id from file_versions where resource_id = $1 order by id desc limit $2) RETURNING blob_hash) "+
+			"update blobs b set refs = b.refs - p.refs "+
+			"from (select blob_hash, count(*) refs from pruned group by blob_hash) p "+
+			"where b.user_id = $3 and b.hash = p.blob_hash",
+		resource.Id,
+		maxFileVersions,
+		resource.UserId,
+	)
+	if err != nil {
+		r.log.Errorf("failed to prune versions of '%d' resource: %v", resource.Id, err)
+		return errs.DbError{Err: err}
+	}
+	return nil
+}
+
+func (r *resourceRepository) GetFileVersions(ctx context.Context, resId int32, userId int32) ([]*model.FileVersion, error) {
+	conn, err := r.db.GetConnection(ctx)
+	if err != nil {
+		r.log.Errorf("failed to get db connection: %v", err)
+		return nil, errs.DbError{Err: err}
+	}
+	defer conn.Release()
+	rows, err := conn.Query(
+		ctx,
+		"select id, resource_id, user_id, data, blob_hash, size, replaced_at from file_versions "+
+			"where resource_id = $1 and user_id = $2 order by id desc",
+		resId,
+		userId,
+	)
+	if err != nil {
+		r.log.Errorf("failed to get versions of '%d' resource: %v", resId, err)
+		return nil, errs.DbError{Err: err}
+	}
+	defer rows.Close()
+	var versions []*model.FileVersion
+	for rows.Next() {
+		var version model.FileVersion
+		err := rows.Scan(
+			&version.Version,
+			&version.ResourceId,
+			&version.UserId,
+			&version.Data,
+			&version.BlobHash,
+			&version.Size,
+			&version.ReplacedAt,
+		)
+		if err != nil {
+			r.log.Errorf("failed to scan version of '%d' resource: %v", resId, err)
+			return nil, errs.DbError{Err: err}
+		}
+		versions = append(versions, &version)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errs.DbError{Err: err}
+	}
+	return versions, nil
+}
+
+func (r *resourceRepository) GetFileVersion(ctx context.Context, resId int32, userId int32, version int32) (*model.FileVersion, error) {
+	conn, err := r.db.GetConnection(ctx)
+	if err != nil {
+		r.log.Errorf("failed to get db connection: %v", err)
+		return nil, errs.DbError{Err: err}
+	}
+	defer conn.Release()
+	var fileVersion model.FileVersion
+	row := conn.QueryRow(
+		ctx,
+		"select id, resource_id, user_id, data, blob_hash, size, replaced_at from file_versions "+
+			"where id = $1 and resource_id = $2 and user_id = $3",
+		version,
+		resId,
+		userId,
+	)
+	err = row.Scan(
+		&fileVersion.Version,
+		&fileVersion.ResourceId,
+		&fileVersion.UserId,
+		&fileVersion.Data,
+		&fileVersion.BlobHash,
+		&fileVersion.Size,
+		&fileVersion.ReplacedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errs.ErrResNotFound
+	}
+	if err != nil {
+		r.log.Errorf("failed to get version '%d' of '%d' resource: %v", version, resId, err)
+		return nil, errs.DbError{Err: err}
+	}
+	return &fileVersion, nil
+}
+
+func (r *resourceRepository) UpdateDescription(ctx context.Context, resource *model.Resource) error {
+	r.log.Infof("Updating description of '%d' file of '%d' user", resource.Id, resource.UserId)
+	conn, err := r.db.GetConnection(ctx)
+	if err != nil {
+		r.log.Errorf("failed to get db connection: %v", err)
+		return errs.DbError{Err: err}
+	}
+	defer conn.Release()
+	row := conn.QueryRow(
+		ctx,
+		"update resources set data = $1, meta = $2, updated_at = now() where id = $3 and user_id = $4 and type = $5 "+
+			"RETURNING created_at, updated_at",
+		resource.Data,
+		resource.Meta,
+		resource.Id,
+		resource.UserId,
+		enum.File,
+	)
+	err = row.Scan(&resource.CreatedAt, &resource.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		r.log.Warnf("There is no '%d' file of '%d' user", resource.Id, resource.UserId)
+		return errs.ErrResNotFound
+	}
+	if err != nil {
+		r.log.Errorf("failed to update description of '%d' resource: %v", resource.Id, err)
+		return errs.DbError{Err: err}
+	}
+	return nil
+}
+
+func (r *resourceRepository) DeleteUnreferencedBlobs(ctx context.Context) ([]model.Blob, error) {
+	conn, err := r.db.GetConnection(ctx)
+	if err != nil {
+		r.log.Errorf("failed to get db connection: %v", err)
+		return nil, errs.DbError{Err: err}
+	}
+	defer conn.Release()
+	rows, err := conn.Query(ctx, "delete from blobs where refs <= 0 RETURNING user_id, hash")
+	if err != nil {
+		r.log.Errorf("failed to delete unreferenced blobs: %v", err)
+		return nil, errs.DbError{Err: err}
+	}
+	defer rows.Close()
+	var blobs []model.Blob
+	for rows.Next() {
+		var blob model.Blob
+		if err := rows.Scan(&blob.UserId, &blob.Hash); err != nil {
+			r.log.Errorf("failed to scan deleted blob: %v", err)
+			return nil, errs.DbError{Err: err}
+		}
+		blobs = append(blobs, blob)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errs.DbError{Err: err}
+	}
+	return blobs, nil
+}
+
+// abortBatch sets error of the failed operation, the rest ones are not applied
+func abortBatch(results []model.BatchResult, failed int, err error) []model.BatchResult {
+	for i := range results {
+		results[i] = model.BatchResult{Err: errs.ErrBatchAborted}
+	}
+	results[failed].Err = err
+	return results
+}
@@ -20,6 +20,7 @@ import (
 type UserRepository interface {
 	CreateUser(context.Context, *model.User) (int32, error)
 	GetUser(ctx context.Context, username string) (*model.User, error)
+	GetUsername(ctx context.Context, userId int32) (string, error)
 }
 
 type userRepository struct {
@@ -75,3 +76,23 @@ func (r *userRepository) GetUser(ctx context.Context, username string) (*model.U
 
 	return user, nil
 }
+
+func (r *userRepository) GetUsername(ctx context.Context, userId int32) (string, error) {
+	conn, err := r.db.GetConnection(ctx)
+	if err != nil {
+		r.log.Errorf("failed to get db connection: %v", err)
+		return "", errs.DbError{Err: err}
+	}
+	defer conn.Release()
+	var username string
+	err = conn.QueryRow(ctx, "select username from users where id = $1", userId).Scan(&username)
+	if errors.Is(err, pgx.ErrNoRows) {
+		r.log.Warnf("User '%d' not found", userId)
+		return "", errs.ErrUserNotFound
+	}
+	if err != nil {
+		r.log.Errorf("failed to scan username of user '%d': %v", userId, err)
+		return "", errs.DbError{Err: err}
+	}
+	return username, nil
+}
@@ -0,0 +1,179 @@
+package services
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"ydx-goadv-gophkeeper/internal/server/model"
+	"ydx-goadv-gophkeeper/internal/server/repositories"
+	"ydx-goadv-gophkeeper/pkg/logger"
+)
+
+const (
+	// legacyFileDir keeps files uploaded before blobs by resource id
+	legacyFileDir = "./cmd/server"
+	uploadPrefix  = "upload-"
+	// abandonedUploadAge is the age of the upload which is surely not in progress anymore
+	abandonedUploadAge = 24 * time.Hour
+)
+
+//go:generate mockgen -source=blob_service.go -destination=../mocks/services/blob_service.go -package=services
+
+// BlobService keeps file contents once by hash of the ciphertext. Blobs are scoped by user,
+// so equal files of different users are never matched.
+type BlobService interface {
+	// UploadPath returns new path to upload file to before its hash is known
+	UploadPath() (string, error)
+	// Path returns path of the file resource content
+	Path(resource *model.Resource) string
+	// Store moves the upload to the blob of its hash and references the blob by the file resource
+	Store(ctx context.Context, userId int32, resId int32, uploadPath string, hash []byte, size int64, limits *model.QuotaLimits) error
+	// Replace moves the upload to the blob of its hash and replaces content and description of the file resource
+	Replace(ctx context.Context, resource *model.Resource, uploadPath string, hash []byte, size int64, limits *model.QuotaLimits) error
+	// Collect removes unreferenced blobs and abandoned uploads, returns the number of removed blobs
+	Collect(ctx context.Context) (int, error)
+	// RunGC collects garbage every interval until ctx is done
+	RunGC(ctx context.Context, interval time.Duration)
+}
+
+type blobService struct {
+	log  *zap.SugaredLogger
+	repo repositories.ResourceRepository
+	dir  string
+	// mu orders storing with collecting, so the blob referenced again is not removed
+	mu sync.Mutex
+}
+
+func NewBlobService(repo repositories.ResourceRepository, dir string) BlobService {
+	return &blobService{log: logger.NewLogger("blob-service"), repo: repo, dir: dir}
+}
+
+func (s *blobService) UploadPath() (string, error) {
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return "", err
+	}
+	file, err := os.CreateTemp(s.dir, uploadPrefix+"*")
+	if err != nil {
+		return "", err
+	}
+	if err := file.Close(); err != nil {
+		return "", err
+	}
+	return file.Name(), nil
+}
+
+func (s *blobService) Path(resource *model.Resource) string {
+	if resource.BlobHash == nil {
+		return fmt.Sprintf("%s/%d", legacyFileDir, resource.Id)
+	}
+	return s.blobPath(resource.UserId, resource.BlobHash)
+}
+
+func (s *blobService) blobPath(userId int32, hash []byte) string {
+	return filepath.Join(s.dir, strconv.Itoa(int(userId)), hex.EncodeToString(hash))
+}
+
+func (s *blobService) Store(ctx context.Context, userId int32, resId int32, uploadPath string, hash []byte, size int64, limits *model.QuotaLimits) error {
+	return s.store(userId, uploadPath, hash, func() error {
+		return s.repo.AttachBlob(ctx, resId, userId, hash, size, limits)
+	})
+}
+
+func (s *blobService) Replace(ctx context.Context, resource *model.Resource, uploadPath string, hash []byte, size int64, limits *model.QuotaLimits) error {
+	return s.store(resource.UserId, uploadPath, hash, func() error {
+		return s.repo.ReplaceBlob(ctx, resource, hash, size, limits)
+	})
+}
+
+// store moves the upload to the blob and references it, the new blob is removed if it is not referenced
+func (s *blobService) store(userId int32, uploadPath string, hash []byte, reference func() error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	path := s.blobPath(userId, hash)
+	_, err := os.Stat(path)
+	exists := err == nil
+	if exists {
+		s.log.Infof("Upload '%s' is a duplicate of the stored blob", uploadPath)
+		if err := os.Remove(uploadPath); err != nil {
+			s.log.Warnf("failed to remove upload '%s': %v", uploadPath, err)
+		}
+	} else {
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			return err
+		}
+		if err := os.Rename(uploadPath, path); err != nil {
+			return err
+		}
+	}
+	if err := reference(); err != nil {
+		if !exists {
+			s.removeBlob(path)
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *blobService) Collect(ctx context.Context) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	blobs, err := s.repo.DeleteUnreferencedBlobs(ctx)
+	if err != nil {
+		return 0, err
+	}
+	for _, blob := range blobs {
+		s.removeBlob(s.blobPath(blob.UserId, blob.Hash))
+	}
+	entries, err := os.ReadDir(s.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return len(blobs), nil
+	}
+	if err != nil {
+		return len(blobs), err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasPrefix(entry.Name(), uploadPrefix) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || time.Since(info.ModTime()) < abandonedUploadAge {
+			continue
+		}
+		s.removeBlob(filepath.Join(s.dir, entry.Name()))
+	}
+	return len(blobs), nil
+}
+
+func (s *blobService) RunGC(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		removed, err := s.Collect(ctx)
+		if err != nil {
+			s.log.Errorf("failed to collect blobs: %v", err)
+		} else if removed != 0 {
+			s.log.Infof("%d unreferenced blobs were removed", removed)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *blobService) removeBlob(path string) {
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		s.log.Errorf("failed to remove '%s': %v", path, err)
+	}
+}
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"ydx-goadv-gophkeeper/internal/server/mocks/repositories"
+	"ydx-goadv-gophkeeper/internal/server/model"
+)
+
+func TestBlobService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+	repo := repositories.NewMockResourceRepository(ctrl)
+	dir := t.TempDir()
+	service := NewBlobService(repo, dir)
+	hash := []byte{0xca, 0xfe}
+
+	upload := func(content string) string {
+		path, err := service.UploadPath()
+		assert.NoError(t, err)
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0600))
+		return path
+	}
+	limits := &model.QuotaLimits{}
+	repo.EXPECT().AttachBlob(ctx, int32(1), int32(7), hash, int64(4), limits).Return(nil)
+	repo.EXPECT().AttachBlob(ctx, int32(2), int32(7), hash, int64(4), limits).Return(nil)
+	assert.NoError(t, service.Store(ctx, 7, 1, upload("data"), hash, 4, limits))
+	assert.NoError(t, service.Store(ctx, 7, 2, upload("data"), hash, 4, limits))
+
+	// duplicate is kept once in the user scope
+	path := service.Path(&model.Resource{UserId: 7, BlobHash: hash})
+	assert.Equal(t, filepath.Join(dir, "7", "cafe"), path)
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "data", string(content))
+
+	repo.EXPECT().DeleteUnreferencedBlobs(ctx).Return([]model.Blob{{UserId: 7, Hash: hash}}, nil)
+	removed, err := service.Collect(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, removed)
+	_, err = os.Stat(path)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -0,0 +1,118 @@
+package services
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"ydx-goadv-gophkeeper/internal/server/configs"
+	"ydx-goadv-gophkeeper/internal/server/model"
+	"ydx-goadv-gophkeeper/internal/server/repositories"
+	"ydx-goadv-gophkeeper/pkg/logger"
+)
+
+//go:generate mockgen -source=quota_service.go -destination=../mocks/services/quota_service.go -package=services
+
+type QuotaService interface {
+	// Usage returns user resources by type and consumption of the user and organization quotas
+	Usage(ctx context.Context, userId int32) (*model.Usage, error)
+	// Left returns how many items and bytes the user can add within both user and organization quotas
+	Left(ctx context.Context, userId int32) (int64, int64, error)
+	// Limits returns the quota limits which are checked by the repository in the write transaction
+	Limits(ctx context.Context, userId int32) (*model.QuotaLimits, error)
+}
+
+type quotaService struct {
+	log      *zap.SugaredLogger
+	resRepo  repositories.ResourceRepository
+	userRepo repositories.UserRepository
+	quotas   configs.QuotaConfig
+}
+
+func NewQuotaService(resRepo repositories.ResourceRepository, userRepo repositories.UserRepository, quotas configs.QuotaConfig) QuotaService {
+	return &quotaService{log: logger.NewLogger("quota-service"), resRepo: resRepo, userRepo: userRepo, quotas: quotas}
+}
+
+func (s *quotaService) Usage(ctx context.Context, userId int32) (*model.Usage, error) {
+	username, err := s.userRepo.GetUsername(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	types, err := s.resRepo.Usage(ctx, []string{username})
+	if err != nil {
+		return nil, err
+	}
+	limit, ok := s.quotas.Users[username]
+	if !ok {
+		limit = s.quotas.User
+	}
+	usage := &model.Usage{Types: types, User: total(types, limit)}
+	if org, ok := s.organization(username); ok {
+		orgTypes, err := s.resRepo.Usage(ctx, org.Members)
+		if err != nil {
+			return nil, err
+		}
+		usage.Organization = org.Name
+		usage.OrganizationUsage = total(orgTypes, org.Limit)
+	}
+	return usage, nil
+}
+
+func (s *quotaService) Left(ctx context.Context, userId int32) (int64, int64, error) {
+	usage, err := s.Usage(ctx, userId)
+	if err != nil {
+		return 0, 0, err
+	}
+	items, bytes := usage.User.Left()
+	if usage.Organization != "" {
+		orgItems, orgBytes := usage.OrganizationUsage.Left()
+		items, bytes = minInt64(items, orgItems), minInt64(bytes, orgBytes)
+	}
+	return items, bytes, nil
+}
+
+func (s *quotaService) Limits(ctx context.Context, userId int32) (*model.QuotaLimits, error) {
+	username, err := s.userRepo.GetUsername(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	limit, ok := s.quotas.Users[username]
+	if !ok {
+		limit = s.quotas.User
+	}
+	limits := &model.QuotaLimits{MaxItems: limit.MaxItems, MaxBytes: limit.MaxBytes}
+	if org, ok := s.organization(username); ok {
+		limits.Organization = org.Name
+		limits.Members = org.Members
+		limits.OrgMaxItems = org.MaxItems
+		limits.OrgMaxBytes = org.MaxBytes
+	}
+	return limits, nil
+}
+
+func (s *quotaService) organization(username string) (configs.Organization, bool) {
+	for _, org := range s.quotas.Organizations {
+		for _, member := range org.Members {
+			if member == username {
+				return org, true
+			}
+		}
+	}
+	return configs.Organization{}, false
+}
+
+func total(types []model.TypeUsage, limit configs.Limit) model.QuotaUsage {
+	usage := model.QuotaUsage{MaxItems: limit.MaxItems, MaxBytes: limit.MaxBytes}
+	for _, typeUsage := range types {
+		usage.Items += typeUsage.Items
+		usage.Bytes += typeUsage.Bytes
+	}
+	return usage
+}
+
+func minInt64(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
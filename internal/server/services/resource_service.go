@@ -14,13 +14,23 @@ import (
 //go:generate mockgen -source=resource_service.go -destination=../mocks/services/resource_service.go -package=services
 
 type ResourceService interface {
-	Save(ctx context.Context, res *model.Resource) error
-	Update(ctx context.Context, res *model.Resource) error
+	// Save adds the resource, errs.ErrQuotaExceeded is returned if it does not fit in the quota limits
+	Save(ctx context.Context, res *model.Resource, limits *model.QuotaLimits) error
+	// Update replaces the resource, growth of its size over the quota limits fails with errs.ErrQuotaExceeded
+	Update(ctx context.Context, res *model.Resource, limits *model.QuotaLimits) error
 	Delete(ctx context.Context, resId, userId int32) error
-	GetDescriptions(ctx context.Context, userId int32, resType enum.ResourceType) ([]*model.ResourceDescription, error)
+	// GetDescriptions returns a page of descriptions matching the query
+	GetDescriptions(ctx context.Context, query *model.DescriptionsQuery) ([]*model.ResourceDescription, error)
 	Get(ctx context.Context, resId int32, userId int32) (*model.Resource, error)
-	SaveFileDescription(ctx context.Context, userId int32, meta []byte, data []byte) (int32, error)
+	SaveFileDescription(ctx context.Context, userId int32, meta []byte, data []byte, limits *model.QuotaLimits) (int32, error)
 	GetFileDescription(ctx context.Context, resource *model.Resource) ([]byte, error)
+	UpdateLabels(ctx context.Context, userId int32, labels []model.ResourceLabels) error
+	Batch(ctx context.Context, userId int32, operations []model.BatchOperation, atomic bool, limits *model.QuotaLimits) ([]model.BatchResult, error)
+	// UpdateFileDescription renames the file or changes its meta keeping its content
+	UpdateFileDescription(ctx context.Context, res *model.Resource) error
+	// GetFileVersions returns previous contents of the file from the latest one
+	GetFileVersions(ctx context.Context, resId int32, userId int32) ([]*model.FileVersion, error)
+	GetFileVersion(ctx context.Context, resId int32, userId int32, version int32) (*model.FileVersion, error)
 }
 
 type resourceService struct {
@@ -32,27 +42,27 @@ func NewResourceService(repo repositories.ResourceRepository) ResourceService {
 	return &resourceService{log: logger.NewLogger("res-service"), repo: repo}
 }
 
-func (s *resourceService) Save(ctx context.Context, data *model.Resource) error {
-	return s.repo.Save(ctx, data)
+func (s *resourceService) Save(ctx context.Context, data *model.Resource, limits *model.QuotaLimits) error {
+	return s.repo.Save(ctx, data, limits)
 }
 
-func (s *resourceService) Update(ctx context.Context, data *model.Resource) error {
-	return s.repo.Update(ctx, data)
+func (s *resourceService) Update(ctx context.Context, data *model.Resource, limits *model.QuotaLimits) error {
+	return s.repo.Update(ctx, data, limits)
 }
 
 func (s *resourceService) Delete(ctx context.Context, resId int32, userId int32) error {
 	return s.repo.Delete(ctx, resId, userId)
 }
 
-func (s *resourceService) GetDescriptions(ctx context.Context, userId int32, resType enum.ResourceType) ([]*model.ResourceDescription, error) {
-	return s.repo.GetResDescriptionsByType(ctx, userId, resType)
+func (s *resourceService) GetDescriptions(ctx context.Context, query *model.DescriptionsQuery) ([]*model.ResourceDescription, error) {
+	return s.repo.GetResDescriptions(ctx, query)
 }
 
 func (s *resourceService) Get(ctx context.Context, resId int32, userId int32) (*model.Resource, error) {
 	return s.repo.Get(ctx, resId, userId)
 }
 
-func (s *resourceService) SaveFileDescription(ctx context.Context, userId int32, meta []byte, data []byte) (int32, error) {
+func (s *resourceService) SaveFileDescription(ctx context.Context, userId int32, meta []byte, data []byte, limits *model.QuotaLimits) (int32, error) {
 	resource := &model.Resource{
 		UserId: userId,
 		Data:   data,
@@ -60,7 +70,7 @@ func (s *resourceService) SaveFileDescription(ctx context.Context, userId int32,
 	resource.Type = enum.File
 	resource.Meta = meta
 
-	err := s.repo.Save(ctx, resource)
+	err := s.repo.Save(ctx, resource, limits)
 	if err != nil {
 		return 0, err
 	}
@@ -75,3 +85,23 @@ func (s *resourceService) GetFileDescription(ctx context.Context, resource *mode
 	}
 	return res.Data, nil
 }
+
+func (s *resourceService) UpdateLabels(ctx context.Context, userId int32, labels []model.ResourceLabels) error {
+	return s.repo.UpdateLabels(ctx, userId, labels)
+}
+
+func (s *resourceService) Batch(ctx context.Context, userId int32, operations []model.BatchOperation, atomic bool, limits *model.QuotaLimits) ([]model.BatchResult, error) {
+	return s.repo.Batch(ctx, userId, operations, atomic, limits)
+}
+
+func (s *resourceService) UpdateFileDescription(ctx context.Context, res *model.Resource) error {
+	return s.repo.UpdateDescription(ctx, res)
+}
+
+func (s *resourceService) GetFileVersions(ctx context.Context, resId int32, userId int32) ([]*model.FileVersion, error) {
+	return s.repo.GetFileVersions(ctx, resId, userId)
+}
+
+func (s *resourceService) GetFileVersion(ctx context.Context, resId int32, userId int32, version int32) (*model.FileVersion, error) {
+	return s.repo.GetFileVersion(ctx, resId, userId, version)
+}
@@ -0,0 +1,8 @@
+-- resources were stored with File = 2, BankCard = 3, while the protobuf wire ids are BankCard = 2, File = 3
+update resources
+set type = case type when 2 then 3 when 3 then 2 end
+where type in (2, 3);
+---- create above / drop below ----
+update resources
+set type = case type when 2 then 3 when 3 then 2 end
+where type in (2, 3);
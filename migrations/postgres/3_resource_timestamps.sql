@@ -0,0 +1,10 @@
+alter table resources
+    add column created_at timestamptz not null default now(),
+    -- update time of existing resources is unknown, so it's null rather than the migration time
+    add column updated_at timestamptz;
+alter table resources
+    alter column updated_at set default now();
+---- create above / drop below ----
+alter table resources
+    drop column if exists created_at,
+    drop column if exists updated_at;
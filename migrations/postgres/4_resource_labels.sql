@@ -0,0 +1,5 @@
+alter table resources
+    add column labels bytea;
+---- create above / drop below ----
+alter table resources
+    drop column if exists labels;
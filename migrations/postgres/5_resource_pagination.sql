@@ -0,0 +1,15 @@
+alter table resources
+    add column label_tokens bytea[] not null default '{}';
+create index if not exists resources_user_id_idx on resources (user_id, id);
+create index if not exists resources_user_type_idx on resources (user_id, type, id);
+create index if not exists resources_user_created_idx on resources (user_id, created_at, id);
+create index if not exists resources_user_updated_idx on resources (user_id, updated_at, id);
+create index if not exists resources_label_tokens_idx on resources using gin (label_tokens);
+---- create above / drop below ----
+drop index if exists resources_label_tokens_idx;
+drop index if exists resources_user_updated_idx;
+drop index if exists resources_user_created_idx;
+drop index if exists resources_user_type_idx;
+drop index if exists resources_user_id_idx;
+alter table resources
+    drop column if exists label_tokens;
@@ -0,0 +1,11 @@
+alter table resources
+    add column size bigint not null default 0;
+-- file resource keeps its description as data, file size is taken from it
+update resources
+set size = case
+               when type = 3 then coalesce((convert_from(data, 'UTF8')::json ->> 'Size')::bigint, 0)
+               else coalesce(octet_length(data), 0)
+    end;
+---- create above / drop below ----
+alter table resources
+    drop column if exists size;
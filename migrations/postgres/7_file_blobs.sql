@@ -0,0 +1,18 @@
+-- file contents are kept once per user by hash of the ciphertext
+create table blobs
+(
+    user_id int    not null,
+    hash    bytea  not null,
+    size    bigint not null,
+    refs    int    not null,
+
+    primary key (user_id, hash),
+    CONSTRAINT fk_users FOREIGN KEY (user_id) REFERENCES users (id) on delete cascade
+);
+-- files uploaded before have no blob and are kept by resource id
+alter table resources
+    add column blob_hash bytea;
+---- create above / drop below ----
+alter table resources
+    drop column if exists blob_hash;
+DROP TABLE IF EXISTS "blobs";
@@ -0,0 +1,7 @@
+-- resources with unknown update time are ordered first, as the zero time of the keyset cursor
+drop index if exists resources_user_updated_idx;
+create index if not exists resources_user_updated_idx
+    on resources (user_id, (coalesce(updated_at, '0001-01-01 00:00:00+00'::timestamptz)), id);
+---- create above / drop below ----
+drop index if exists resources_user_updated_idx;
+create index if not exists resources_user_updated_idx on resources (user_id, updated_at, id);
@@ -0,0 +1,16 @@
+-- previous contents of replaced files, every version references its blob
+create table file_versions
+(
+    id          serial primary key,
+    resource_id int         not null,
+    user_id     int         not null,
+    data        bytea       not null,
+    blob_hash   bytea       not null,
+    size        bigint      not null,
+    replaced_at timestamptz not null default now(),
+
+    CONSTRAINT fk_resources FOREIGN KEY (resource_id) REFERENCES resources (id) on delete cascade
+);
+create index file_versions_resource_idx on file_versions (resource_id, id);
+---- create above / drop below ----
+DROP TABLE IF EXISTS "file_versions";
@@ -75,6 +75,20 @@ func (mr *MockExitHandlerMockRecorder) IsNewFuncExecutionAllowed() *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsNewFuncExecutionAllowed", reflect.TypeOf((*MockExitHandler)(nil).IsNewFuncExecutionAllowed))
 }
 
+// OnInterrupt mocks base method.
+func (m *MockExitHandler) OnInterrupt(handler func()) func() {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OnInterrupt", handler)
+	ret0, _ := ret[0].(func())
+	return ret0
+}
+
+// OnInterrupt indicates an expected call of OnInterrupt.
+func (mr *MockExitHandlerMockRecorder) OnInterrupt(handler interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnInterrupt", reflect.TypeOf((*MockExitHandler)(nil).OnInterrupt), handler)
+}
+
 // ProperExitDefer mocks base method.
 func (m *MockExitHandler) ProperExitDefer() chan struct{} {
 	m.ctrl.T.Helper()
@@ -2,9 +2,14 @@ package enum
 
 type ResourceType uint8
 
+// values are the protobuf wire ids, see registry package
 const (
-	Nan ResourceType = iota
-	LoginPassword
-	File
-	BankCard
+	Nan           ResourceType = 0
+	LoginPassword ResourceType = 1
+	BankCard      ResourceType = 2
+	File          ResourceType = 3
+	SshKey        ResourceType = 4
+	SecureNote    ResourceType = 5
+	Totp          ResourceType = 6
+	Identity      ResourceType = 7
 )
@@ -0,0 +1,6 @@
+package model
+
+import "errors"
+
+// ErrDigestMismatch is returned when a file chunk does not match its digest, it is shared by the server and the client
+var ErrDigestMismatch = errors.New("digest mismatch")
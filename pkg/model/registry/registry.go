@@ -0,0 +1,72 @@
+package registry
+
+import (
+	"fmt"
+
+	"ydx-goadv-gophkeeper/pkg/model/enum"
+	"ydx-goadv-gophkeeper/pkg/pb"
+)
+
+// Kind describes resource type shared by client and server
+type Kind struct {
+	Type enum.ResourceType
+	// Wire is the protobuf type, it's also stored in the 'type' column
+	Wire pb.TYPE
+	// Alias is the CLI argument of the type
+	Alias string
+	Name  string
+}
+
+var kinds = []Kind{
+	{Type: enum.LoginPassword, Wire: pb.TYPE_LOGIN_PASSWORD, Alias: "lp", Name: "LoginPassword"},
+	{Type: enum.BankCard, Wire: pb.TYPE_BANK_CARD, Alias: "bc", Name: "BankCard"},
+	{Type: enum.File, Wire: pb.TYPE_FILE, Alias: "fl", Name: "File"},
+	{Type: enum.SshKey, Wire: pb.TYPE_SSH_KEY, Alias: "ssh", Name: "SshKey"},
+	{Type: enum.SecureNote, Wire: pb.TYPE_SECURE_NOTE, Alias: "nt", Name: "SecureNote"},
+	{Type: enum.Totp, Wire: pb.TYPE_TOTP, Alias: "otp", Name: "Totp"},
+	{Type: enum.Identity, Wire: pb.TYPE_IDENTITY, Alias: "id", Name: "Identity"},
+}
+
+// All returns kinds in the declaration order
+func All() []Kind {
+	return append([]Kind(nil), kinds...)
+}
+
+func ByType(resType enum.ResourceType) (Kind, bool) {
+	for _, kind := range kinds {
+		if kind.Type == resType {
+			return kind, true
+		}
+	}
+	return Kind{}, false
+}
+
+func ByAlias(alias string) (Kind, bool) {
+	for _, kind := range kinds {
+		if kind.Alias == alias {
+			return kind, true
+		}
+	}
+	return Kind{}, false
+}
+
+// ToWire converts resource type to protobuf type, enum.Nan is pb.TYPE_NAN
+func ToWire(resType enum.ResourceType) pb.TYPE {
+	if kind, ok := ByType(resType); ok {
+		return kind.Wire
+	}
+	return pb.TYPE_NAN
+}
+
+// FromWire converts protobuf type to resource type, pb.TYPE_NAN is enum.Nan
+func FromWire(wire pb.TYPE) (enum.ResourceType, error) {
+	if wire == pb.TYPE_NAN {
+		return enum.Nan, nil
+	}
+	for _, kind := range kinds {
+		if kind.Wire == wire {
+			return kind.Type, nil
+		}
+	}
+	return enum.Nan, fmt.Errorf("resource type '%d' is not supported", wire)
+}
@@ -0,0 +1,33 @@
+package registry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"ydx-goadv-gophkeeper/pkg/model/enum"
+	"ydx-goadv-gophkeeper/pkg/pb"
+)
+
+func TestKinds_MatchWire(t *testing.T) {
+	aliases := make(map[string]bool)
+	for _, kind := range All() {
+		assert.Equal(t, int32(kind.Wire), int32(kind.Type), "enum and wire ids of %s differ", kind.Name)
+		assert.False(t, aliases[kind.Alias], "alias %s is duplicated", kind.Alias)
+		aliases[kind.Alias] = true
+	}
+	assert.Len(t, All(), len(pb.TYPE_name)-1, "every protobuf type should be registered")
+}
+
+func TestFromWire(t *testing.T) {
+	resType, err := FromWire(pb.TYPE_FILE)
+	assert.NoError(t, err)
+	assert.Equal(t, enum.File, resType)
+
+	resType, err = FromWire(pb.TYPE_NAN)
+	assert.NoError(t, err)
+	assert.Equal(t, enum.Nan, resType)
+
+	_, err = FromWire(pb.TYPE(100))
+	assert.Error(t, err)
+}
@@ -2,24 +2,17 @@ package model
 
 import (
 	"ydx-goadv-gophkeeper/pkg/model/enum"
-)
-
-const (
-	LoginPasswordArg = "lp"
-	FileArg          = "fl"
-	BankCardArg      = "bc"
+	"ydx-goadv-gophkeeper/pkg/model/registry"
 )
 
 var (
-	ArgToType = map[string]enum.ResourceType{
-		LoginPasswordArg: enum.LoginPassword,
-		FileArg:          enum.File,
-		BankCardArg:      enum.BankCard,
-	}
+	ArgToType = map[string]enum.ResourceType{}
+	TypeToArg = map[enum.ResourceType]string{}
+)
 
-	TypeToArg = map[enum.ResourceType]string{
-		enum.LoginPassword: LoginPasswordArg,
-		enum.File:          FileArg,
-		enum.BankCard:      BankCardArg,
+func init() {
+	for _, kind := range registry.All() {
+		ArgToType[kind.Alias] = kind.Type
+		TypeToArg[kind.Type] = kind.Alias
 	}
-)
+}
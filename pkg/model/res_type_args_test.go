@@ -0,0 +1,40 @@
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"ydx-goadv-gophkeeper/pkg/model/enum"
+	"ydx-goadv-gophkeeper/pkg/model/registry"
+	"ydx-goadv-gophkeeper/pkg/pb"
+)
+
+// TestResourceType_RoundTrip checks every protobuf type against the enum value of the same id
+// by name, and that it round-trips through the wire conversion and the CLI argument parser
+func TestResourceType_RoundTrip(t *testing.T) {
+	for wireId, wireName := range pb.TYPE_name {
+		wire := pb.TYPE(wireId)
+		if wire == pb.TYPE_NAN {
+			continue
+		}
+		resType := enum.ResourceType(wireId)
+		kind, ok := registry.ByType(resType)
+		if !assert.True(t, ok, "protobuf type %s has no enum value %d", wireName, wireId) {
+			continue
+		}
+		assert.True(t, strings.EqualFold(strings.ReplaceAll(wireName, "_", ""), kind.Name),
+			"enum value %d is %s, but protobuf type is %s", wireId, kind.Name, wireName)
+
+		fromWire, err := registry.FromWire(wire)
+		assert.NoError(t, err)
+		assert.Equal(t, resType, fromWire)
+		assert.Equal(t, wire, registry.ToWire(resType))
+
+		arg, ok := TypeToArg[resType]
+		assert.True(t, ok, "%s has no CLI argument", kind.Name)
+		assert.Equal(t, resType, ArgToType[arg])
+	}
+	assert.Len(t, ArgToType, len(pb.TYPE_name)-1)
+}
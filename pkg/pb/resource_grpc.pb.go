@@ -27,6 +27,11 @@ const (
 	Resources_Get_FullMethodName             = "/gophkeeper.Resources/Get"
 	Resources_SaveFile_FullMethodName        = "/gophkeeper.Resources/SaveFile"
 	Resources_GetFile_FullMethodName         = "/gophkeeper.Resources/GetFile"
+	Resources_GetFileVersions_FullMethodName = "/gophkeeper.Resources/GetFileVersions"
+	Resources_GetFileVersion_FullMethodName  = "/gophkeeper.Resources/GetFileVersion"
+	Resources_UpdateLabels_FullMethodName    = "/gophkeeper.Resources/UpdateLabels"
+	Resources_Batch_FullMethodName           = "/gophkeeper.Resources/Batch"
+	Resources_Usage_FullMethodName           = "/gophkeeper.Resources/Usage"
 )
 
 // ResourcesClient is the client API for Resources service.
@@ -40,6 +45,11 @@ type ResourcesClient interface {
 	Get(ctx context.Context, in *ResourceId, opts ...grpc.CallOption) (*Resource, error)
 	SaveFile(ctx context.Context, opts ...grpc.CallOption) (Resources_SaveFileClient, error)
 	GetFile(ctx context.Context, in *ResourceId, opts ...grpc.CallOption) (Resources_GetFileClient, error)
+	GetFileVersions(ctx context.Context, in *ResourceId, opts ...grpc.CallOption) (*FileVersions, error)
+	GetFileVersion(ctx context.Context, in *FileVersionId, opts ...grpc.CallOption) (Resources_GetFileVersionClient, error)
+	UpdateLabels(ctx context.Context, in *LabelsUpdate, opts ...grpc.CallOption) (*empty.Empty, error)
+	Batch(ctx context.Context, in *BatchRequest, opts ...grpc.CallOption) (*BatchResponse, error)
+	Usage(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*UsageReport, error)
 }
 
 type resourcesClient struct {
@@ -184,6 +194,74 @@ func (x *resourcesGetFileClient) Recv() (*FileChunk, error) {
 	return m, nil
 }
 
+func (c *resourcesClient) GetFileVersions(ctx context.Context, in *ResourceId, opts ...grpc.CallOption) (*FileVersions, error) {
+	out := new(FileVersions)
+	err := c.cc.Invoke(ctx, Resources_GetFileVersions_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *resourcesClient) GetFileVersion(ctx context.Context, in *FileVersionId, opts ...grpc.CallOption) (Resources_GetFileVersionClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Resources_ServiceDesc.Streams[3], Resources_GetFileVersion_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &resourcesGetFileVersionClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Resources_GetFileVersionClient interface {
+	Recv() (*FileChunk, error)
+	grpc.ClientStream
+}
+
+type resourcesGetFileVersionClient struct {
+	grpc.ClientStream
+}
+
+func (x *resourcesGetFileVersionClient) Recv() (*FileChunk, error) {
+	m := new(FileChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *resourcesClient) UpdateLabels(ctx context.Context, in *LabelsUpdate, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, Resources_UpdateLabels_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *resourcesClient) Batch(ctx context.Context, in *BatchRequest, opts ...grpc.CallOption) (*BatchResponse, error) {
+	out := new(BatchResponse)
+	err := c.cc.Invoke(ctx, Resources_Batch_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *resourcesClient) Usage(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*UsageReport, error) {
+	out := new(UsageReport)
+	err := c.cc.Invoke(ctx, Resources_Usage_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ResourcesServer is the server API for Resources service.
 // All implementations must embed UnimplementedResourcesServer
 // for forward compatibility
@@ -195,6 +273,11 @@ type ResourcesServer interface {
 	Get(context.Context, *ResourceId) (*Resource, error)
 	SaveFile(Resources_SaveFileServer) error
 	GetFile(*ResourceId, Resources_GetFileServer) error
+	GetFileVersions(context.Context, *ResourceId) (*FileVersions, error)
+	GetFileVersion(*FileVersionId, Resources_GetFileVersionServer) error
+	UpdateLabels(context.Context, *LabelsUpdate) (*empty.Empty, error)
+	Batch(context.Context, *BatchRequest) (*BatchResponse, error)
+	Usage(context.Context, *empty.Empty) (*UsageReport, error)
 	mustEmbedUnimplementedResourcesServer()
 }
 
@@ -223,6 +306,21 @@ func (UnimplementedResourcesServer) SaveFile(Resources_SaveFileServer) error {
 func (UnimplementedResourcesServer) GetFile(*ResourceId, Resources_GetFileServer) error {
 	return status.Errorf(codes.Unimplemented, "method GetFile not implemented")
 }
+func (UnimplementedResourcesServer) GetFileVersions(context.Context, *ResourceId) (*FileVersions, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFileVersions not implemented")
+}
+func (UnimplementedResourcesServer) GetFileVersion(*FileVersionId, Resources_GetFileVersionServer) error {
+	return status.Errorf(codes.Unimplemented, "method GetFileVersion not implemented")
+}
+func (UnimplementedResourcesServer) UpdateLabels(context.Context, *LabelsUpdate) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateLabels not implemented")
+}
+func (UnimplementedResourcesServer) Batch(context.Context, *BatchRequest) (*BatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Batch not implemented")
+}
+func (UnimplementedResourcesServer) Usage(context.Context, *empty.Empty) (*UsageReport, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Usage not implemented")
+}
 func (UnimplementedResourcesServer) mustEmbedUnimplementedResourcesServer() {}
 
 // UnsafeResourcesServer may be embedded to opt out of forward compatibility for this service.
@@ -376,6 +474,99 @@ func (x *resourcesGetFileServer) Send(m *FileChunk) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _Resources_GetFileVersions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResourceId)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ResourcesServer).GetFileVersions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Resources_GetFileVersions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ResourcesServer).GetFileVersions(ctx, req.(*ResourceId))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Resources_GetFileVersion_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(FileVersionId)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ResourcesServer).GetFileVersion(m, &resourcesGetFileVersionServer{stream})
+}
+
+type Resources_GetFileVersionServer interface {
+	Send(*FileChunk) error
+	grpc.ServerStream
+}
+
+type resourcesGetFileVersionServer struct {
+	grpc.ServerStream
+}
+
+func (x *resourcesGetFileVersionServer) Send(m *FileChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Resources_UpdateLabels_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LabelsUpdate)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ResourcesServer).UpdateLabels(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Resources_UpdateLabels_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ResourcesServer).UpdateLabels(ctx, req.(*LabelsUpdate))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Resources_Batch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ResourcesServer).Batch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Resources_Batch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ResourcesServer).Batch(ctx, req.(*BatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Resources_Usage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ResourcesServer).Usage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Resources_Usage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ResourcesServer).Usage(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Resources_ServiceDesc is the grpc.ServiceDesc for Resources service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -399,6 +590,22 @@ var Resources_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Get",
 			Handler:    _Resources_Get_Handler,
 		},
+		{
+			MethodName: "GetFileVersions",
+			Handler:    _Resources_GetFileVersions_Handler,
+		},
+		{
+			MethodName: "UpdateLabels",
+			Handler:    _Resources_UpdateLabels_Handler,
+		},
+		{
+			MethodName: "Batch",
+			Handler:    _Resources_Batch_Handler,
+		},
+		{
+			MethodName: "Usage",
+			Handler:    _Resources_Usage_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -416,6 +623,11 @@ var Resources_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _Resources_GetFile_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "GetFileVersion",
+			Handler:       _Resources_GetFileVersion_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "resource.proto",
 }
@@ -0,0 +1,22 @@
+package services
+
+import (
+	"bytes"
+	"crypto/sha256"
+
+	"ydx-goadv-gophkeeper/pkg/model"
+)
+
+// Digest returns SHA-256 of the file chunk
+func Digest(data []byte) []byte {
+	digest := sha256.Sum256(data)
+	return digest[:]
+}
+
+// VerifyDigest returns ErrDigestMismatch if data does not match its digest
+func VerifyDigest(data []byte, digest []byte) error {
+	if !bytes.Equal(Digest(data), digest) {
+		return model.ErrDigestMismatch
+	}
+	return nil
+}
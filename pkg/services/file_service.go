@@ -85,6 +85,8 @@ func (fm *fileService) SaveFile(path string, chunks chan []byte) (chan error, er
 		return nil, errs.FileProcessingError{Err: err}
 	}
 	go func() {
+		// errCh is closed when the file is completely written
+		defer close(errCh)
 		writer := bufio.NewWriter(file)
 		defer file.Close()
 		defer writer.Flush()
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+type progressCtxKey struct{}
+
+// Progress counts transferred bytes of the file chunks, it is safe for concurrent use and nil Progress counts nothing
+type Progress struct {
+	total   atomic.Int64
+	done    atomic.Int64
+	started time.Time
+}
+
+// ProgressStats is the state of the transfer, Rate is in bytes per second and ETA is zero until the rate is known
+type ProgressStats struct {
+	Done    int64
+	Total   int64
+	Rate    float64
+	ETA     time.Duration
+	Elapsed time.Duration
+}
+
+func NewProgress() *Progress {
+	return &Progress{started: time.Now()}
+}
+
+// WithProgress returns the context which file transfers report progress to
+func WithProgress(ctx context.Context, progress *Progress) context.Context {
+	return context.WithValue(ctx, progressCtxKey{}, progress)
+}
+
+// ProgressFrom returns progress of the context or nil
+func ProgressFrom(ctx context.Context) *Progress {
+	progress, _ := ctx.Value(progressCtxKey{}).(*Progress)
+	return progress
+}
+
+// SetTotal sets the size of the transfer once it is known
+func (p *Progress) SetTotal(total int64) {
+	if p != nil {
+		p.total.Store(total)
+	}
+}
+
+// Add counts the transferred chunk
+func (p *Progress) Add(chunk []byte) {
+	if p != nil {
+		p.done.Add(int64(len(chunk)))
+	}
+}
+
+func (p *Progress) Stats() ProgressStats {
+	stats := ProgressStats{Done: p.done.Load(), Total: p.total.Load(), Elapsed: time.Since(p.started)}
+	if seconds := stats.Elapsed.Seconds(); seconds > 0 {
+		stats.Rate = float64(stats.Done) / seconds
+	}
+	if stats.Rate > 0 && stats.Total > stats.Done {
+		stats.ETA = time.Duration(float64(stats.Total-stats.Done) / stats.Rate * float64(time.Second))
+	}
+	return stats
+}
@@ -30,6 +30,8 @@ type ExitHandler interface {
 	AddFuncInProcessing(alias string)
 	FuncFinished(alias string)
 	ProperExitDefer() chan struct{}
+	// OnInterrupt routes SIGINT to the handler instead of exit until the returned func is called
+	OnInterrupt(handler func()) func()
 
 	ToCancel([]context.CancelFunc)
 	ToStop([]chan struct{})
@@ -48,6 +50,7 @@ type exitHandler struct {
 	toExecute         []func(ctx context.Context) error
 	funcsInProcessing sync.WaitGroup
 	newFuncAllowed    bool
+	onInterrupt       func()
 }
 
 func NewExitHandlerWithCtx(mainCtxCanceler context.CancelFunc) ExitHandler {
@@ -87,6 +90,23 @@ func (eh *exitHandler) setNewFuncExecutionAllowed(value bool) {
 	eh.newFuncAllowed = value
 }
 
+func (eh *exitHandler) OnInterrupt(handler func()) func() {
+	mu.Lock()
+	defer mu.Unlock()
+	eh.onInterrupt = handler
+	return func() {
+		mu.Lock()
+		defer mu.Unlock()
+		eh.onInterrupt = nil
+	}
+}
+
+func (eh *exitHandler) interruptHandler() func() {
+	mu.Lock()
+	defer mu.Unlock()
+	return eh.onInterrupt
+}
+
 func (eh *exitHandler) ShutdownHTTPServerBeforeExit(httpServer *http.Server) {
 	eh.httpServer = httpServer
 }
@@ -120,6 +140,15 @@ func (eh *exitHandler) ProperExitDefer() chan struct{} {
 	exit := make(chan struct{})
 	go func() {
 		s := <-signals
+		for s == syscall.SIGINT {
+			handler := eh.interruptHandler()
+			if handler == nil {
+				break
+			}
+			eh.log.Infof("Received a signal '%s', interrupting", s)
+			handler()
+			s = <-signals
+		}
 		eh.log.Infof("Received a signal '%s'", s)
 		exit <- struct{}{}
 		eh.setNewFuncExecutionAllowed(false)